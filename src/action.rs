@@ -9,6 +9,9 @@ pub enum Action {
     Suspend,
     Resume,
     Quit,
+    ConfirmQuit,   // Ask before quitting
+    QuitConfirmed, // Quit without checking the quit setting again
+    CancelQuit,    // Dismiss the quit confirmation
     ClearScreen,
     Error(String),
     Success(String),
@@ -21,7 +24,12 @@ pub enum Action {
     CreateNewExtension,
     EditExtension(String),   // Extension ID
     DeleteExtension(String), // Extension ID
+    DeleteUnusedExtensions,  // Delete every extension no profile uses
     RefreshExtensions,       // Reload extensions from storage
+    // Extension ID - open the extension's notes in $EDITOR
+    EditExtensionNotes(String),
+    // Extension ID, and each tested MCP server with how it fared
+    ServersTested(String, Vec<(String, Result<(), String>)>),
 
     // Navigation actions
     NavigateToExtensions,
@@ -37,11 +45,30 @@ pub enum Action {
     ConfirmDelete,             // Confirm deletion
     CancelDelete,              // Cancel deletion
     LaunchWithProfile(String), // Profile ID
-    RefreshProfiles,           // Reload profiles from storage
+    // Profile ID and skipped extension IDs - ask before launching without extensions
+    ConfirmEmptyLaunch(String, Vec<String>),
+    // Profile ID and skipped extension IDs - launch without further checks
+    LaunchConfirmed(String, Vec<String>),
+    // Profile ID and skipped extension IDs - ask before launching extensions
+    // not made for this OS
+    ConfirmPlatformLaunch(String, Vec<String>),
+    // Profile ID, and the extension IDs to skip for this launch only
+    LaunchWithout(String, Vec<String>),
+    CancelLaunch, // Dismiss the launch confirmation
+    // Profile ID - ask before pointing it at the extensions enabled by default
+    CaptureDefaults(String),
+    // Profile ID - point it at the extensions enabled by default
+    CaptureDefaultsConfirmed(String),
+    CancelCaptureDefaults,      // Dismiss the capture confirmation
+    RefreshProfiles,            // Reload profiles from storage
+    PreviewConfig(String),      // Profile ID - summarize what a launch sets up
+    EditProfileFile(String),    // Profile ID - open the stored file in $EDITOR
+    IconPicked(Option<String>), // Chosen icon, None for no icon
 
     // Settings actions
     ChangeTheme(String),              // Theme name
     UpdateKeybinding(String, String), // Action name, key combination
     ResetKeybindings,                 // Reset to defaults
+    SetCompactCards(bool),            // Card density for the lists
     SaveSettings,                     // Save settings to file
 }
@@ -1,4 +1,5 @@
 use std::sync::{Arc, RwLock};
+use std::time::{Duration, Instant};
 
 use color_eyre::Result;
 use crossterm::event::KeyEvent;
@@ -10,12 +11,14 @@ use crate::{
     action::Action,
     components::{
         Component,
-        settings_view::{SettingsManager, UserSettings},
+        settings_view::{QuitBehavior, SettingsManager, UserSettings},
     },
     config::Config,
-    storage::Storage,
+    icons::Icon,
+    storage::{Storage, data_dir_badge},
     tui::{Event, Tui},
     view::ViewManager,
+    watcher::{DirectorySource, RELOAD_DEBOUNCE, ReloadWatcher},
 };
 
 pub struct App {
@@ -29,32 +32,82 @@ pub struct App {
     storage: Storage,
     settings: Arc<RwLock<UserSettings>>,
     in_form_view: bool,
+    watchers: Vec<ReloadWatcher<DirectorySource>>, // Empty unless auto-reload is on
+    quit_guard: QuitGuard,
 }
 
 impl App {
     pub fn new() -> Result<Self> {
+        Self::with_storage(Storage::new()?)
+    }
+
+    /// Run against `storage` instead of the default data directory
+    pub fn with_storage(storage: Storage) -> Result<Self> {
         let (action_tx, action_rx) = mpsc::unbounded_channel();
 
         // Initialize storage
-        let storage = Storage::new()?;
         storage.init()?;
 
         // Load settings from disk into shared memory
         let settings_manager = SettingsManager::new()?;
         let settings = Arc::new(RwLock::new(settings_manager.get_settings().clone()));
 
-        // Apply saved theme from the loaded settings
-        if let Ok(settings_lock) = settings.read()
+        // Offer any themes the user has added, before the saved one is applied.
+        // A broken theme file is reported once the app is running.
+        if let Err(e) = crate::theme::load_custom_themes(&storage.data_dir().join("themes")) {
+            let _ = action_tx.send(Action::Error(e.to_string()));
+        }
+
+        // Apply saved theme from the loaded settings. A theme that no longer
+        // exists falls back to the default, so the settings view shows the
+        // theme actually in use.
+        if let Ok(mut settings_lock) = settings.write()
             && let Err(e) = crate::theme::set_theme_by_name(&settings_lock.theme)
         {
             debug!(
                 "Warning: Could not apply saved theme '{}': {}",
                 settings_lock.theme, e
             );
+            crate::theme::set_theme(crate::theme::Theme::default());
+            settings_lock.theme = UserSettings::default().theme;
         }
 
+        // Apply the saved icon style
+        if let Ok(settings_lock) = settings.read() {
+            crate::icons::set_ascii_only(settings_lock.no_emoji);
+        }
+
+        // Watch storage for changes made outside the app, if asked to
+        let auto_reload = settings.read().map(|s| s.auto_reload).unwrap_or(false);
+        let watchers = if auto_reload {
+            [
+                (storage.extensions_dir(), Action::RefreshExtensions),
+                (storage.profiles_dir(), Action::RefreshProfiles),
+            ]
+            .into_iter()
+            .map(|(dir, reload)| {
+                let source = DirectorySource::new(dir);
+                ReloadWatcher::new(source, reload, RELOAD_DEBOUNCE)
+            })
+            .collect()
+        } else {
+            Vec::new()
+        };
+
+        let quit_guard = settings
+            .read()
+            .map(|s| QuitGuard::new(s.quit_behavior, Duration::from_millis(s.quit_window_ms)))
+            .unwrap_or_else(|_| QuitGuard::new(QuitBehavior::Immediate, Duration::ZERO));
+
         // Create view manager with storage
-        let view_manager = ViewManager::with_storage(storage.clone());
+        let mut view_manager = ViewManager::with_storage(storage.clone());
+
+        // Keep a reminder on screen when this isn't the usual data directory
+        if let Ok(default_dir) = Storage::default_data_dir()
+            && let Some(name) = data_dir_badge(storage.data_dir(), &default_dir)
+        {
+            view_manager.add_status_segment(move || Ok(format!("data: {name}")));
+        }
 
         Ok(Self {
             components: vec![Box::new(view_manager)],
@@ -67,6 +120,8 @@ impl App {
             storage,
             settings,
             in_form_view: false,
+            watchers,
+            quit_guard,
         })
     }
 
@@ -113,42 +168,60 @@ impl App {
         let Some(event) = tui.next_event().await else {
             return Ok(());
         };
-        let action_tx = self.action_tx.clone();
+        self.handle_event(event)
+    }
+
+    /// Turn one terminal event into actions.
+    ///
+    /// A component and the global keymap can both answer the same key with
+    /// `Quit`; it is only sent once, so a single press never counts twice
+    /// towards double-press quitting.
+    pub fn handle_event(&mut self, event: Event) -> Result<()> {
+        let mut actions = Vec::new();
 
         // First, let components handle the event
         for component in self.components.iter_mut() {
             if let Some(action) = component.handle_events(Some(event.clone()))? {
-                action_tx.send(action)?;
+                actions.push(action);
             }
         }
 
         // Process system events (Tick, Render, Resize) always
         // But only process Key events if we're not in a form view
         match event {
-            Event::Tick => action_tx.send(Action::Tick)?,
-            Event::Render => action_tx.send(Action::Render)?,
-            Event::Resize(x, y) => action_tx.send(Action::Resize(x, y))?,
+            Event::Tick => actions.push(Action::Tick),
+            Event::Render => actions.push(Action::Render),
+            Event::Resize(x, y) => actions.push(Action::Resize(x, y)),
             Event::Key(key) => {
                 // Only process global keybindings if we're not in a form view
                 if !self.in_form_view {
-                    self.handle_key_event(key)?;
+                    actions.extend(self.handle_key_event(key));
                 }
             }
-            Event::Quit => action_tx.send(Action::Quit)?,
+            Event::Quit => actions.push(Action::Quit),
             _ => {}
         }
+
+        let mut quit_sent = false;
+        for action in actions {
+            if action == Action::Quit {
+                if quit_sent {
+                    continue;
+                }
+                quit_sent = true;
+            }
+            self.action_tx.send(action)?;
+        }
         Ok(())
     }
 
-    fn handle_key_event(&mut self, key: KeyEvent) -> Result<()> {
-        let action_tx = self.action_tx.clone();
-        let Some(keymap) = self.config.keybindings.get(&crate::config::Mode::Normal) else {
-            return Ok(());
-        };
+    /// The action the global keymap binds to `key`, if any
+    fn handle_key_event(&mut self, key: KeyEvent) -> Option<Action> {
+        let keymap = self.config.keybindings.get(&crate::config::Mode::Normal)?;
         match keymap.get(&vec![key]) {
             Some(action) => {
                 info!("Got action: {action:?}");
-                action_tx.send(action.clone())?;
+                Some(action.clone())
             }
             _ => {
                 // If the key was not handled as a single key action,
@@ -156,13 +229,21 @@ impl App {
                 self.last_tick_key_events.push(key);
 
                 // Check for multi-key combinations
-                if let Some(action) = keymap.get(&self.last_tick_key_events) {
-                    info!("Got action: {action:?}");
-                    action_tx.send(action.clone())?;
-                }
+                let action = keymap.get(&self.last_tick_key_events)?;
+                info!("Got action: {action:?}");
+                Some(action.clone())
             }
         }
-        Ok(())
+    }
+
+    /// Take the actions queued so far without handling them
+    #[allow(dead_code)]
+    pub fn take_pending_actions(&mut self) -> Vec<Action> {
+        let mut actions = Vec::new();
+        while let Ok(action) = self.action_rx.try_recv() {
+            actions.push(action);
+        }
+        actions
     }
 
     fn handle_actions(&mut self, tui: &mut Tui) -> Result<()> {
@@ -173,15 +254,40 @@ impl App {
             match action.clone() {
                 Action::Tick => {
                     self.last_tick_key_events.drain(..);
+                    let now = Instant::now();
+                    for watcher in &mut self.watchers {
+                        if let Some(reload) = watcher.poll(now) {
+                            self.action_tx.send(reload)?;
+                        }
+                    }
                 }
-                Action::Quit => self.should_quit = true,
+                Action::Quit => match self.quit_guard.request(Instant::now()) {
+                    QuitDecision::Quit => self.should_quit = true,
+                    QuitDecision::Confirm => self.action_tx.send(Action::ConfirmQuit)?,
+                    QuitDecision::PressAgain => self
+                        .action_tx
+                        .send(Action::Success("Press again to quit".to_string()))?,
+                },
+                Action::QuitConfirmed => self.should_quit = true,
                 Action::Suspend => self.should_suspend = true,
                 Action::Resume => self.should_suspend = false,
                 Action::ClearScreen => tui.terminal.clear()?,
                 Action::Resize(w, h) => self.handle_resize(tui, w, h)?,
                 Action::Render => self.render(tui)?,
                 Action::LaunchWithProfile(profile_id) => {
-                    self.handle_launch_profile(profile_id, tui)?;
+                    self.request_launch(profile_id, Vec::new(), tui)?;
+                }
+                Action::LaunchWithout(profile_id, skipped) => {
+                    self.request_launch(profile_id, skipped, tui)?;
+                }
+                Action::LaunchConfirmed(profile_id, skipped) => {
+                    self.handle_launch_profile(profile_id, &skipped, tui)?;
+                }
+                Action::EditProfileFile(profile_id) => {
+                    self.handle_edit_profile_file(profile_id, tui)?;
+                }
+                Action::EditExtensionNotes(extension_id) => {
+                    self.handle_edit_extension_notes(extension_id, tui)?;
                 }
                 // Track when we're in form views
                 Action::CreateNewExtension
@@ -235,25 +341,177 @@ impl App {
         Ok(())
     }
 
-    fn handle_launch_profile(&mut self, profile_id: String, tui: &mut Tui) -> Result<()> {
+    fn handle_edit_profile_file(&mut self, profile_id: String, tui: &mut Tui) -> Result<()> {
+        use crate::utils::editor::{edit_file, scratch_file};
+
+        // Edit a scratch copy so a broken edit never replaces the stored profile
+        let stored = self.storage.profile_file(&profile_id);
+        let extension = stored
+            .extension()
+            .and_then(|s| s.to_str())
+            .unwrap_or("json");
+        let scratch = std::fs::read(&stored)
+            .map_err(Into::into)
+            .and_then(|contents| {
+                scratch_file("gemini-profile-", &format!(".{extension}"), &contents)
+            });
+        let scratch = match scratch {
+            Ok(scratch) => scratch,
+            Err(e) => {
+                self.action_tx
+                    .send(Action::Error(format!("Failed to open profile: {e}")))?;
+                return Ok(());
+            }
+        };
+
+        // Hand the terminal to the editor, then take it back
+        tui.exit()?;
+        let edited = edit_file(scratch.path());
+        tui.enter()?;
+        self.action_tx.send(Action::ClearScreen)?;
+
+        if let Err(e) = edited {
+            self.action_tx
+                .send(Action::Error(format!("Failed to edit profile: {e}")))?;
+            return Ok(());
+        }
+
+        match self
+            .storage
+            .apply_edited_profile(&profile_id, scratch.path())
+        {
+            Ok(profile) => {
+                self.action_tx.send(Action::Success(format!(
+                    "Profile '{}' updated",
+                    profile.name
+                )))?;
+                self.action_tx.send(Action::RefreshProfiles)?;
+            }
+            Err(e) => {
+                self.action_tx.send(Action::Error(format!(
+                    "Profile not saved: {e}{}",
+                    kept(scratch)
+                )))?;
+            }
+        }
+        self.action_tx.send(Action::Render)?;
+
+        Ok(())
+    }
+
+    /// Open an extension's notes in the user's editor, starting from the
+    /// current notes, and store whatever is left when the editor exits
+    fn handle_edit_extension_notes(&mut self, extension_id: String, tui: &mut Tui) -> Result<()> {
+        use crate::utils::editor::{edit_file, scratch_file};
+
+        let current = self
+            .storage
+            .load_extension_notes(&extension_id)
+            .unwrap_or_default()
+            .unwrap_or_default();
+        let scratch = match scratch_file("gemini-notes-", ".md", current.as_bytes()) {
+            Ok(scratch) => scratch,
+            Err(e) => {
+                self.action_tx
+                    .send(Action::Error(format!("Failed to open notes: {e}")))?;
+                return Ok(());
+            }
+        };
+
+        // Hand the terminal to the editor, then take it back
+        tui.exit()?;
+        let edited = edit_file(scratch.path());
+        tui.enter()?;
+        self.action_tx.send(Action::ClearScreen)?;
+
+        let saved = edited.and_then(|()| {
+            let notes = std::fs::read_to_string(scratch.path())?;
+            self.storage.save_extension_notes(&extension_id, &notes)
+        });
+        match saved {
+            Ok(()) => {
+                self.action_tx
+                    .send(Action::Success("Notes saved".to_string()))?;
+                self.action_tx.send(Action::RefreshExtensions)?;
+            }
+            Err(e) => {
+                self.action_tx.send(Action::Error(format!(
+                    "Notes not saved: {e}{}",
+                    kept(scratch)
+                )))?;
+            }
+        }
+        self.action_tx.send(Action::Render)?;
+        Ok(())
+    }
+
+    /// Launch a profile without the `skipped` extensions, asking first when
+    /// [`launch_confirmation`] says the launch needs a second look
+    fn request_launch(
+        &mut self,
+        profile_id: String,
+        skipped: Vec<String>,
+        tui: &mut Tui,
+    ) -> Result<()> {
+        let confirm_empty = self
+            .settings
+            .read()
+            .map(|s| s.confirm_empty_launch)
+            .unwrap_or(true);
+
+        if let Some(confirm) =
+            launch_confirmation(&self.storage, &profile_id, &skipped, confirm_empty)
+        {
+            self.action_tx.send(confirm)?;
+            Ok(())
+        } else {
+            self.handle_launch_profile(profile_id, &skipped, tui)
+        }
+    }
+
+    /// Launch a stored profile, leaving out the `skipped` extensions for
+    /// this launch only
+    fn handle_launch_profile(
+        &mut self,
+        profile_id: String,
+        skipped: &[String],
+        tui: &mut Tui,
+    ) -> Result<()> {
         use crate::launcher::Launcher;
 
-        // Get the profile from storage
-        match self.storage.load_profile(&profile_id) {
+        // Get the profile from storage, with what it inherits merged in
+        match self.storage.resolve_profile(&profile_id) {
             Ok(profile) => {
                 // Exit TUI mode before launching
                 tui.exit()?;
 
                 // Display launch message
                 println!("Preparing to launch profile: {}", profile.display_name());
+                if !skipped.is_empty() {
+                    println!("Skipping for this launch: {}", skipped.join(", "));
+                }
                 println!();
+                let profile = profile.without_extensions(skipped);
 
-                // Launch the profile with storage
-                let launcher = Launcher::with_storage(self.storage.clone());
+                // Launch the profile with storage and the configured Gemini
+                // CLI and extensions directory
+                let (gemini_cli, extensions_dir) = self
+                    .settings
+                    .read()
+                    .map(|settings| {
+                        (
+                            settings.gemini_cli_path.clone(),
+                            settings.gemini_extensions_dir.clone(),
+                        )
+                    })
+                    .unwrap_or_default();
+                let launcher = Launcher::with_storage(self.storage.clone())
+                    .with_gemini_cli(gemini_cli.as_deref())
+                    .with_extensions_dir(extensions_dir.as_deref());
                 match launcher.launch_with_profile(&profile) {
                     Ok(_) => {
                         println!();
-                        println!("✅ Gemini CLI session ended successfully.");
+                        println!("{} Gemini CLI session ended successfully.", Icon::Done);
 
                         // Small delay to let the user see the message
                         std::thread::sleep(std::time::Duration::from_millis(500));
@@ -264,7 +522,7 @@ impl App {
                         self.action_tx.send(Action::Render)?;
                     }
                     Err(e) => {
-                        eprintln!("❌ Error launching profile: {e}");
+                        eprintln!("{} Error launching profile: {e}", Icon::Failed);
 
                         // Longer delay for errors so user can read the message
                         std::thread::sleep(std::time::Duration::from_secs(2));
@@ -289,3 +547,101 @@ impl App {
         Ok(())
     }
 }
+
+/// What to do with a quit request
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum QuitDecision {
+    Quit,
+    Confirm,
+    PressAgain, // Wait for a second press
+}
+
+/// Applies the user's quit setting to each quit request
+pub struct QuitGuard {
+    behavior: QuitBehavior,
+    window: Duration,
+    armed_at: Option<Instant>, // First press of a double press, awaiting the second
+}
+
+impl QuitGuard {
+    pub fn new(behavior: QuitBehavior, window: Duration) -> Self {
+        Self {
+            behavior,
+            window,
+            armed_at: None,
+        }
+    }
+
+    /// Decide what a quit request made at `now` does. With double-press
+    /// quitting, a press that comes too late starts a new wait.
+    pub fn request(&mut self, now: Instant) -> QuitDecision {
+        match self.behavior {
+            QuitBehavior::Immediate => QuitDecision::Quit,
+            QuitBehavior::Confirm => QuitDecision::Confirm,
+            QuitBehavior::DoublePress => {
+                if let Some(armed_at) = self.armed_at.take()
+                    && now.duration_since(armed_at) <= self.window
+                {
+                    return QuitDecision::Quit;
+                }
+                self.armed_at = Some(now);
+                QuitDecision::PressAgain
+            }
+        }
+    }
+}
+
+/// Decide whether launching a profile without the `skipped` extensions needs
+/// confirmation first.
+///
+/// Returns the action that asks the user to confirm, or `None` to launch straight
+/// away. A profile with malformed environment values gets an error instead, before
+/// anything is installed. Profiles that fail to load are passed through so the
+/// launch reports the error.
+pub fn launch_confirmation(
+    storage: &Storage,
+    profile_id: &str,
+    skipped: &[String],
+    confirm_empty: bool,
+) -> Option<Action> {
+    use crate::{
+        launcher::{Launcher, check_platforms},
+        models::extension::current_platform,
+    };
+
+    let profile = storage
+        .resolve_profile(profile_id)
+        .ok()?
+        .without_extensions(skipped);
+    let problems = profile.environment_problems();
+    if !problems.is_empty() {
+        return Some(Action::Error(format!(
+            "Can't launch '{}': {}",
+            profile.name,
+            problems.join("; ")
+        )));
+    }
+
+    let launcher = Launcher::with_storage(storage.clone());
+    let enabled = launcher.enabled_extensions(&profile);
+
+    // Extensions made for another OS are always worth a second look
+    if !check_platforms(&enabled, current_platform()).is_empty() {
+        return Some(Action::ConfirmPlatformLaunch(
+            profile_id.to_string(),
+            skipped.to_vec(),
+        ));
+    }
+
+    (confirm_empty && enabled.is_empty())
+        .then(|| Action::ConfirmEmptyLaunch(profile_id.to_string(), skipped.to_vec()))
+}
+
+/// Keep a scratch file the user's edits could not be saved from, and say
+/// where it is so the edits are not lost
+fn kept(scratch: tempfile::NamedTempFile) -> String {
+    match scratch.keep() {
+        Ok((_, path)) => format!(". Your edits are in {}", path.display()),
+        Err(_) => String::new(),
+    }
+}
@@ -1,6 +1,11 @@
-use clap::Parser;
+use std::path::PathBuf;
 
-use crate::config::{get_config_dir, get_data_dir};
+use clap::{Parser, Subcommand};
+
+use crate::{
+    config::{get_config_dir, get_data_dir},
+    storage::ConflictResolution,
+};
 
 #[derive(Parser, Debug)]
 #[command(author, version = version(), about)]
@@ -8,6 +13,59 @@ pub struct Cli {
     /// List stored profiles and extensions
     #[arg(long)]
     pub list_storage: bool,
+
+    /// Enable debug logging
+    #[arg(long)]
+    pub debug: bool,
+
+    /// Write logs to this file instead of the data directory
+    #[arg(long, value_name = "PATH")]
+    pub log_file: Option<PathBuf>,
+
+    /// Use this data directory instead of the default, e.g. one made by `init`
+    #[arg(long, value_name = "DIR")]
+    pub data_dir: Option<PathBuf>,
+
+    #[command(subcommand)]
+    pub command: Option<Command>,
+}
+
+#[derive(Subcommand, Debug)]
+pub enum Command {
+    /// Create a new data directory from the profiles and extensions of an existing one
+    Init {
+        /// Data directory to copy from
+        #[arg(long, value_name = "DIR")]
+        from: PathBuf,
+
+        /// New data directory to create
+        #[arg(long, value_name = "DIR")]
+        to: PathBuf,
+    },
+    /// Write a profile to a file that can be shared and imported elsewhere
+    ExportProfile {
+        /// ID of the profile to export
+        id: String,
+
+        /// File to write
+        #[arg(long, value_name = "FILE")]
+        to: PathBuf,
+    },
+    /// Import a profile from a file written by `export-profile`
+    ImportProfile {
+        /// File to read
+        file: PathBuf,
+
+        /// What to do if a profile with the same ID already exists
+        #[arg(long, value_enum, value_name = "ACTION", default_value_t = ConflictResolution::Rename)]
+        on_conflict: ConflictResolution,
+    },
+    /// Print the effective keybindings, followed by the keys that can't be changed
+    Keys {
+        /// Print a markdown table for documentation
+        #[arg(long)]
+        markdown: bool,
+    },
 }
 
 const VERSION_MESSAGE: &str = concat!(
@@ -11,11 +11,16 @@ use tokio::sync::mpsc::UnboundedSender;
 use self::settings_view::UserSettings;
 use crate::{action::Action, config::Config, tui::Event};
 
+pub mod badge;
+pub mod config_preview;
 pub mod confirm_dialog;
 pub mod extension_detail;
 pub mod extension_form;
 pub mod extension_list;
+pub mod highlight;
+pub mod icon_picker;
 pub mod import_dialog;
+pub mod list_scroll;
 pub mod profile_detail;
 pub mod profile_form;
 pub mod profile_list;
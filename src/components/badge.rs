@@ -0,0 +1,110 @@
+use ratatui::prelude::*;
+
+use crate::theme;
+
+/// What a badge reports, which decides its colour
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq)]
+pub enum BadgeKind {
+    /// Something is on or healthy, e.g. "active"
+    Success,
+    /// Worth a look before going on, e.g. "modified"
+    Warning,
+    /// Something is broken or missing
+    Error,
+    /// A neutral fact that should stand out, e.g. "default"
+    Info,
+    /// Counts and other background detail
+    #[default]
+    Neutral,
+}
+
+impl BadgeKind {
+    /// Style for a badge of this kind in the current theme
+    pub fn style(self) -> Style {
+        let color = match self {
+            BadgeKind::Success => theme::success(),
+            BadgeKind::Warning => theme::warning(),
+            BadgeKind::Error => theme::error(),
+            BadgeKind::Info => theme::info(),
+            BadgeKind::Neutral => theme::text_secondary(),
+        };
+        Style::default().fg(color)
+    }
+}
+
+/// A short status label or count shown beside a name, styled by its kind so
+/// cards, details and the status bar agree on what each colour means
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub struct Badge {
+    label: String,
+    kind: BadgeKind,
+}
+
+impl Badge {
+    pub fn new(label: impl Into<String>, kind: BadgeKind) -> Self {
+        Self {
+            label: label.into(),
+            kind,
+        }
+    }
+
+    pub fn success(label: impl Into<String>) -> Self {
+        Self::new(label, BadgeKind::Success)
+    }
+
+    pub fn warning(label: impl Into<String>) -> Self {
+        Self::new(label, BadgeKind::Warning)
+    }
+
+    #[allow(dead_code)]
+    pub fn error(label: impl Into<String>) -> Self {
+        Self::new(label, BadgeKind::Error)
+    }
+
+    pub fn info(label: impl Into<String>) -> Self {
+        Self::new(label, BadgeKind::Info)
+    }
+
+    pub fn neutral(label: impl Into<String>) -> Self {
+        Self::new(label, BadgeKind::Neutral)
+    }
+
+    #[allow(dead_code)]
+    pub fn kind(&self) -> BadgeKind {
+        self.kind
+    }
+
+    /// The badge as a span, ready to add to a line
+    pub fn span(&self) -> Span<'static> {
+        Span::styled(self.label.clone(), self.kind.style())
+    }
+}
+
+/// Tags shown one after another, each styled as a badge of the same kind
+pub struct ChipRow {
+    chips: Vec<Badge>,
+}
+
+impl ChipRow {
+    pub fn new(tags: &[String], kind: BadgeKind) -> Self {
+        Self {
+            chips: tags
+                .iter()
+                .map(|tag| Badge::new(tag.clone(), kind))
+                .collect(),
+        }
+    }
+
+    /// The chips as spans, separated by muted commas
+    pub fn spans(&self) -> Vec<Span<'static>> {
+        let separator = Span::styled(", ", Style::default().fg(theme::text_muted()));
+        let mut spans = Vec::with_capacity(self.chips.len() * 2);
+        for (i, chip) in self.chips.iter().enumerate() {
+            if i > 0 {
+                spans.push(separator.clone());
+            }
+            spans.push(chip.span());
+        }
+        spans
+    }
+}
@@ -0,0 +1,184 @@
+use color_eyre::Result;
+use ratatui::{prelude::*, widgets::*};
+
+use super::Component;
+use crate::{action::Action, theme};
+
+/// Read-only summary of what a profile launch would set up.
+///
+/// The summary is not a file Gemini reads: the extensions are installed as
+/// their own `gemini-extension.json` files and the variables are passed in
+/// the environment. When a summary was recorded for the most recent launch,
+/// Tab switches to it, so that launch can be compared with one made now.
+pub struct ConfigPreview {
+    profile_name: String,
+    config: String,
+    last_launch: Option<String>,
+    showing_last_launch: bool,
+    scroll_offset: u16,
+}
+
+impl ConfigPreview {
+    pub fn new(profile_name: &str, config: String) -> Self {
+        Self {
+            profile_name: profile_name.to_string(),
+            config,
+            last_launch: None,
+            showing_last_launch: false,
+            scroll_offset: 0,
+        }
+    }
+
+    /// Offer the summary recorded for the most recent launch alongside this one
+    pub fn with_last_launch(mut self, config: Option<String>) -> Self {
+        self.last_launch = config;
+        self
+    }
+
+    /// The serialized summary being shown
+    #[allow(dead_code)]
+    pub fn config(&self) -> &str {
+        match &self.last_launch {
+            Some(last) if self.showing_last_launch => last,
+            _ => &self.config,
+        }
+    }
+
+    /// Whether the last launch's summary is shown instead of the profile's
+    #[allow(dead_code)]
+    pub fn is_showing_last_launch(&self) -> bool {
+        self.showing_last_launch
+    }
+
+    fn max_scroll(&self) -> u16 {
+        self.config().lines().count().saturating_sub(1) as u16
+    }
+}
+
+/// Colour one line of pretty-printed JSON: keys, strings, and other values
+/// (numbers, booleans, null) each get their own colour
+fn highlight_json_line(line: &str) -> Line<'_> {
+    let punctuation = Style::default().fg(theme::text_muted());
+    let mut spans = Vec::new();
+    let mut rest = line;
+
+    while !rest.is_empty() {
+        if let Some(after_quote) = rest.strip_prefix('"') {
+            // Find the closing quote, skipping escaped ones
+            let mut end = after_quote.len();
+            let mut escaped = false;
+            for (i, c) in after_quote.char_indices() {
+                match c {
+                    '\\' if !escaped => escaped = true,
+                    '"' if !escaped => {
+                        end = i;
+                        break;
+                    }
+                    _ => escaped = false,
+                }
+            }
+            let len = (end + 2).min(rest.len());
+            let is_key = rest[len..].trim_start().starts_with(':');
+            let color = if is_key {
+                theme::primary()
+            } else {
+                theme::success()
+            };
+            spans.push(Span::styled(&rest[..len], Style::default().fg(color)));
+            rest = &rest[len..];
+        } else {
+            let len = rest
+                .find(|c: char| c == '"' || "{}[]:,".contains(c))
+                .map_or(rest.len(), |i| i.max(1));
+            let token = &rest[..len];
+            let style = if token.trim().is_empty() || "{}[]:,".contains(token) {
+                punctuation
+            } else {
+                Style::default().fg(theme::warning())
+            };
+            spans.push(Span::styled(token, style));
+            rest = &rest[len..];
+        }
+    }
+
+    Line::from(spans)
+}
+
+impl Component for ConfigPreview {
+    fn draw(&mut self, frame: &mut Frame, area: Rect) -> Result<()> {
+        let chunks = Layout::default()
+            .direction(Direction::Vertical)
+            .constraints([Constraint::Min(0), Constraint::Length(3)])
+            .split(area);
+
+        let title = if self.showing_last_launch {
+            " Last Launch Summary ".to_string()
+        } else {
+            format!(" Launch Summary · {} ", self.profile_name)
+        };
+        let block = Block::default()
+            .title(title)
+            .borders(Borders::ALL)
+            .border_type(BorderType::Rounded)
+            .border_style(Style::default().fg(theme::primary()));
+
+        let lines: Vec<Line> = self.config().lines().map(highlight_json_line).collect();
+        let paragraph = Paragraph::new(lines)
+            .style(Style::default().fg(theme::text_primary()))
+            .block(block)
+            .scroll((self.scroll_offset, 0));
+        frame.render_widget(paragraph, chunks[0]);
+
+        use crate::utils::build_help_text;
+        let mut actions = vec![("up", "Scroll"), ("down", "Scroll")];
+        if self.last_launch.is_some() {
+            actions.push((
+                "tab",
+                if self.showing_last_launch {
+                    "This profile"
+                } else {
+                    "Last launch"
+                },
+            ));
+        }
+        actions.extend([("back", "Back"), ("quit", "Quit")]);
+        let help_text = build_help_text(&actions);
+        let help_bar = Paragraph::new(help_text)
+            .style(Style::default().fg(theme::text_muted()))
+            .alignment(Alignment::Center)
+            .block(
+                Block::default()
+                    .borders(Borders::ALL)
+                    .border_type(BorderType::Rounded),
+            );
+        frame.render_widget(help_bar, chunks[1]);
+
+        Ok(())
+    }
+
+    fn handle_events(&mut self, event: Option<crate::tui::Event>) -> Result<Option<Action>> {
+        use crossterm::event::KeyCode;
+
+        match event {
+            Some(crate::tui::Event::Key(key)) => match key.code {
+                KeyCode::Up | KeyCode::Char('k') => {
+                    self.scroll_offset = self.scroll_offset.saturating_sub(1);
+                    Ok(Some(Action::Render))
+                }
+                KeyCode::Down | KeyCode::Char('j') => {
+                    self.scroll_offset = (self.scroll_offset + 1).min(self.max_scroll());
+                    Ok(Some(Action::Render))
+                }
+                KeyCode::Tab if self.last_launch.is_some() => {
+                    self.showing_last_launch = !self.showing_last_launch;
+                    self.scroll_offset = 0;
+                    Ok(Some(Action::Render))
+                }
+                KeyCode::Char('b') | KeyCode::Esc => Ok(Some(Action::NavigateBack)),
+                KeyCode::Char('q') => Ok(Some(Action::Quit)),
+                _ => Ok(None),
+            },
+            _ => Ok(None),
+        }
+    }
+}
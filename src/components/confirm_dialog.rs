@@ -3,7 +3,7 @@ use ratatui::{prelude::*, widgets::*};
 use tokio::sync::mpsc::UnboundedSender;
 
 use super::Component;
-use crate::{action::Action, config::Config, theme};
+use crate::{action::Action, config::Config, icons::Icon, theme};
 
 pub struct ConfirmDialog {
     command_tx: Option<UnboundedSender<Action>>,
@@ -49,8 +49,8 @@ impl Component for ConfirmDialog {
 
     fn draw(&mut self, frame: &mut Frame, area: Rect) -> Result<()> {
         // Calculate dialog size
-        let dialog_width = 60.min(area.width - 4);
-        let dialog_height = 10.min(area.height - 4);
+        let dialog_width = 60.min(area.width.saturating_sub(4));
+        let dialog_height = 10.min(area.height.saturating_sub(4));
 
         // Center the dialog
         let x = (area.width.saturating_sub(dialog_width)) / 2;
@@ -95,7 +95,7 @@ impl Component for ConfirmDialog {
             .split(inner);
 
         // Render message with warning icon
-        let message_text = format!("⚠ {}", self.message);
+        let message_text = format!("{} {}", Icon::Warning, self.message);
         let message = Paragraph::new(message_text)
             .wrap(Wrap { trim: true })
             .alignment(Alignment::Center)
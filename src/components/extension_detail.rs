@@ -1,9 +1,37 @@
+use std::collections::HashMap;
+use std::path::PathBuf;
+
 use color_eyre::Result;
 use ratatui::{prelude::*, widgets::*};
 use tokio::sync::mpsc::UnboundedSender;
 
-use super::Component;
-use crate::{action::Action, config::Config, models::Extension, storage::Storage, theme};
+use super::{
+    Component,
+    badge::{Badge, BadgeKind, ChipRow},
+};
+use crate::{
+    action::Action,
+    config::Config,
+    icons::Icon,
+    launcher::{SMOKE_TEST_WAIT, extension_dir, smoke_test_server, source_dir},
+    models::{
+        Extension,
+        extension::{McpServerConfig, current_platform, sorted_entries},
+    },
+    storage::Storage,
+    theme,
+    utils::{
+        clipboard::{Clipboard, Osc52Clipboard},
+        file_tree::{TreeEntry, build_tree, format_size},
+        humanize_since, truncate_to_width,
+    },
+};
+
+/// How many MCP server args to list before collapsing the rest
+const MAX_VISIBLE_ARGS: usize = 5;
+
+/// How many files the file tree lists before stopping
+const MAX_TREE_ENTRIES: usize = 1000;
 
 #[derive(Default)]
 pub struct ExtensionDetail {
@@ -11,7 +39,12 @@ pub struct ExtensionDetail {
     config: Config,
     storage: Option<Storage>,
     extension: Option<Extension>,
+    notes: Option<String>, // The user's own notes, stored beside the extension
     scroll_offset: u16,
+    clipboard: Option<Box<dyn Clipboard>>, // Defaults to OSC 52 when unset
+    file_tree: Option<(PathBuf, Vec<TreeEntry>)>, // Shown instead of the details while open
+    server_checks: HashMap<String, Result<(), String>>, // Last `t` result per server
+    testing_servers: bool,                 // A `t` test is running in the background
 }
 
 impl ExtensionDetail {
@@ -32,8 +65,22 @@ impl ExtensionDetail {
     }
 
     pub fn set_extension(&mut self, extension: Extension) {
+        self.notes = self
+            .storage
+            .as_ref()
+            .and_then(|storage| storage.load_extension_notes(&extension.id).ok())
+            .flatten();
         self.extension = Some(extension);
         self.scroll_offset = 0; // Reset scroll when setting new extension
+        self.file_tree = None;
+        self.server_checks.clear();
+        self.testing_servers = false;
+    }
+
+    /// How each MCP server fared the last time they were tested, by name
+    #[allow(dead_code)]
+    pub fn server_checks(&self) -> &HashMap<String, Result<(), String>> {
+        &self.server_checks
     }
 
     fn scroll_up(&mut self) {
@@ -46,6 +93,160 @@ impl ExtensionDetail {
         // We'll calculate max scroll based on content height in draw
         self.scroll_offset = self.scroll_offset.saturating_add(1);
     }
+
+    /// Override the clipboard used for copy actions
+    #[allow(dead_code)]
+    pub fn set_clipboard(&mut self, clipboard: Box<dyn Clipboard>) {
+        self.clipboard = Some(clipboard);
+    }
+
+    /// Copy a hint teammates can use to install this extension
+    fn copy_install_hint(&mut self) -> Option<Action> {
+        let hint = self.extension.as_ref()?.install_hint();
+
+        let result = match &mut self.clipboard {
+            Some(clipboard) => clipboard.set_text(&hint),
+            None => Osc52Clipboard.set_text(&hint),
+        };
+
+        Some(match result {
+            Ok(()) => Action::Success("Install hint copied to clipboard".to_string()),
+            Err(e) => Action::Error(format!("Failed to copy install hint: {e}")),
+        })
+    }
+
+    /// Show or hide the files in the extension's source directory. The
+    /// directory is read each time the tree is opened, never before.
+    fn toggle_file_tree(&mut self) -> Option<Action> {
+        self.scroll_offset = 0;
+        if self.file_tree.take().is_some() {
+            return Some(Action::Render);
+        }
+
+        let extension = self.extension.as_ref()?;
+        let Some(dir) = source_dir(extension) else {
+            return Some(Action::Error(format!(
+                "'{}' has no source directory to show",
+                extension.name
+            )));
+        };
+        match build_tree(&dir, MAX_TREE_ENTRIES) {
+            Ok(entries) => {
+                self.file_tree = Some((dir, entries));
+                Some(Action::Render)
+            }
+            Err(e) => Some(Action::Error(format!(
+                "Failed to read {}: {e}",
+                dir.display()
+            ))),
+        }
+    }
+
+    /// Lines of the file tree view
+    fn file_tree_lines(entries: &[TreeEntry]) -> Vec<Line<'_>> {
+        if entries.is_empty() {
+            return vec![Line::from(Span::styled(
+                "(empty directory)",
+                Style::default().fg(theme::text_muted()),
+            ))];
+        }
+
+        let mut lines: Vec<Line> = entries
+            .iter()
+            .map(|entry| {
+                let indent = "  ".repeat(entry.depth);
+                match entry.size {
+                    None => Line::from(Span::styled(
+                        format!("{indent}{}/", entry.name),
+                        Style::default().fg(theme::highlight()),
+                    )),
+                    Some(size) => Line::from(vec![
+                        Span::styled(
+                            format!("{indent}{}", entry.name),
+                            Style::default().fg(theme::text_primary()),
+                        ),
+                        Span::styled(
+                            format!("  {}", format_size(size)),
+                            Style::default().fg(theme::text_muted()),
+                        ),
+                    ]),
+                }
+            })
+            .collect();
+        if entries.len() >= MAX_TREE_ENTRIES {
+            lines.push(Line::from(Span::styled(
+                format!("(showing the first {MAX_TREE_ENTRIES} entries)"),
+                Style::default().fg(theme::text_muted()),
+            )));
+        }
+        lines
+    }
+
+    /// Start each command-based MCP server briefly to check it comes up.
+    ///
+    /// The servers are tried on a background thread, since each takes a
+    /// moment; the results come back as [`Action::ServersTested`].
+    fn test_servers(&mut self) -> Option<Action> {
+        if self.testing_servers {
+            return None;
+        }
+        let extension = self.extension.as_ref()?;
+        let tx = self.command_tx.clone()?;
+        let servers: Vec<(String, McpServerConfig)> = extension
+            .sorted_mcp_servers()
+            .into_iter()
+            .filter(|(_, server)| server.command.is_some())
+            .map(|(name, server)| (name.clone(), server.clone()))
+            .collect();
+        if servers.is_empty() {
+            return Some(Action::Error(
+                "No command-based MCP servers to test".to_string(),
+            ));
+        }
+
+        let id = extension.id.clone();
+        let dir = extension_dir(extension);
+        let count = servers.len();
+        std::thread::spawn(move || {
+            let checks = servers
+                .into_iter()
+                .map(|(name, server)| {
+                    let result = smoke_test_server(&server, &dir, SMOKE_TEST_WAIT);
+                    (name, result.map_err(|e| e.to_string()))
+                })
+                .collect();
+            let _ = tx.send(Action::ServersTested(id, checks));
+        });
+
+        self.testing_servers = true;
+        Some(Action::Success(format!("Testing {count} MCP server(s)...")))
+    }
+
+    /// Keep the results of a finished server test and sum them up
+    fn finish_server_test(&mut self, checks: Vec<(String, Result<(), String>)>) -> Action {
+        let failed: Vec<&str> = checks
+            .iter()
+            .filter(|(_, result)| result.is_err())
+            .map(|(name, _)| name.as_str())
+            .collect();
+        let action = if failed.is_empty() {
+            Action::Success(format!(
+                "{} MCP server(s) started successfully",
+                checks.len()
+            ))
+        } else {
+            Action::Error(format!(
+                "{} of {} MCP server(s) failed: {}",
+                failed.len(),
+                checks.len(),
+                failed.join(", ")
+            ))
+        };
+
+        self.server_checks = checks.into_iter().collect();
+        self.testing_servers = false;
+        action
+    }
 }
 
 impl Component for ExtensionDetail {
@@ -60,12 +261,35 @@ impl Component for ExtensionDetail {
     }
 
     fn update(&mut self, action: Action) -> Result<Option<Action>> {
-        if let Action::ViewExtensionDetails(id) = action {
-            // Load the extension from storage
-            if let Some(storage) = &self.storage
-                && let Ok(extension) = storage.load_extension(&id)
-            {
-                self.set_extension(extension);
+        let id = match action {
+            Action::ServersTested(id, checks) => {
+                // Results for an extension no longer shown are dropped
+                let shown = self.extension.as_ref().is_some_and(|e| e.id == id);
+                return Ok(shown.then(|| self.finish_server_test(checks)));
+            }
+            Action::ViewExtensionDetails(id) => id,
+            // Pick up edits to the extension shown, such as its notes
+            Action::RefreshExtensions => match &self.extension {
+                Some(extension) => extension.id.clone(),
+                None => return Ok(None),
+            },
+            _ => return Ok(None),
+        };
+
+        // Load the extension from storage
+        if let Some(storage) = &self.storage
+            && let Ok(extension) = storage.load_extension(&id)
+        {
+            let scroll_offset = self.scroll_offset;
+            let file_tree = self.file_tree.take();
+            let server_checks = std::mem::take(&mut self.server_checks);
+            let same = self.extension.as_ref().is_some_and(|e| e.id == id);
+            self.set_extension(extension);
+            if same {
+                // A refresh keeps the reader's place
+                self.scroll_offset = scroll_offset;
+                self.file_tree = file_tree;
+                self.server_checks = server_checks;
             }
         }
         Ok(None)
@@ -98,13 +322,44 @@ impl Component for ExtensionDetail {
 
         // Main content block
         let block = Block::default()
-            .title(format!(" {} v{} ", extension.name, extension.version))
+            .title(match extension.display_icon() {
+                Some(icon) => format!(" {icon} {} v{} ", extension.name, extension.version),
+                None => format!(" {} v{} ", extension.name, extension.version),
+            })
             .borders(Borders::ALL)
             .border_type(BorderType::Rounded)
             .border_style(Style::default().fg(theme::text_secondary()));
 
         let inner_area = block.inner(chunks[0]);
 
+        if let Some((dir, entries)) = &self.file_tree {
+            let block = block.title_bottom(format!(" {} ", dir.display()));
+            let paragraph =
+                Paragraph::new(Self::file_tree_lines(entries)).scroll((self.scroll_offset, 0));
+            frame.render_widget(block, chunks[0]);
+            frame.render_widget(paragraph, inner_area);
+
+            use crate::utils::build_help_text;
+            let help_text = build_help_text(&[
+                ("up", "Scroll"),
+                ("down", "Scroll"),
+                ("f", "Details"),
+                ("back", "Details"),
+                ("quit", "Quit"),
+            ]);
+            let help_bar = Paragraph::new(help_text)
+                .style(Style::default().fg(theme::text_muted()))
+                .alignment(Alignment::Center)
+                .block(
+                    Block::default()
+                        .borders(Borders::ALL)
+                        .border_type(BorderType::Rounded)
+                        .border_style(Style::default().fg(theme::text_secondary())),
+                );
+            frame.render_widget(help_bar, chunks[1]);
+            return Ok(());
+        }
+
         // Build content
         let mut content = vec![];
 
@@ -134,20 +389,60 @@ impl Component for ExtensionDetail {
         ]));
         content.push(Line::from(""));
 
-        // Tags
-        if !extension.metadata.tags.is_empty() {
+        // Gemini CLI requirement
+        if let Some(min) = &extension.min_gemini_version {
+            content.push(Line::from(vec![
+                Span::styled(
+                    "Requires: ",
+                    Style::default()
+                        .fg(theme::highlight())
+                        .add_modifier(Modifier::BOLD),
+                ),
+                Span::styled(
+                    format!("Gemini CLI {min} or newer"),
+                    Style::default().fg(theme::text_primary()),
+                ),
+            ]));
+            content.push(Line::from(""));
+        }
+
+        // Supported operating systems
+        if !extension.platforms.is_empty() {
             content.push(Line::from(vec![
                 Span::styled(
-                    "Tags: ",
+                    "Platforms: ",
                     Style::default()
                         .fg(theme::highlight())
                         .add_modifier(Modifier::BOLD),
                 ),
                 Span::styled(
-                    extension.metadata.tags.join(", "),
-                    Style::default().fg(theme::primary()),
+                    extension.platforms.join(", "),
+                    Style::default().fg(theme::text_primary()),
                 ),
             ]));
+            if !extension.supports_platform(current_platform()) {
+                content.push(Line::from(
+                    Badge::warning(format!(
+                        "  {} Not listed for {}, it may not work here",
+                        Icon::Warning,
+                        current_platform()
+                    ))
+                    .span(),
+                ));
+            }
+            content.push(Line::from(""));
+        }
+
+        // Tags
+        if !extension.metadata.tags.is_empty() {
+            let mut tags = vec![Span::styled(
+                "Tags: ",
+                Style::default()
+                    .fg(theme::highlight())
+                    .add_modifier(Modifier::BOLD),
+            )];
+            tags.extend(ChipRow::new(&extension.metadata.tags, BadgeKind::Info).spans());
+            content.push(Line::from(tags));
             content.push(Line::from(""));
         }
 
@@ -167,8 +462,31 @@ impl Component for ExtensionDetail {
                     .to_string(),
                 Style::default().fg(theme::text_primary()),
             ),
+            Span::styled(
+                format!(
+                    " (Installed {})",
+                    humanize_since(extension.metadata.imported_at)
+                ),
+                Style::default().fg(theme::text_muted()),
+            ),
         ]));
 
+        // Last edit, if the extension has been changed since import
+        if let Some(updated_at) = extension.metadata.updated_at {
+            content.push(Line::from(vec![
+                Span::styled(
+                    "Updated: ",
+                    Style::default()
+                        .fg(theme::highlight())
+                        .add_modifier(Modifier::BOLD),
+                ),
+                Span::styled(
+                    humanize_since(updated_at),
+                    Style::default().fg(theme::text_primary()),
+                ),
+            ]));
+        }
+
         // Source path
         if let Some(path) = &extension.metadata.source_path {
             content.push(Line::from(vec![
@@ -193,11 +511,30 @@ impl Component for ExtensionDetail {
             )));
             content.push(Line::from(""));
 
-            for (name, config) in &extension.mcp_servers {
-                content.push(Line::from(vec![
+            for (name, config) in extension.sorted_mcp_servers() {
+                let mut title = vec![
                     Span::styled("  ", Style::default().fg(theme::text_primary())),
                     Span::styled(format!("• {name}"), Style::default().fg(theme::success())),
-                ]));
+                ];
+                let check = self.server_checks.get(name.as_str());
+                match check {
+                    Some(Ok(())) => title.push(Span::styled(
+                        format!(" {}", Icon::Success),
+                        Style::default().fg(theme::success()),
+                    )),
+                    Some(Err(_)) => title.push(Span::styled(
+                        format!(" {}", Icon::Error),
+                        Style::default().fg(theme::error()),
+                    )),
+                    None => {}
+                }
+                content.push(Line::from(title));
+                if let Some(Err(problem)) = check {
+                    content.push(Line::from(vec![
+                        Span::styled("    Test: ", Style::default().fg(theme::text_secondary())),
+                        Span::styled(problem.clone(), Style::default().fg(theme::error())),
+                    ]));
+                }
 
                 // Server type - MCP servers can be URL-based or command-based
                 if let Some(url) = &config.url {
@@ -218,22 +555,38 @@ impl Component for ExtensionDetail {
                     ]));
 
                     if let Some(args) = &config.args {
-                        content.push(Line::from(vec![
-                            Span::styled(
-                                "    Args: ",
-                                Style::default().fg(theme::text_secondary()),
-                            ),
-                            Span::styled(
-                                args.join(" "),
-                                Style::default().fg(theme::text_primary()),
-                            ),
-                        ]));
+                        // One arg per line, continuation lines aligned under the first
+                        const ARGS_LABEL: &str = "    Args: ";
+                        let width = (inner_area.width as usize).saturating_sub(ARGS_LABEL.len());
+
+                        for (i, arg) in format_arg_list(args, width, MAX_VISIBLE_ARGS)
+                            .into_iter()
+                            .enumerate()
+                        {
+                            let label = if i == 0 {
+                                ARGS_LABEL.to_string()
+                            } else {
+                                " ".repeat(ARGS_LABEL.len())
+                            };
+                            content.push(Line::from(vec![
+                                Span::styled(label, Style::default().fg(theme::text_secondary())),
+                                Span::styled(arg, Style::default().fg(theme::text_primary())),
+                            ]));
+                        }
                     }
                 }
 
+                // Working directory, relative to the extension unless absolute
+                if let Some(cwd) = &config.cwd {
+                    content.push(Line::from(vec![
+                        Span::styled("    Cwd: ", Style::default().fg(theme::text_secondary())),
+                        Span::styled(cwd, Style::default().fg(theme::text_primary())),
+                    ]));
+                }
+
                 // Environment variables
                 if let Some(env) = &config.env {
-                    for (key, value) in env {
+                    for (key, value) in sorted_entries(env) {
                         content.push(Line::from(vec![
                             Span::styled("    Env: ", Style::default().fg(theme::text_secondary())),
                             Span::styled(key, Style::default().fg(theme::highlight())),
@@ -290,6 +643,23 @@ impl Component for ExtensionDetail {
             content.push(Line::from(""));
         }
 
+        if let Some(notes) = &self.notes {
+            content.push(Line::from(Span::styled(
+                "Notes",
+                Style::default()
+                    .fg(theme::accent())
+                    .add_modifier(Modifier::BOLD | Modifier::UNDERLINED),
+            )));
+            content.push(Line::from(""));
+            for line in notes.lines() {
+                content.push(Line::from(vec![
+                    Span::styled("  ", Style::default().fg(theme::text_primary())),
+                    Span::styled(line, Style::default().fg(theme::text_primary())),
+                ]));
+            }
+            content.push(Line::from(""));
+        }
+
         // Create scrollable paragraph
         let paragraph = Paragraph::new(content).scroll((self.scroll_offset, 0));
 
@@ -305,6 +675,10 @@ impl Component for ExtensionDetail {
             ("back", "Back"),
             ("edit", "Edit"),
             ("delete", "Delete"),
+            ("t", "Test servers"),
+            ("y", "Copy install"),
+            ("f", "Files"),
+            ("n", "Notes"),
             ("quit", "Quit"),
         ]);
         let help_bar = Paragraph::new(help_text)
@@ -335,6 +709,9 @@ impl Component for ExtensionDetail {
                     self.scroll_down();
                     Ok(Some(Action::Render))
                 }
+                KeyCode::Char('b') | KeyCode::Esc if self.file_tree.is_some() => {
+                    Ok(self.toggle_file_tree())
+                }
                 KeyCode::Char('b') | KeyCode::Esc => Ok(Some(Action::NavigateBack)),
                 KeyCode::Char('e') => {
                     if let Some(ext) = &self.extension {
@@ -350,6 +727,13 @@ impl Component for ExtensionDetail {
                         Ok(None)
                     }
                 }
+                KeyCode::Char('t') => Ok(self.test_servers()),
+                KeyCode::Char('y') => Ok(self.copy_install_hint()),
+                KeyCode::Char('f') => Ok(self.toggle_file_tree()),
+                KeyCode::Char('n') => Ok(self
+                    .extension
+                    .as_ref()
+                    .map(|ext| Action::EditExtensionNotes(ext.id.clone()))),
                 KeyCode::Char('q') => Ok(Some(Action::Quit)),
                 _ => Ok(None),
             },
@@ -358,6 +742,25 @@ impl Component for ExtensionDetail {
     }
 }
 
+/// Format command args one per line, each truncated to `width` characters.
+///
+/// At most `max_items` args are listed; any remainder is summarised as a
+/// trailing "+K more" line.
+pub fn format_arg_list(args: &[String], width: usize, max_items: usize) -> Vec<String> {
+    let mut lines: Vec<String> = args
+        .iter()
+        .take(max_items)
+        .map(|a| truncate_to_width(a, width))
+        .collect();
+    if args.len() > max_items {
+        lines.push(truncate_to_width(
+            &format!("+{} more", args.len() - max_items),
+            width,
+        ));
+    }
+    lines
+}
+
 // Test helper methods
 impl ExtensionDetail {
     /// Test helper method - returns current section
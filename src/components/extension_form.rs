@@ -12,7 +12,7 @@ use crate::{
     config::Config,
     models::{
         Extension,
-        extension::{ExtensionMetadata, McpServerConfig},
+        extension::{ExtensionMetadata, McpServerConfig, sorted_entries},
     },
     storage::Storage,
     theme,
@@ -29,6 +29,15 @@ pub enum FormField {
     Tags,
 }
 
+/// A step of the form. Steps stack on top of the fields, and Esc leaves the
+/// top one, so only Esc on the fields themselves closes the form.
+#[derive(Debug, Clone, Copy, PartialEq)]
+pub enum FormStep {
+    Fields,
+    Server,  // Adding an MCP server
+    Preview, // Reviewing the manifest that saving would write
+}
+
 pub struct ExtensionForm {
     command_tx: Option<UnboundedSender<Action>>,
     config: Config,
@@ -46,7 +55,6 @@ pub struct ExtensionForm {
     // MCP servers management
     mcp_servers: HashMap<String, McpServerConfig>,
     mcp_server_cursor: usize,
-    editing_server: Option<String>,
     server_name_input: Input,
     server_command_input: Input,
     server_args_input: Input,
@@ -58,6 +66,7 @@ pub struct ExtensionForm {
 
     // Form navigation
     current_field: FormField,
+    steps: Vec<FormStep>, // Never empty; the bottom is always Fields
 
     // Edit mode (if editing existing extension)
     edit_mode: bool,
@@ -79,7 +88,6 @@ impl ExtensionForm {
             tags_input: Input::default(),
             mcp_servers: HashMap::new(),
             mcp_server_cursor: 0,
-            editing_server: None,
             server_name_input: Input::default(),
             server_command_input: Input::default(),
             server_args_input: Input::default(),
@@ -89,6 +97,7 @@ impl ExtensionForm {
             server_trust_input: false,
             server_field_cursor: 0,
             current_field: FormField::Name,
+            steps: vec![FormStep::Fields],
             edit_mode: false,
             edit_extension_id: None,
         }
@@ -122,7 +131,6 @@ impl ExtensionForm {
             tags_input,
             mcp_servers: extension.mcp_servers.clone(),
             mcp_server_cursor: 0,
-            editing_server: None,
             server_name_input: Input::default(),
             server_command_input: Input::default(),
             server_args_input: Input::default(),
@@ -132,12 +140,19 @@ impl ExtensionForm {
             server_trust_input: false,
             server_field_cursor: 0,
             current_field: FormField::Name,
+            steps: vec![FormStep::Fields],
             edit_mode: true,
             edit_extension_id: Some(extension.id.clone()),
         }
     }
 
     fn save_extension(&self) -> Result<()> {
+        // Storage runs the same validation before anything is written
+        self.storage.save_extension(&self.build_extension())
+    }
+
+    /// The extension described by the current form contents
+    fn build_extension(&self) -> Extension {
         let extension_id = if let Some(id) = &self.edit_extension_id {
             id.clone()
         } else {
@@ -173,7 +188,14 @@ impl ExtensionForm {
             .filter(|s| !s.is_empty())
             .collect();
 
-        let extension = Extension {
+        // When editing, what the form doesn't show is kept from the stored copy
+        let stored = self
+            .edit_extension_id
+            .as_ref()
+            .filter(|_| self.edit_mode)
+            .and_then(|id| self.storage.load_extension(id).ok());
+
+        Extension {
             id: extension_id,
             name: self.name_input.value().to_string(),
             version: self.version_input.value().to_string(),
@@ -196,23 +218,47 @@ impl ExtensionForm {
             } else {
                 Some(self.context_content_input.value().to_string())
             },
+            enabled_by_default: stored.as_ref().is_some_and(|e| e.enabled_by_default),
+            min_gemini_version: stored.as_ref().and_then(|e| e.min_gemini_version.clone()),
+            platforms: stored
+                .as_ref()
+                .map(|e| e.platforms.clone())
+                .unwrap_or_default(),
+            icon: stored.as_ref().and_then(|e| e.icon.clone()),
             metadata: ExtensionMetadata {
-                imported_at: if self.edit_mode {
-                    // Preserve original import date
-                    self.storage
-                        .load_extension(self.edit_extension_id.as_ref().unwrap())
-                        .map(|e| e.metadata.imported_at)
-                        .unwrap_or_else(|_| Utc::now())
+                // Preserve original import date
+                imported_at: stored
+                    .as_ref()
+                    .map_or_else(Utc::now, |e| e.metadata.imported_at),
+                updated_at: if self.edit_mode {
+                    Some(Utc::now())
                 } else {
-                    Utc::now()
+                    None
                 },
                 source_path: None,
                 tags,
             },
-        };
+        }
+    }
 
-        self.storage.save_extension(&extension)?;
-        Ok(())
+    /// The first section below the metadata that the detail view shows for
+    /// `extension`: its MCP servers, then its context, else the name.
+    pub fn detail_field(extension: &Extension) -> FormField {
+        if !extension.mcp_servers.is_empty() {
+            FormField::McpServers
+        } else if extension.context_content.is_some() {
+            FormField::ContextContent
+        } else {
+            FormField::Name
+        }
+    }
+
+    /// Open the form focused on `field` instead of the name input.
+    ///
+    /// Callers use this to drop the user straight into the part of the
+    /// extension they were looking at.
+    pub fn set_initial_field(&mut self, field: FormField) {
+        self.current_field = field;
     }
 
     fn next_field(&mut self) {
@@ -239,8 +285,51 @@ impl ExtensionForm {
         };
     }
 
+    /// The step the user is on
+    pub fn current_step(&self) -> FormStep {
+        self.steps.last().copied().unwrap_or(FormStep::Fields)
+    }
+
+    /// Leave the current step, or close the form from the fields
+    fn back(&mut self) -> Action {
+        if self.steps.len() > 1 {
+            self.steps.pop();
+            Action::Render
+        } else {
+            Action::NavigateBack
+        }
+    }
+
+    /// Validate and save the form, closing it on success
+    fn submit(&mut self) -> Action {
+        // Validate before writing so the user sees exactly which field is wrong
+        if let Err(e) = self.build_extension().validate() {
+            return Action::Error(format!("Cannot save extension: {e}"));
+        }
+
+        match self.save_extension() {
+            Ok(_) => {
+                // Send success notification and refresh action
+                if let Some(tx) = &self.command_tx {
+                    let action_verb = if self.edit_extension_id.is_some() {
+                        "updated"
+                    } else {
+                        "created"
+                    };
+                    let _ = tx.send(Action::Success(format!(
+                        "Extension {action_verb} successfully"
+                    )));
+                    let _ = tx.send(Action::RefreshExtensions);
+                    let _ = tx.send(Action::Render);
+                }
+                Action::NavigateBack
+            }
+            Err(e) => Action::Error(format!("Failed to save extension: {e}")),
+        }
+    }
+
     fn start_add_server(&mut self) {
-        self.editing_server = Some(String::new());
+        self.steps.push(FormStep::Server);
         self.server_name_input.reset();
         self.server_command_input.reset();
         self.server_args_input.reset();
@@ -252,7 +341,7 @@ impl ExtensionForm {
     }
 
     fn save_server(&mut self) {
-        if self.editing_server.is_some() {
+        if self.current_step() == FormStep::Server {
             let name = self.server_name_input.value().to_string();
             let command = self.server_command_input.value().to_string();
             let args: Vec<String> = self
@@ -295,16 +384,18 @@ impl ExtensionForm {
                     },
                 };
                 self.mcp_servers.insert(name, server);
-                self.editing_server = None;
+                self.steps.pop();
                 self.server_field_cursor = 0;
             }
         }
     }
 
     fn delete_selected_server(&mut self) {
-        let server_names: Vec<String> = self.mcp_servers.keys().cloned().collect();
-        if let Some(name) = server_names.get(self.mcp_server_cursor) {
-            self.mcp_servers.remove(name);
+        let name = sorted_entries(&self.mcp_servers)
+            .get(self.mcp_server_cursor)
+            .map(|(name, _)| (*name).clone());
+        if let Some(name) = name {
+            self.mcp_servers.remove(&name);
             if self.mcp_server_cursor > 0 && self.mcp_server_cursor >= self.mcp_servers.len() {
                 self.mcp_server_cursor -= 1;
             }
@@ -351,6 +442,41 @@ impl ExtensionForm {
     pub fn context_content_input(&self) -> &Input {
         &self.context_content_input
     }
+
+    /// Focused input of the MCP server editor, if it is open
+    #[allow(dead_code)]
+    pub fn server_field_cursor(&self) -> Option<usize> {
+        (self.current_step() == FormStep::Server).then_some(self.server_field_cursor)
+    }
+
+    /// Show the manifest that saving the form would install
+    fn draw_preview(&self, frame: &mut Frame, area: Rect) -> Result<()> {
+        use crate::utils::build_help_text;
+
+        let chunks = Layout::vertical([Constraint::Min(3), Constraint::Length(2)])
+            .margin(1)
+            .split(area);
+
+        let manifest = serde_json::to_string_pretty(&self.build_extension().manifest())?;
+        let preview = Paragraph::new(manifest)
+            .style(Style::default().fg(theme::text_primary()))
+            .block(
+                Block::default()
+                    .title(" gemini-extension.json ")
+                    .borders(Borders::ALL)
+                    .border_style(Style::default().fg(theme::highlight())),
+            );
+        frame.render_widget(preview, chunks[0]);
+
+        let help_text = build_help_text(&[("Ctrl+S", "Save"), ("back", "Back to edit")]);
+        frame.render_widget(
+            Paragraph::new(help_text)
+                .style(Style::default().fg(theme::text_muted()))
+                .alignment(Alignment::Center),
+            chunks[1],
+        );
+        Ok(())
+    }
 }
 
 impl Component for ExtensionForm {
@@ -390,6 +516,10 @@ impl Component for ExtensionForm {
         let inner = block.inner(area);
         frame.render_widget(block, area);
 
+        if self.current_step() == FormStep::Preview {
+            return self.draw_preview(frame, inner);
+        }
+
         // Create main vertical layout
         let main_chunks = ratatui::layout::Layout::default()
             .direction(ratatui::layout::Direction::Vertical)
@@ -645,7 +775,7 @@ impl Component for ExtensionForm {
             Style::default().fg(theme::text_secondary())
         };
 
-        if self.editing_server.is_some() {
+        if self.current_step() == FormStep::Server {
             // Show server edit form
             let server_block = Block::default()
                 .title("Add MCP Server (Tab: Next field, Enter to save, Esc to cancel)")
@@ -727,9 +857,8 @@ impl Component for ExtensionForm {
                 .borders(Borders::ALL)
                 .border_style(mcp_style);
 
-            let server_items: Vec<ListItem> = self
-                .mcp_servers
-                .iter()
+            let server_items: Vec<ListItem> = sorted_entries(&self.mcp_servers)
+                .into_iter()
                 .enumerate()
                 .map(|(i, (name, server))| {
                     let is_selected = i == self.mcp_server_cursor
@@ -777,41 +906,53 @@ impl Component for ExtensionForm {
             frame.render_widget(mcp_list, mcp_editor_area);
         }
 
-        // Help text
+        // Help text, split over the two footer lines so it fits 80 columns
         use crate::utils::build_help_text;
-        let help_text = match self.current_field {
-            FormField::McpServers if self.editing_server.is_some() => build_help_text(&[
-                ("select", "Save server"),
-                ("back", "Cancel"),
-                ("tab", "Next field"),
-            ]),
-            FormField::McpServers => build_help_text(&[
-                ("tab", "Next field"),
-                ("up", "Navigate"),
-                ("down", "Navigate"),
-                ("create", "New server"),
-                ("delete", "Delete"),
-                ("Ctrl+S", "Save"),
-                ("back", "Cancel"),
-            ]),
-            FormField::ContextContent => build_help_text(&[
-                ("tab", "Next field"),
-                ("up", "Scroll"),
-                ("down", "Scroll"),
-                ("Type", "Edit"),
-                ("Ctrl+S", "Save"),
-                ("back", "Cancel"),
-            ]),
-            _ => build_help_text(&[
-                ("tab", "Next field"),
-                ("Type", "Edit"),
-                ("Ctrl+S", "Save"),
-                ("back", "Cancel"),
-            ]),
+        let help_lines = match self.current_field {
+            // Tab and Shift+Tab move focus within whatever is being edited
+            FormField::McpServers if self.current_step() == FormStep::Server => vec![
+                build_help_text(&[("select", "Save server"), ("back", "Cancel")]),
+                build_help_text(&[("focus", "Switch server field")]),
+            ],
+            FormField::McpServers => vec![
+                build_help_text(&[
+                    ("focus", "Move focus"),
+                    ("up", "Navigate"),
+                    ("down", "Navigate"),
+                ]),
+                build_help_text(&[
+                    ("create", "New"),
+                    ("delete", "Delete"),
+                    ("Ctrl+S", "Save"),
+                    ("Ctrl+P", "Preview"),
+                    ("back", "Cancel"),
+                ]),
+            ],
+            FormField::ContextContent => vec![
+                build_help_text(&[
+                    ("focus", "Move focus"),
+                    ("up", "Scroll"),
+                    ("down", "Scroll"),
+                ]),
+                build_help_text(&[
+                    ("Type", "Edit"),
+                    ("Ctrl+S", "Save"),
+                    ("Ctrl+P", "Preview"),
+                    ("back", "Cancel"),
+                ]),
+            ],
+            _ => vec![
+                build_help_text(&[("focus", "Move focus"), ("Type", "Edit")]),
+                build_help_text(&[
+                    ("Ctrl+S", "Save"),
+                    ("Ctrl+P", "Preview"),
+                    ("back", "Cancel"),
+                ]),
+            ],
         };
         let help_style = Style::default().fg(theme::text_muted());
         frame.render_widget(
-            Paragraph::new(help_text)
+            Paragraph::new(help_lines.join("\n"))
                 .style(help_style)
                 .alignment(Alignment::Center),
             main_chunks[3],
@@ -824,13 +965,22 @@ impl Component for ExtensionForm {
         use crossterm::event::{KeyCode, KeyModifiers};
 
         if let Some(crate::tui::Event::Key(key)) = event {
+            if key.code == KeyCode::Esc {
+                return Ok(Some(self.back()));
+            }
+
+            // Nothing is typed in the preview, so `b` goes back as well
+            if self.current_step() == FormStep::Preview {
+                return Ok(match (key.code, key.modifiers) {
+                    (KeyCode::Char('s'), KeyModifiers::CONTROL) => Some(self.submit()),
+                    (KeyCode::Char('b'), _) => Some(self.back()),
+                    _ => None,
+                });
+            }
+
             // Handle server editing mode separately
-            if self.editing_server.is_some() {
+            if self.current_step() == FormStep::Server {
                 match key.code {
-                    KeyCode::Esc => {
-                        self.editing_server = None;
-                        return Ok(Some(Action::Render));
-                    }
                     KeyCode::Enter => {
                         self.save_server();
                         return Ok(Some(Action::Render));
@@ -920,41 +1070,12 @@ impl Component for ExtensionForm {
 
             // Normal form handling
             match (key.code, key.modifiers) {
-                (KeyCode::Esc, _) => {
-                    return Ok(Some(Action::NavigateBack));
-                }
                 (KeyCode::Char('s'), KeyModifiers::CONTROL) => {
-                    // Save extension
-                    if !self.name_input.value().is_empty() && !self.version_input.value().is_empty()
-                    {
-                        match self.save_extension() {
-                            Ok(_) => {
-                                // Send success notification and refresh action
-                                if let Some(tx) = &self.command_tx {
-                                    let action_verb = if self.edit_extension_id.is_some() {
-                                        "updated"
-                                    } else {
-                                        "created"
-                                    };
-                                    let _ = tx.send(Action::Success(format!(
-                                        "Extension {action_verb} successfully"
-                                    )));
-                                    let _ = tx.send(Action::RefreshExtensions);
-                                    let _ = tx.send(Action::Render);
-                                }
-                                return Ok(Some(Action::NavigateBack));
-                            }
-                            Err(e) => {
-                                return Ok(Some(Action::Error(format!(
-                                    "Failed to save extension: {e}"
-                                ))));
-                            }
-                        }
-                    } else {
-                        return Ok(Some(Action::Error(
-                            "Extension name and version are required".to_string(),
-                        )));
-                    }
+                    return Ok(Some(self.submit()));
+                }
+                (KeyCode::Char('p'), KeyModifiers::CONTROL) => {
+                    self.steps.push(FormStep::Preview);
+                    return Ok(Some(Action::Render));
                 }
                 (KeyCode::Tab, _) => {
                     self.next_field();
@@ -1,17 +1,39 @@
 use std::sync::{Arc, RwLock};
 
+use chrono::{DateTime, Duration, Utc};
 use color_eyre::Result;
 use ratatui::{prelude::*, widgets::*};
 use tokio::sync::mpsc::UnboundedSender;
 use tui_input::Input;
 use tui_input::backend::crossterm::EventHandler;
 
-use super::{Component, settings_view::UserSettings};
+use super::{
+    Component, badge::Badge, highlight::highlight_matches, list_scroll::ListScroll,
+    settings_view::UserSettings,
+};
 use crate::{
-    action::Action, config::Config, models::Extension, storage::Storage, theme,
-    utils::keybinding_manager::KeybindingManager,
+    action::Action,
+    config::Config,
+    icons::Icon,
+    models::{Extension, Profile},
+    storage::Storage,
+    theme,
+    utils::{fuzzy::fuzzy_match, keybinding_manager::KeybindingManager},
 };
 
+/// Extensions installed or updated within this window count as recent
+const RECENT_WINDOW_HOURS: i64 = 24;
+
+/// Ordering of the extension list
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq)]
+pub enum SortMode {
+    /// Storage order (by id)
+    #[default]
+    Default,
+    /// Recently installed or updated extensions first, newest at the top
+    RecentFirst,
+}
+
 #[derive(Default)]
 pub struct ExtensionList {
     command_tx: Option<UnboundedSender<Action>>,
@@ -24,6 +46,13 @@ pub struct ExtensionList {
     search_input: Input,
     settings: Option<Arc<RwLock<UserSettings>>>,
     keybinding_manager: Option<KeybindingManager>,
+    sort_mode: SortMode,
+    recent_count: usize, // Leading entries of filtered_extensions that are recent
+    compact: bool,       // Show only title and description on each card
+    active_profile: Option<Profile>, // The default profile, whose extensions get a badge
+    profiles: Vec<Profile>, // Every profile, for finding unused extensions
+    orphans_only: bool,  // Show only extensions no profile uses
+    scroll: ListScroll,  // First card shown, kept between draws
 }
 
 impl ExtensionList {
@@ -36,12 +65,49 @@ impl ExtensionList {
         // Load extensions from storage
         if let Ok(extensions) = storage.list_extensions() {
             list.extensions = extensions;
-            list.update_filter();
         }
+        list.load_profiles();
 
         list
     }
 
+    /// Reload the profiles that decide badges and which extensions are unused
+    fn load_profiles(&mut self) {
+        self.active_profile = self
+            .storage
+            .as_ref()
+            .and_then(|storage| storage.get_default_profile().ok().flatten());
+        self.profiles = self
+            .storage
+            .as_ref()
+            .and_then(|storage| storage.list_profiles().ok())
+            .unwrap_or_default();
+        self.update_filter();
+    }
+
+    /// Make the default profile's extensions the ones new profiles start with
+    fn sync_defaults(&mut self) -> Action {
+        self.load_profiles();
+        let (Some(storage), Some(profile)) = (&self.storage, &self.active_profile) else {
+            return Action::Error("No default profile to sync from".to_string());
+        };
+
+        match storage.sync_defaults_from_profile(profile) {
+            Ok(changed) => {
+                let message = format!(
+                    "Updated {changed} extension default(s) to match '{}'",
+                    profile.name
+                );
+                if let Ok(extensions) = storage.list_extensions() {
+                    self.extensions = extensions;
+                    self.update_filter();
+                }
+                Action::Success(message)
+            }
+            Err(e) => Action::Error(format!("Failed to sync extension defaults: {e}")),
+        }
+    }
+
     fn update_filter(&mut self) {
         let search_query = self.search_input.value();
         if search_query.is_empty() {
@@ -50,28 +116,44 @@ impl ExtensionList {
         } else {
             // Filter extensions based on search query
             let query = search_query.to_lowercase();
-            self.filtered_extensions = self
+
+            // "path:" restricts the search to where the extension was imported from
+            let path_query = query.strip_prefix("path:").map(str::trim);
+
+            let mut ranked: Vec<(Option<i32>, usize)> = self
                 .extensions
                 .iter()
                 .enumerate()
-                .filter(|(_, ext)| {
-                    ext.name.to_lowercase().contains(&query)
-                        || ext
-                            .description
+                .filter_map(|(i, ext)| {
+                    let rank = if let Some(path_query) = path_query {
+                        ext.metadata
+                            .source_path
                             .as_ref()
-                            .is_some_and(|d| d.to_lowercase().contains(&query))
-                        || ext
-                            .metadata
-                            .tags
-                            .iter()
-                            .any(|tag| tag.to_lowercase().contains(&query))
-                        || ext
-                            .mcp_servers
-                            .keys()
-                            .any(|server_name| server_name.to_lowercase().contains(&query))
+                            .is_some_and(|p| p.to_lowercase().contains(path_query))
+                            .then_some(None)
+                    } else {
+                        search_rank(ext, &query)
+                    };
+                    rank.map(|rank| (rank, i))
                 })
-                .map(|(i, _)| i)
                 .collect();
+
+            // Best matches first; the sort is stable, so ties keep their order
+            ranked.sort_by_key(|&(rank, _)| std::cmp::Reverse(rank));
+            self.filtered_extensions = ranked.into_iter().map(|(_, i)| i).collect();
+        }
+
+        if self.orphans_only {
+            self.filtered_extensions
+                .retain(|&i| self.extensions[i].is_orphan(&self.profiles));
+        }
+
+        self.recent_count = 0;
+        if self.sort_mode == SortMode::RecentFirst {
+            let (ordered, recent_count) =
+                group_recent(&self.extensions, &self.filtered_extensions, Utc::now());
+            self.filtered_extensions = ordered;
+            self.recent_count = recent_count;
         }
 
         // Adjust selection if needed
@@ -98,6 +180,84 @@ impl ExtensionList {
         }
     }
 
+    fn toggle_sort_mode(&mut self) {
+        self.sort_mode = match self.sort_mode {
+            SortMode::Default => SortMode::RecentFirst,
+            SortMode::RecentFirst => SortMode::Default,
+        };
+        self.selected = 0;
+        self.update_filter();
+    }
+
+    fn toggle_orphans_only(&mut self) {
+        self.orphans_only = !self.orphans_only;
+        self.selected = 0;
+        self.update_filter();
+    }
+
+    /// Enable the selected extension in the default profile, or disable it
+    /// there if it is already enabled. The profile is saved straight away.
+    fn toggle_in_active_profile(&mut self) -> Option<Action> {
+        self.load_profiles();
+        let extension = self.get_selected_extension()?.clone();
+        let storage = self.storage.clone()?;
+        let Some(mut profile) = self.active_profile.clone() else {
+            return Some(Action::Error(
+                "No default profile to enable extensions in".to_string(),
+            ));
+        };
+
+        let enabled = match profile
+            .extension_ids
+            .iter()
+            .position(|id| *id == extension.id)
+        {
+            Some(pos) => {
+                profile.extension_ids.remove(pos);
+                false
+            }
+            None => {
+                profile.extension_ids.push(extension.id.clone());
+                true
+            }
+        };
+        profile.metadata.updated_at = Utc::now();
+        if let Err(e) = storage.save_profile(&profile) {
+            return Some(Action::Error(format!(
+                "Failed to update '{}': {e}",
+                profile.name
+            )));
+        }
+
+        self.load_profiles();
+        if let Some(tx) = &self.command_tx {
+            let _ = tx.send(Action::RefreshProfiles);
+        }
+        Some(Action::Success(format!(
+            "{} '{}' in '{}'",
+            if enabled { "Enabled" } else { "Disabled" },
+            extension.name,
+            profile.name
+        )))
+    }
+
+    /// Ask to delete every unused extension, only while they are the ones shown
+    fn delete_unused(&self) -> Option<Action> {
+        self.orphans_only.then_some(Action::DeleteUnusedExtensions)
+    }
+
+    /// Put the cursor back on `id` after a reload. If it's gone the cursor
+    /// keeps its position, landing on the extension that took its place.
+    fn reselect(&mut self, id: Option<&str>) {
+        if let Some(pos) = self
+            .filtered_extensions
+            .iter()
+            .position(|&i| Some(self.extensions[i].id.as_str()) == id)
+        {
+            self.selected = pos;
+        }
+    }
+
     fn get_selected_extension(&self) -> Option<&Extension> {
         self.filtered_extensions
             .get(self.selected)
@@ -129,6 +289,74 @@ impl ExtensionList {
     pub fn total_count(&self) -> usize {
         self.extensions.len()
     }
+
+    #[allow(dead_code)]
+    pub fn is_compact(&self) -> bool {
+        self.compact
+    }
+
+    #[allow(dead_code)]
+    pub fn sort_mode(&self) -> SortMode {
+        self.sort_mode
+    }
+
+    #[allow(dead_code)]
+    pub fn active_profile(&self) -> Option<&Profile> {
+        self.active_profile.as_ref()
+    }
+
+    #[allow(dead_code)]
+    pub fn is_orphans_only(&self) -> bool {
+        self.orphans_only
+    }
+
+    #[allow(dead_code)]
+    pub fn recent_count(&self) -> usize {
+        self.recent_count
+    }
+}
+
+/// Whether `extension` is enabled in the active profile, if there is one
+pub fn in_active_profile(profile: Option<&Profile>, extension: &Extension) -> bool {
+    profile.is_some_and(|profile| profile.extension_ids.contains(&extension.id))
+}
+
+/// How `extension` ranks in a search for `query`: `Some(score)` when its name
+/// matches fuzzily, `None` when only its description, a tag or a server name
+/// contains the query, so those come after every name match. Returns no rank
+/// at all when nothing matches.
+fn search_rank(extension: &Extension, query: &str) -> Option<Option<i32>> {
+    if let Some(name_match) = fuzzy_match(query, &extension.name) {
+        return Some(Some(name_match.score));
+    }
+
+    let query = query.to_lowercase();
+    let contains = |text: &str| text.to_lowercase().contains(&query);
+    (extension.description.as_deref().is_some_and(contains)
+        || extension.metadata.tags.iter().any(|tag| contains(tag))
+        || extension.mcp_servers.keys().any(|name| contains(name)))
+    .then_some(None)
+}
+
+/// Move extensions installed or updated within the last day ahead of the rest.
+///
+/// Recent extensions are ordered newest first; everything else keeps its
+/// relative order. Returns the reordered indices and how many are recent.
+pub fn group_recent(
+    extensions: &[Extension],
+    indices: &[usize],
+    now: DateTime<Utc>,
+) -> (Vec<usize>, usize) {
+    let cutoff = now - Duration::hours(RECENT_WINDOW_HOURS);
+    let (mut recent, rest): (Vec<usize>, Vec<usize>) = indices
+        .iter()
+        .copied()
+        .partition(|&i| extensions[i].last_updated() >= cutoff);
+    recent.sort_by_key(|&i| std::cmp::Reverse(extensions[i].last_updated()));
+
+    let recent_count = recent.len();
+    recent.extend(rest);
+    (recent, recent_count)
 }
 
 impl Component for ExtensionList {
@@ -143,6 +371,9 @@ impl Component for ExtensionList {
     }
 
     fn register_settings_handler(&mut self, settings: Arc<RwLock<UserSettings>>) -> Result<()> {
+        if let Ok(settings) = settings.read() {
+            self.compact = settings.compact_cards;
+        }
         self.settings = Some(settings.clone());
         self.keybinding_manager = Some(KeybindingManager::new(settings));
         Ok(())
@@ -157,14 +388,18 @@ impl Component for ExtensionList {
                 // No render-specific logic needed
             }
             Action::RefreshExtensions => {
-                // Reload extensions from storage
+                // Reload extensions from storage, keeping the cursor on the same one
+                let selected_id = self.get_selected_extension().map(|e| e.id.clone());
                 if let Some(storage) = &self.storage
                     && let Ok(extensions) = storage.list_extensions()
                 {
                     self.extensions = extensions;
-                    self.update_filter();
                 }
+                self.load_profiles();
+                self.reselect(selected_id.as_deref());
             }
+            Action::RefreshProfiles => self.load_profiles(),
+            Action::SetCompactCards(compact) => self.compact = compact,
             _ => {}
         }
         Ok(None)
@@ -219,6 +454,10 @@ impl Component for ExtensionList {
                 self.filtered_extensions.len(),
                 self.extensions.len()
             )
+        } else if self.orphans_only {
+            " Extensions · Unused (D deletes all) ".to_string()
+        } else if self.sort_mode == SortMode::RecentFirst {
+            " Extensions · Recent first ".to_string()
         } else {
             " Extensions ".to_string()
         };
@@ -230,6 +469,10 @@ impl Component for ExtensionList {
             .border_type(BorderType::Rounded)
             .border_style(Style::default().fg(theme::text_secondary()));
 
+        // Names are searched fuzzily; show which characters matched
+        let name_query = Some(self.search_input.value())
+            .filter(|query| !query.is_empty() && !query.to_lowercase().starts_with("path:"));
+
         // Create list items
         let items: Vec<ListItem> = self
             .filtered_extensions
@@ -238,26 +481,51 @@ impl Component for ExtensionList {
             .filter_map(|(i, &ext_idx)| {
                 self.extensions.get(ext_idx).map(|ext| {
                     let is_selected = i == self.selected;
+                    // With a default profile, the extensions it leaves out are dimmed
+                    let is_enabled = self.active_profile.is_none()
+                        || in_active_profile(self.active_profile.as_ref(), ext);
+                    let name_color = if is_enabled {
+                        theme::text_primary()
+                    } else {
+                        theme::text_muted()
+                    };
+                    // Divide the recent group from the rest in place of the spacer line
+                    let is_last_recent = self.recent_count > 0
+                        && i + 1 == self.recent_count
+                        && self.recent_count < self.filtered_extensions.len();
 
                     // Build the display string
-                    let content = vec![
-                        Line::from(vec![
-                            Span::styled(
-                                &ext.name,
-                                if is_selected {
-                                    Style::default()
-                                        .fg(theme::text_primary())
-                                        .add_modifier(Modifier::BOLD)
-                                } else {
-                                    Style::default().fg(theme::text_primary())
-                                },
-                            ),
-                            Span::styled(" ", Style::default().fg(theme::text_primary())),
-                            Span::styled(
-                                format!("v{}", ext.version),
-                                Style::default().fg(theme::text_muted()),
-                            ),
-                        ]),
+                    let name_style = if is_selected {
+                        Style::default().fg(name_color).add_modifier(Modifier::BOLD)
+                    } else {
+                        Style::default().fg(name_color)
+                    };
+                    let matched = name_query
+                        .and_then(|query| fuzzy_match(query, &ext.name))
+                        .map(|name_match| name_match.positions)
+                        .unwrap_or_default();
+
+                    let mut title = vec![Span::styled(
+                        ext.display_icon()
+                            .map(|icon| format!("{icon} "))
+                            .unwrap_or_default(),
+                        Style::default().fg(theme::text_primary()),
+                    )];
+                    title.extend(highlight_matches(&ext.name, &matched, name_style));
+                    title.extend([
+                        Span::styled(" ", Style::default().fg(theme::text_primary())),
+                        Span::styled(
+                            format!("v{}", ext.version),
+                            Style::default().fg(theme::text_muted()),
+                        ),
+                        if in_active_profile(self.active_profile.as_ref(), ext) {
+                            Badge::success(format!("  {}", Icon::Checked)).span()
+                        } else {
+                            Span::raw("")
+                        },
+                    ]);
+                    let mut content = vec![
+                        Line::from(title),
                         Line::from(vec![
                             Span::styled("  ", Style::default().fg(theme::text_primary())),
                             Span::styled(
@@ -265,20 +533,26 @@ impl Component for ExtensionList {
                                 Style::default().fg(theme::text_secondary()),
                             ),
                         ]),
-                        Line::from(vec![
+                    ];
+
+                    // Compact cards stop at the title and description
+                    if !self.compact {
+                        content.push(Line::from(vec![
                             Span::styled("  ", Style::default().fg(theme::text_primary())),
-                            Span::styled(
-                                format!("{} MCP servers", ext.mcp_servers.len()),
-                                Style::default().fg(theme::accent()),
-                            ),
+                            Badge::info(format!("{} MCP servers", ext.mcp_servers.len())).span(),
                             Span::styled(" | ", Style::default().fg(theme::text_secondary())),
-                            Span::styled(
-                                format!("{} tags", ext.metadata.tags.len()),
-                                Style::default().fg(theme::primary()),
-                            ),
-                        ]),
-                        Line::from(""), // Empty line for spacing
-                    ];
+                            Badge::neutral(format!("{} tags", ext.metadata.tags.len())).span(),
+                        ]));
+                    }
+
+                    if is_last_recent {
+                        content.push(Line::from(Span::styled(
+                            "─".repeat(area.width.saturating_sub(4) as usize),
+                            Style::default().fg(theme::text_muted()),
+                        )));
+                    } else if !self.compact {
+                        content.push(Line::from("")); // Empty line for spacing
+                    }
 
                     ListItem::new(content)
                 })
@@ -290,6 +564,7 @@ impl Component for ExtensionList {
             || (self.search_mode
                 && self.filtered_extensions.is_empty()
                 && !self.search_input.value().is_empty())
+            || (self.orphans_only && self.filtered_extensions.is_empty())
         {
             // Show empty state message
             let empty_msg = if self.search_mode && !self.search_input.value().is_empty() {
@@ -298,6 +573,12 @@ impl Component for ExtensionList {
                     "",
                     "Try a different search term",
                 ]
+            } else if self.orphans_only && !self.extensions.is_empty() {
+                vec![
+                    "Every extension is used by a profile",
+                    "",
+                    "Press 'u' to show all extensions",
+                ]
             } else {
                 vec![
                     "No extensions found",
@@ -314,6 +595,20 @@ impl Component for ExtensionList {
 
             frame.render_widget(empty_widget, list_area);
         } else {
+            // Show the cards that fit, keeping the selected one in view, and
+            // say how many are out of view
+            let heights: Vec<usize> = items.iter().map(ListItem::height).collect();
+            let viewport = list_area.height.saturating_sub(2) as usize;
+            let offset = self.scroll.follow(&heights, self.selected, viewport);
+            let block = match self.scroll.hint(&heights, viewport) {
+                Some(hint) => block.title(
+                    Line::from(format!(" {hint} "))
+                        .style(Style::default().fg(theme::text_muted()))
+                        .right_aligned(),
+                ),
+                None => block,
+            };
+
             // Create the list widget
             let list = List::new(items)
                 .block(block)
@@ -321,8 +616,9 @@ impl Component for ExtensionList {
                 .highlight_symbol("│ ");
 
             // Create a stateful list to track selection
-            let mut state = ListState::default();
-            state.select(Some(self.selected));
+            let mut state = ListState::default()
+                .with_offset(offset)
+                .with_selected(Some(self.selected));
 
             // Render the list
             frame.render_stateful_widget(list, list_area, &mut state);
@@ -348,6 +644,11 @@ impl Component for ExtensionList {
                         ("import", "Import"),
                         ("delete", "Delete"),
                         ("search", "Search"),
+                        ("Space", "Enable/disable"),
+                        ("s", "Sort"),
+                        ("m", "Compact"),
+                        ("p", "Sync defaults"),
+                        ("u", "Unused"),
                         ("quit", "Quit"),
                     ])
                 }
@@ -370,6 +671,11 @@ impl Component for ExtensionList {
                         ("import", "Import"),
                         ("delete", "Delete"),
                         ("search", "Search"),
+                        ("Space", "Enable/disable"),
+                        ("s", "Sort"),
+                        ("m", "Compact"),
+                        ("p", "Sync defaults"),
+                        ("u", "Unused"),
                         ("quit", "Quit"),
                     ])
                 }
@@ -483,6 +789,21 @@ impl Component for ExtensionList {
                                 }
                                 Ok(Some(Action::Render))
                             }
+                            KeyCode::Char('s') => {
+                                self.toggle_sort_mode();
+                                Ok(Some(Action::Render))
+                            }
+                            KeyCode::Char('m') => {
+                                self.compact = !self.compact;
+                                Ok(Some(Action::Render))
+                            }
+                            KeyCode::Char(' ') => Ok(self.toggle_in_active_profile()),
+                            KeyCode::Char('p') => Ok(Some(self.sync_defaults())),
+                            KeyCode::Char('u') => {
+                                self.toggle_orphans_only();
+                                Ok(Some(Action::Render))
+                            }
+                            KeyCode::Char('D') => Ok(self.delete_unused()),
                             KeyCode::Tab => Ok(Some(Action::NavigateToProfiles)),
                             _ => Ok(None),
                         }
@@ -539,6 +860,21 @@ impl Component for ExtensionList {
                                 Ok(Some(Action::Render))
                             }
                             KeyCode::Char('q') => Ok(Some(Action::Quit)),
+                            KeyCode::Char('s') => {
+                                self.toggle_sort_mode();
+                                Ok(Some(Action::Render))
+                            }
+                            KeyCode::Char('m') => {
+                                self.compact = !self.compact;
+                                Ok(Some(Action::Render))
+                            }
+                            KeyCode::Char(' ') => Ok(self.toggle_in_active_profile()),
+                            KeyCode::Char('p') => Ok(Some(self.sync_defaults())),
+                            KeyCode::Char('u') => {
+                                self.toggle_orphans_only();
+                                Ok(Some(Action::Render))
+                            }
+                            KeyCode::Char('D') => Ok(self.delete_unused()),
                             KeyCode::Tab => Ok(Some(Action::NavigateToProfiles)),
                             _ => Ok(None),
                         }
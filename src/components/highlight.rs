@@ -0,0 +1,32 @@
+use ratatui::prelude::*;
+
+use crate::theme;
+
+/// `text` as spans in `style`, with the characters at `positions` (char
+/// indices, as from [`fuzzy_match`](crate::utils::fuzzy::fuzzy_match))
+/// picked out so it's clear why a search matched
+pub fn highlight_matches(text: &str, positions: &[usize], style: Style) -> Vec<Span<'static>> {
+    let matched_style = style
+        .fg(theme::highlight())
+        .add_modifier(Modifier::UNDERLINED);
+
+    let mut spans: Vec<Span<'static>> = Vec::new();
+    let mut run = String::new();
+    let mut run_matched = false;
+    for (i, c) in text.chars().enumerate() {
+        let matched = positions.contains(&i);
+        if matched != run_matched && !run.is_empty() {
+            let run_style = if run_matched { matched_style } else { style };
+            spans.push(Span::styled(std::mem::take(&mut run), run_style));
+        }
+        run_matched = matched;
+        run.push(c);
+    }
+    if !run.is_empty() || spans.is_empty() {
+        spans.push(Span::styled(
+            run,
+            if run_matched { matched_style } else { style },
+        ));
+    }
+    spans
+}
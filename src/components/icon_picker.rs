@@ -0,0 +1,270 @@
+use color_eyre::Result;
+use ratatui::{prelude::*, widgets::*};
+use tui_input::Input;
+use tui_input::backend::crossterm::EventHandler;
+
+use super::Component;
+use crate::{action::Action, theme};
+
+/// Icons per row of the grid
+const COLUMNS: usize = 8;
+
+/// Widest ASCII stand-in, so the grid lines up in ASCII mode
+const ASCII_WIDTH: usize = 5;
+
+/// Curated icons, each with the ASCII stand-in offered when emoji are turned
+/// off and the words it can be found by
+const ICONS: &[(&str, &str, &str)] = &[
+    ("🚀", "[>>]", "rocket launch deploy release"),
+    ("💻", "[dev]", "laptop computer code dev"),
+    ("🧪", "[lab]", "test lab experiment qa"),
+    ("🐛", "[bug]", "bug debug fix"),
+    ("📦", "[pkg]", "package box build"),
+    ("🔒", "[sec]", "lock security secret private"),
+    ("🌐", "[web]", "globe web network internet"),
+    ("🧠", "[ai]", "brain ai model think"),
+    ("🤖", "[bot]", "robot ai agent bot"),
+    ("📊", "[dat]", "chart data analytics metrics"),
+    ("💾", "[db]", "disk save storage database"),
+    ("📝", "[doc]", "memo notes docs write"),
+    ("📚", "[lib]", "books docs learn research"),
+    ("🎨", "[art]", "palette art design ui"),
+    ("🔥", "[prd]", "fire hot prod production"),
+    ("⭐", "[*]", "star favorite"),
+    ("✅", "[ok]", "check done ok"),
+    ("⚡", "[zap]", "zap lightning fast power"),
+    ("🌙", "[nit]", "moon night dark"),
+    ("🏠", "[~]", "house home personal"),
+    ("💼", "[job]", "briefcase work business"),
+    ("🎯", "[o]", "target goal focus"),
+    ("🔍", "[?]", "magnifier search find"),
+    ("🧹", "[cln]", "broom clean tidy"),
+    ("🐳", "[dkr]", "whale docker container"),
+    ("🐍", "[py]", "snake python"),
+    ("🦀", "[rs]", "crab rust"),
+    ("☕", "[cup]", "coffee java break"),
+    ("🌱", "[new]", "seedling new green grow"),
+    ("🔬", "[sci]", "microscope science research"),
+    ("🧩", "[ext]", "puzzle extension plugin"),
+    ("🔧", "[cfg]", "wrench tools config settings"),
+];
+
+/// Filterable grid of curated icons.
+///
+/// Typing filters the grid, the arrow keys move the selection and Enter
+/// reports it as `Action::IconPicked`. Delete picks "no icon" and Esc backs
+/// out without choosing. With ASCII icons turned on, the ASCII stand-ins are
+/// offered instead of the emoji.
+pub struct IconPicker {
+    filter: Input,
+    matches: Vec<usize>, // Indices into ICONS
+    selected: usize,     // Index into matches
+    ascii: bool,         // Offer the ASCII stand-ins
+}
+
+impl Default for IconPicker {
+    fn default() -> Self {
+        Self::new()
+    }
+}
+
+impl IconPicker {
+    pub fn new() -> Self {
+        Self {
+            filter: Input::default(),
+            matches: (0..ICONS.len()).collect(),
+            selected: 0,
+            ascii: crate::icons::ascii_only(),
+        }
+    }
+
+    /// Start with `icon` selected, if it is one of the curated icons in
+    /// either style
+    pub fn with_selected(icon: Option<&str>) -> Self {
+        let mut picker = Self::new();
+        if let Some(index) = icon.and_then(|icon| {
+            ICONS
+                .iter()
+                .position(|(emoji, ascii, _)| *emoji == icon || *ascii == icon)
+        }) {
+            picker.selected = index;
+        }
+        picker
+    }
+
+    /// Offer the ASCII stand-ins (or the emoji) whatever the current setting
+    #[allow(dead_code)]
+    pub fn with_ascii(mut self, ascii: bool) -> Self {
+        self.ascii = ascii;
+        self
+    }
+
+    /// Icon `index` of [`ICONS`] in the style being offered
+    fn glyph(&self, index: usize) -> &'static str {
+        let (emoji, ascii, _) = ICONS[index];
+        if self.ascii { ascii } else { emoji }
+    }
+
+    /// The highlighted icon, if any icon matches the filter
+    pub fn selected_icon(&self) -> Option<&'static str> {
+        self.matches.get(self.selected).map(|&i| self.glyph(i))
+    }
+
+    /// Icons matching the current filter, in grid order
+    #[allow(dead_code)]
+    pub fn matching_icons(&self) -> Vec<&'static str> {
+        self.matches.iter().map(|&i| self.glyph(i)).collect()
+    }
+
+    /// Keep the icons where every typed term starts one of the icon's words
+    fn update_filter(&mut self) {
+        let query = self.filter.value().trim().to_lowercase();
+        self.matches = ICONS
+            .iter()
+            .enumerate()
+            .filter(|(_, (emoji, ascii, words))| {
+                *emoji == query
+                    || *ascii == query
+                    || query
+                        .split_whitespace()
+                        .all(|term| words.split_whitespace().any(|w| w.starts_with(term)))
+            })
+            .map(|(i, _)| i)
+            .collect();
+        self.selected = 0;
+    }
+
+    /// Move the selection by `delta` cells, staying inside the grid
+    fn move_by(&mut self, delta: isize) {
+        if self.matches.is_empty() {
+            return;
+        }
+        let target = self.selected as isize + delta;
+        if (0..self.matches.len() as isize).contains(&target) {
+            self.selected = target as usize;
+        }
+    }
+}
+
+impl Component for IconPicker {
+    fn draw(&mut self, frame: &mut Frame, area: Rect) -> Result<()> {
+        let rows = ICONS.len().div_ceil(COLUMNS) as u16;
+        let cell_width = if self.ascii { ASCII_WIDTH + 1 } else { 4 };
+        let dialog_width = ((COLUMNS * cell_width) as u16 + 12).min(area.width.saturating_sub(4));
+        let dialog_height = (rows + 8).min(area.height.saturating_sub(2));
+
+        // Center the picker over whatever opened it
+        let dialog_area = Rect {
+            x: area.x + area.width.saturating_sub(dialog_width) / 2,
+            y: area.y + area.height.saturating_sub(dialog_height) / 2,
+            width: dialog_width,
+            height: dialog_height,
+        };
+        frame.render_widget(Clear, dialog_area);
+
+        let block = Block::default()
+            .title(" Choose an Icon ")
+            .title_alignment(Alignment::Center)
+            .borders(Borders::ALL)
+            .border_type(BorderType::Rounded)
+            .border_style(Style::default().fg(theme::highlight()))
+            .style(Style::default().bg(theme::overlay()));
+        let inner = block.inner(dialog_area);
+        frame.render_widget(block, dialog_area);
+
+        let mut lines = vec![
+            Line::from(vec![
+                Span::styled("Search: ", Style::default().fg(theme::highlight())),
+                Span::styled(
+                    self.filter.value(),
+                    Style::default().fg(theme::text_primary()),
+                ),
+            ]),
+            Line::from(""),
+        ];
+
+        if self.matches.is_empty() {
+            lines.push(Line::from(Span::styled(
+                "No icons match",
+                Style::default().fg(theme::text_muted()),
+            )));
+        } else {
+            for (row, chunk) in self.matches.chunks(COLUMNS).enumerate() {
+                let cells = chunk.iter().enumerate().map(|(col, &icon)| {
+                    let style = if row * COLUMNS + col == self.selected {
+                        Style::default()
+                            .bg(theme::selection())
+                            .add_modifier(Modifier::BOLD)
+                    } else {
+                        Style::default()
+                    };
+                    let glyph = self.glyph(icon);
+                    let cell = if self.ascii {
+                        format!(" {glyph:<ASCII_WIDTH$}")
+                    } else {
+                        format!(" {glyph} ")
+                    };
+                    Span::styled(cell, style)
+                });
+                lines.push(Line::from(cells.collect::<Vec<_>>()));
+            }
+        }
+
+        lines.push(Line::from(""));
+        if let Some(&icon) = self.matches.get(self.selected) {
+            lines.push(Line::from(Span::styled(
+                ICONS[icon].2,
+                Style::default().fg(theme::text_secondary()),
+            )));
+        }
+        lines.push(Line::from(Span::styled(
+            "Enter: Choose | Del: No icon | Esc: Cancel",
+            Style::default().fg(theme::text_muted()),
+        )));
+
+        frame.render_widget(Paragraph::new(lines), inner);
+        Ok(())
+    }
+
+    fn handle_events(&mut self, event: Option<crate::tui::Event>) -> Result<Option<Action>> {
+        use crossterm::event::KeyCode;
+
+        let Some(crate::tui::Event::Key(key)) = event else {
+            return Ok(None);
+        };
+        match key.code {
+            KeyCode::Esc => Ok(Some(Action::NavigateBack)),
+            KeyCode::Enter => Ok(self
+                .selected_icon()
+                .map(|icon| Action::IconPicked(Some(icon.to_string())))),
+            KeyCode::Delete => Ok(Some(Action::IconPicked(None))),
+            KeyCode::Left => {
+                self.move_by(-1);
+                Ok(Some(Action::Render))
+            }
+            KeyCode::Right => {
+                self.move_by(1);
+                Ok(Some(Action::Render))
+            }
+            KeyCode::Up => {
+                self.move_by(-(COLUMNS as isize));
+                Ok(Some(Action::Render))
+            }
+            KeyCode::Down => {
+                self.move_by(COLUMNS as isize);
+                Ok(Some(Action::Render))
+            }
+            _ => {
+                if self
+                    .filter
+                    .handle_event(&crossterm::event::Event::Key(key))
+                    .is_some()
+                {
+                    self.update_filter();
+                    return Ok(Some(Action::Render));
+                }
+                Ok(None)
+            }
+        }
+    }
+}
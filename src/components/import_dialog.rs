@@ -7,15 +7,22 @@ use std::collections::HashMap;
 use std::path::{Path, PathBuf};
 use std::time::Instant;
 use tokio::sync::mpsc::UnboundedSender;
+use tracing::warn;
 
 use super::Component;
 use crate::{
     action::Action,
     config::Config,
-    models::extension::{Extension, ExtensionMetadata, McpServerConfig},
-    storage::Storage,
+    icons::Icon,
+    models::{
+        Profile,
+        extension::{Extension, ExtensionMetadata, McpServerConfig},
+        profile::ProfileDefaults,
+    },
+    storage::{ConflictResolution, ImportOutcome, ProfileExport, Storage},
     theme,
     tui::Event,
+    utils::text::read_text,
 };
 
 pub struct ImportDialog {
@@ -24,12 +31,32 @@ pub struct ImportDialog {
     storage: Storage,
     state: ImportState,
     state_timestamp: Option<Instant>,
+    // Existing extension and the import that would replace it, awaiting confirmation
+    pending_update: Option<(Extension, Extension)>,
+    // Profile settings the manifest being imported suggests
+    suggested_defaults: Option<ProfileDefaults>,
+    // Suggested settings awaiting confirmation before they touch a profile
+    pending_defaults: Option<PendingDefaults>,
+    // Profile export whose ID is taken, awaiting a skip, rename or overwrite
+    pending_profile: Option<PathBuf>,
+}
+
+/// Settings offered to the default profile once an import is saved
+struct PendingDefaults {
+    profile: Profile, // The profile as it would be saved
+    extension_name: String,
+    adds_extension: bool,
+    variables: Vec<String>, // Names of the variables that would be set
+    message: String,        // Import result, reported once the user decides
 }
 
 #[derive(Debug, Clone, PartialEq)]
 enum ImportState {
     Selecting,
     Importing,
+    ConfirmUpdate(String), // Name of the extension that is already installed
+    ConfirmDefaults,
+    ConfirmProfileConflict(String), // Name of the profile that has the ID
     Error(String),
 }
 
@@ -42,10 +69,120 @@ struct ImportExtension {
     description: Option<String>,
     #[serde(rename = "mcpServers")]
     mcp_servers: Option<HashMap<String, McpServerConfig>>,
+    #[serde(alias = "contextFileName")]
     context_file_name: Option<String>,
     context_content: Option<String>,
+    #[serde(rename = "enabledByDefault", default)]
+    enabled_by_default: bool,
+    #[serde(rename = "minGeminiVersion")]
+    min_gemini_version: Option<String>,
+    #[serde(default)]
+    platforms: Vec<String>,
+    icon: Option<String>,
     // The metadata in the import files has a different structure than our internal one
     metadata: Option<ImportMetadata>,
+    #[serde(rename = "profileDefaults")]
+    profile_defaults: Option<ProfileDefaults>,
+}
+
+/// Top-level manifest keys `ImportExtension` reads; anything else is dropped
+const RECOGNIZED_FIELDS: &[&str] = &[
+    "id",
+    "name",
+    "version",
+    "description",
+    "mcpServers",
+    "context_file_name",
+    "contextFileName",
+    "context_content",
+    "enabledByDefault",
+    "minGeminiVersion",
+    "platforms",
+    "icon",
+    "metadata",
+    "profileDefaults",
+];
+
+/// Top-level keys of the manifest in `content` that the import will drop,
+/// sorted by name
+fn unrecognized_fields(content: &str) -> Vec<String> {
+    let Ok(serde_json::Value::Object(fields)) = serde_json::from_str(content) else {
+        return Vec::new();
+    };
+    fields
+        .into_iter()
+        .map(|(key, _)| key)
+        .filter(|key| !RECOGNIZED_FIELDS.contains(&key.as_str()))
+        .collect()
+}
+
+/// Directories never searched for nested manifests
+const SKIPPED_DIRS: &[&str] = &["node_modules", "target"];
+
+/// How many directory levels below the imported one are searched for manifests
+const MANIFEST_SEARCH_DEPTH: usize = 3;
+
+/// The manifest directly inside `dir`, preferring `extension.json`
+fn manifest_in(dir: &Path) -> Option<PathBuf> {
+    ["extension.json", "gemini-extension.json"]
+        .into_iter()
+        .map(|name| dir.join(name))
+        .find(|path| path.exists())
+}
+
+/// Manifests in subdirectories of `dir`, sorted by path. A directory with a
+/// manifest is not searched further, and hidden or dependency directories
+/// are skipped.
+fn find_nested_manifests(dir: &Path) -> Vec<PathBuf> {
+    fn walk(dir: &Path, depth: usize, found: &mut Vec<PathBuf>) {
+        let Ok(entries) = std::fs::read_dir(dir) else {
+            return;
+        };
+        for entry in entries.flatten() {
+            let path = entry.path();
+            let name = entry.file_name();
+            let name = name.to_string_lossy();
+            if !path.is_dir()
+                || path.is_symlink()
+                || name.starts_with('.')
+                || SKIPPED_DIRS.contains(&name.as_ref())
+            {
+                continue;
+            }
+            match manifest_in(&path) {
+                Some(manifest) => found.push(manifest),
+                None if depth > 1 => walk(&path, depth - 1, found),
+                None => {}
+            }
+        }
+    }
+
+    let mut found = Vec::new();
+    walk(dir, MANIFEST_SEARCH_DEPTH, &mut found);
+    found.sort();
+    found
+}
+
+/// Whether `path` is a symlink that leads outside `dir`, or nowhere.
+///
+/// Context files are copied into the extension and handed to Gemini, so a
+/// link to a file elsewhere on disk must not have its contents pulled in.
+fn links_outside(dir: &Path, path: &Path) -> bool {
+    if !path.is_symlink() {
+        return false;
+    }
+    let escapes = match (std::fs::canonicalize(dir), std::fs::canonicalize(path)) {
+        (Ok(dir), Ok(target)) => !target.starts_with(dir),
+        _ => true,
+    };
+    if escapes {
+        warn!(
+            "Skipping {}: it links outside {}",
+            path.display(),
+            dir.display()
+        );
+    }
+    escapes
 }
 
 #[derive(Debug, Deserialize)]
@@ -87,6 +224,10 @@ impl ImportDialog {
             storage,
             state: ImportState::Selecting,
             state_timestamp: None,
+            pending_update: None,
+            suggested_defaults: None,
+            pending_defaults: None,
+            pending_profile: None,
         }
     }
 
@@ -94,11 +235,18 @@ impl ImportDialog {
     pub fn reset(&mut self) {
         self.state = ImportState::Selecting;
         self.state_timestamp = None;
+        self.pending_update = None;
+        self.suggested_defaults = None;
+        self.pending_defaults = None;
+        self.pending_profile = None;
         // The explorer maintains its own state (current directory)
         // which is fine - users might want to stay in the same directory
     }
 
     fn import_extension(&mut self, path: PathBuf) -> Result<()> {
+        // Suggestions belong to the manifest being imported, never an earlier one
+        self.suggested_defaults = None;
+
         // Check if it's a directory or a file
         if path.is_dir() {
             self.import_from_directory(path)?;
@@ -118,17 +266,30 @@ impl ImportDialog {
     }
 
     fn import_from_directory(&mut self, dir_path: PathBuf) -> Result<()> {
-        // Look for extension.json or gemini-extension.json
-        let extension_path = dir_path.join("extension.json");
-        let gemini_extension_path = dir_path.join("gemini-extension.json");
-
-        let json_path = if extension_path.exists() {
-            Some(extension_path)
-        } else if gemini_extension_path.exists() {
-            Some(gemini_extension_path)
-        } else {
-            None
-        };
+        let json_path = manifest_in(&dir_path);
+
+        // A monorepo keeps its extensions in subdirectories; import the only
+        // one, or ask which one when there are several
+        if json_path.is_none() {
+            let nested = find_nested_manifests(&dir_path);
+            match nested.as_slice() {
+                [only] => return self.import_from_file(only.clone()),
+                [_, _, ..] => {
+                    let dirs: Vec<String> = nested
+                        .iter()
+                        .filter_map(|path| path.parent()?.strip_prefix(&dir_path).ok())
+                        .map(|dir| dir.to_string_lossy().replace('\\', "/"))
+                        .collect();
+                    self.state = ImportState::Error(format!(
+                        "Found several extensions, choose one of: {}",
+                        dirs.join(", ")
+                    ));
+                    self.state_timestamp = Some(Instant::now());
+                    return Ok(());
+                }
+                [] => {}
+            }
+        }
 
         // Look for context files
         let context_files = self.find_context_files(&dir_path);
@@ -169,6 +330,7 @@ impl ImportDialog {
                     && let Some(name) = path.file_name().and_then(|n| n.to_str())
                     && name.ends_with(".md")
                     && !name.starts_with(".")
+                    && !links_outside(dir_path, &path)
                 {
                     context_files.push((name.to_string(), path));
                 }
@@ -210,7 +372,13 @@ impl ImportDialog {
         self.state = ImportState::Importing;
 
         // Read the context file
-        let context_content = std::fs::read_to_string(&context_path)?;
+        let Some(context_content) = read_text(&context_path)? else {
+            self.state = ImportState::Error(format!(
+                "{context_name} is a binary file, not a text context file"
+            ));
+            self.state_timestamp = Some(Instant::now());
+            return Ok(());
+        };
 
         // Generate a name from the file or directory
         let extension_name = if let Some(dir) = &base_dir {
@@ -237,32 +405,38 @@ impl ImportDialog {
             mcp_servers: HashMap::new(),
             context_file_name: Some(context_name),
             context_content: Some(context_content),
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: Some(context_path.to_string_lossy().to_string()),
                 tags: vec!["context-only".to_string()],
             },
         };
 
-        self.storage.save_extension(&extension)?;
-
-        // Send success message and navigate back
-        if let Some(tx) = &self.action_tx {
-            let _ = tx.send(Action::Success(format!(
-                "Successfully imported context: {extension_name}"
-            )));
-            let _ = tx.send(Action::RefreshExtensions);
-            let _ = tx.send(Action::NavigateBack);
-        }
-
-        Ok(())
+        self.finish_import(
+            extension,
+            format!("Successfully imported context: {extension_name}"),
+        )
     }
 
     fn import_from_file(&mut self, path: PathBuf) -> Result<()> {
         self.state = ImportState::Importing;
 
         // Read the file
-        let content = std::fs::read_to_string(&path)?;
+        let Some(content) = read_text(&path)? else {
+            self.state = ImportState::Error("The manifest is a binary file, not JSON".to_string());
+            self.state_timestamp = Some(Instant::now());
+            return Ok(());
+        };
+
+        // A shared profile rather than an extension manifest
+        if serde_json::from_str::<ProfileExport>(&content).is_ok() {
+            return self.import_profile_export(&path);
+        }
 
         // Parse as import extension first
         match serde_json::from_str::<ImportExtension>(&content) {
@@ -278,19 +452,23 @@ impl ImportDialog {
                     mcp_servers: import_ext.mcp_servers.unwrap_or_default(),
                     context_file_name: import_ext.context_file_name,
                     context_content: import_ext.context_content,
+                    enabled_by_default: import_ext.enabled_by_default,
+                    min_gemini_version: import_ext.min_gemini_version,
+                    platforms: import_ext.platforms,
+                    icon: import_ext.icon,
                     metadata: ExtensionMetadata {
                         imported_at: Utc::now(),
+                        updated_at: None,
                         source_path: Some(path.to_string_lossy().to_string()),
                         tags: import_ext.metadata.and_then(|m| m.tags).unwrap_or_default(),
                     },
                 };
 
+                self.suggested_defaults = import_ext.profile_defaults;
+
                 // Always generate a new ID to avoid conflicts
                 extension.id = uuid::Uuid::new_v4().to_string();
 
-                // Save the extension
-                self.storage.save_extension(&extension)?;
-
                 // Check if there's a context file in the same directory
                 if let Some(parent) = path.parent() {
                     // Look for common context file names
@@ -305,28 +483,26 @@ impl ImportDialog {
                     for name in potential_names {
                         let context_path = parent.join(&name);
                         if context_path.exists()
-                            && let Ok(context_content) = std::fs::read_to_string(&context_path)
+                            && !links_outside(parent, &context_path)
+                            && let Ok(Some(context_content)) = read_text(&context_path)
                         {
-                            // Update extension with context
-                            let mut updated = extension.clone();
                             // Store original filename for reference, but it will be written as GEMINI.md
-                            updated.context_file_name = Some(name.clone());
-                            updated.context_content = Some(context_content);
-                            self.storage.save_extension(&updated)?;
+                            extension.context_file_name = Some(name.clone());
+                            extension.context_content = Some(context_content);
                             break;
                         }
                     }
                 }
 
-                // Send success message and navigate back
-                if let Some(tx) = &self.action_tx {
-                    let _ = tx.send(Action::Success(format!(
-                        "Successfully imported: {}",
-                        extension.name
-                    )));
-                    let _ = tx.send(Action::RefreshExtensions);
-                    let _ = tx.send(Action::NavigateBack);
+                let mut message = format!("Successfully imported: {}", extension.name);
+                let dropped = unrecognized_fields(&content);
+                if !dropped.is_empty() {
+                    message.push_str(&format!(
+                        " (ignored unknown fields: {})",
+                        dropped.join(", ")
+                    ));
                 }
+                self.finish_import(extension, message)?;
             }
             Err(e) => {
                 self.state = ImportState::Error(format!("Failed to parse extension: {e}"));
@@ -336,6 +512,184 @@ impl ImportDialog {
 
         Ok(())
     }
+
+    /// Import a profile written by `export-profile`, or ask what to do if its
+    /// ID is already taken
+    fn import_profile_export(&mut self, path: &Path) -> Result<()> {
+        let mut conflict = None;
+        let result = self.storage.import_profile_file(path, |existing, _| {
+            conflict = Some(existing.name.clone());
+            ConflictResolution::Skip
+        });
+        if let (Ok(_), Some(name)) = (&result, conflict) {
+            self.state = ImportState::ConfirmProfileConflict(name);
+            self.pending_profile = Some(path.to_path_buf());
+            return Ok(());
+        }
+
+        self.finish_profile_import(result);
+        Ok(())
+    }
+
+    /// Import the pending profile export, settling its ID conflict with `resolution`
+    fn resolve_profile_conflict(&mut self, resolution: ConflictResolution) {
+        if let Some(path) = self.pending_profile.take() {
+            self.state = ImportState::Importing;
+            let result = self.storage.import_profile_file(&path, |_, _| resolution);
+            self.finish_profile_import(result);
+        }
+    }
+
+    fn finish_profile_import(&mut self, result: Result<ImportOutcome>) {
+        match result {
+            Ok(outcome) => {
+                let message = match outcome {
+                    ImportOutcome::Imported(id) => format!("Imported profile '{id}'"),
+                    ImportOutcome::Renamed { from, to } => {
+                        format!("Imported profile as '{to}' ('{from}' already exists)")
+                    }
+                    ImportOutcome::Overwritten(id) => format!("Replaced profile '{id}'"),
+                    ImportOutcome::Skipped(id) => {
+                        format!("Skipped profile '{id}': it already exists")
+                    }
+                };
+                if let Some(tx) = &self.action_tx {
+                    let _ = tx.send(Action::RefreshProfiles);
+                }
+                self.notify_imported(message);
+            }
+            Err(e) => {
+                self.state = ImportState::Error(format!("Failed to import profile: {e}"));
+                self.state_timestamp = Some(Instant::now());
+            }
+        }
+    }
+
+    /// Save a freshly imported extension, or ask before replacing one that is
+    /// already installed under another id
+    fn finish_import(&mut self, extension: Extension, message: String) -> Result<()> {
+        if let Some(existing) = self.storage.find_duplicate_extension(&extension)? {
+            self.state = ImportState::ConfirmUpdate(existing.name.clone());
+            self.pending_update = Some((existing, extension));
+            return Ok(());
+        }
+
+        self.storage.save_extension(&extension)?;
+        self.offer_defaults(&extension, message)
+    }
+
+    /// Replace the installed extension with the pending import, keeping its id
+    /// so profiles that reference it pick up the new version
+    fn apply_pending_update(&mut self) -> Result<()> {
+        if let Some((existing, mut extension)) = self.pending_update.take() {
+            self.state = ImportState::Importing;
+            extension.id = existing.id;
+            extension.metadata.imported_at = existing.metadata.imported_at;
+            extension.metadata.updated_at = Some(Utc::now());
+
+            self.storage.save_extension(&extension)?;
+            self.offer_defaults(&extension, format!("Updated extension: {}", extension.name))?;
+        }
+        Ok(())
+    }
+
+    /// Ask before applying the manifest's suggested settings to the default
+    /// profile. Finishes the import straight away when there is nothing to
+    /// suggest, no default profile, or the profile already has it all.
+    fn offer_defaults(&mut self, extension: &Extension, message: String) -> Result<()> {
+        let Some(defaults) = self.suggested_defaults.take() else {
+            self.notify_imported(message);
+            return Ok(());
+        };
+        let Some(mut profile) = self.storage.get_default_profile()? else {
+            self.notify_imported(message);
+            return Ok(());
+        };
+
+        let adds_extension = !profile.extension_ids.contains(&extension.id);
+        let variables = profile.apply_defaults(&extension.id, &defaults);
+        if !adds_extension && variables.is_empty() {
+            self.notify_imported(message);
+            return Ok(());
+        }
+
+        self.state = ImportState::ConfirmDefaults;
+        self.pending_defaults = Some(PendingDefaults {
+            profile,
+            extension_name: extension.name.clone(),
+            adds_extension,
+            variables,
+            message,
+        });
+        Ok(())
+    }
+
+    /// Save or drop the suggested settings, then finish the import
+    fn resolve_defaults(&mut self, apply: bool) -> Result<()> {
+        if let Some(pending) = self.pending_defaults.take() {
+            self.state = ImportState::Importing;
+            let mut message = pending.message;
+            if apply {
+                self.storage.save_profile(&pending.profile)?;
+                message.push_str(&format!(
+                    " (applied suggested settings to {})",
+                    pending.profile.name
+                ));
+                if let Some(tx) = &self.action_tx {
+                    let _ = tx.send(Action::RefreshProfiles);
+                }
+            }
+            self.notify_imported(message);
+        }
+        Ok(())
+    }
+
+    fn notify_imported(&self, message: String) {
+        // Send success message and navigate back
+        if let Some(tx) = &self.action_tx {
+            let _ = tx.send(Action::Success(message));
+            let _ = tx.send(Action::RefreshExtensions);
+            let _ = tx.send(Action::NavigateBack);
+        }
+    }
+
+    /// Test helper method - import a path as if it had been picked in the explorer
+    #[doc(hidden)]
+    #[allow(dead_code)]
+    pub fn import_path(&mut self, path: PathBuf) -> Result<()> {
+        self.import_extension(path)
+    }
+
+    /// Test helper method - the error being shown, if any
+    #[doc(hidden)]
+    #[allow(dead_code)]
+    pub fn error_message(&self) -> Option<&str> {
+        match &self.state {
+            ImportState::Error(msg) => Some(msg),
+            _ => None,
+        }
+    }
+
+    /// Test helper method - whether an update of an installed extension awaits confirmation
+    #[doc(hidden)]
+    #[allow(dead_code)]
+    pub fn is_confirming_update(&self) -> bool {
+        matches!(self.state, ImportState::ConfirmUpdate(_))
+    }
+
+    /// Test helper method - whether suggested profile settings await confirmation
+    #[doc(hidden)]
+    #[allow(dead_code)]
+    pub fn is_confirming_defaults(&self) -> bool {
+        self.state == ImportState::ConfirmDefaults
+    }
+
+    /// Test helper method - whether an imported profile's taken ID awaits a decision
+    #[doc(hidden)]
+    #[allow(dead_code)]
+    pub fn is_confirming_profile_conflict(&self) -> bool {
+        matches!(self.state, ImportState::ConfirmProfileConflict(_))
+    }
 }
 
 impl Component for ImportDialog {
@@ -419,6 +773,108 @@ impl Component for ImportDialog {
                     .alignment(Alignment::Center);
                 frame.render_widget(loading, inner_area);
             }
+            ImportState::ConfirmUpdate(name) => {
+                let chunks = Layout::default()
+                    .direction(Direction::Vertical)
+                    .constraints([
+                        Constraint::Min(0),    // Question
+                        Constraint::Length(3), // Instructions
+                    ])
+                    .split(inner_area);
+
+                let question = Paragraph::new(format!(
+                    "{} '{name}' is already installed.\n\nUpdate it with the imported version?",
+                    Icon::Warning
+                ))
+                .style(
+                    Style::default()
+                        .fg(theme::warning())
+                        .add_modifier(Modifier::BOLD),
+                )
+                .alignment(Alignment::Center)
+                .wrap(Wrap { trim: true });
+                frame.render_widget(question, chunks[0]);
+
+                let instructions = Paragraph::new("y/Enter: Update | n/Esc: Cancel")
+                    .style(Style::default().fg(theme::text_secondary()))
+                    .alignment(Alignment::Center);
+                frame.render_widget(instructions, chunks[1]);
+            }
+            ImportState::ConfirmProfileConflict(name) => {
+                let chunks = Layout::default()
+                    .direction(Direction::Vertical)
+                    .constraints([
+                        Constraint::Min(0),    // Question
+                        Constraint::Length(3), // Instructions
+                    ])
+                    .split(inner_area);
+
+                let question = Paragraph::new(format!(
+                    "{} Profile '{name}' already uses this ID.\n\nImport it under a new ID, replace '{name}', or skip it?",
+                    Icon::Warning
+                ))
+                .style(
+                    Style::default()
+                        .fg(theme::warning())
+                        .add_modifier(Modifier::BOLD),
+                )
+                .alignment(Alignment::Center)
+                .wrap(Wrap { trim: true });
+                frame.render_widget(question, chunks[0]);
+
+                let instructions =
+                    Paragraph::new("r/Enter: Rename | o: Overwrite | s: Skip | Esc: Cancel")
+                        .style(Style::default().fg(theme::text_secondary()))
+                        .alignment(Alignment::Center);
+                frame.render_widget(instructions, chunks[1]);
+            }
+            ImportState::ConfirmDefaults => {
+                let Some(pending) = &self.pending_defaults else {
+                    return Ok(());
+                };
+                let chunks = Layout::default()
+                    .direction(Direction::Vertical)
+                    .constraints([
+                        Constraint::Min(0),    // Suggestions
+                        Constraint::Length(3), // Instructions
+                    ])
+                    .split(inner_area);
+
+                let mut lines = vec![
+                    Line::from(Span::styled(
+                        format!(
+                            "'{}' suggests settings for your default profile '{}':",
+                            pending.extension_name, pending.profile.name
+                        ),
+                        Style::default()
+                            .fg(theme::primary())
+                            .add_modifier(Modifier::BOLD),
+                    )),
+                    Line::from(""),
+                ];
+                if pending.adds_extension {
+                    lines.push(Line::from(format!("Include {}", pending.extension_name)));
+                }
+                for key in &pending.variables {
+                    lines.push(Line::from(format!(
+                        "{key}={}",
+                        pending.profile.environment_variables[key]
+                    )));
+                }
+                lines.push(Line::from(""));
+                lines.push(Line::from("Apply them to the profile?"));
+
+                let suggestions = Paragraph::new(lines)
+                    .style(Style::default().fg(theme::text_primary()))
+                    .alignment(Alignment::Center)
+                    .wrap(Wrap { trim: true });
+                frame.render_widget(suggestions, chunks[0]);
+
+                let instructions = Paragraph::new("y/Enter: Apply | n/Esc: Skip")
+                    .style(Style::default().fg(theme::text_secondary()))
+                    .alignment(Alignment::Center);
+                frame.render_widget(instructions, chunks[1]);
+            }
             ImportState::Error(msg) => {
                 // Split area for error message and instructions
                 let chunks = Layout::default()
@@ -429,7 +885,7 @@ impl Component for ImportDialog {
                     ])
                     .split(inner_area);
 
-                let error = Paragraph::new(format!("✗ {msg}"))
+                let error = Paragraph::new(format!("{} {msg}", Icon::Error))
                     .style(
                         Style::default()
                             .fg(theme::error())
@@ -493,6 +949,47 @@ impl Component for ImportDialog {
                         }
                     }
                 }
+                ImportState::ConfirmUpdate(_) => match key.code {
+                    KeyCode::Char('y') | KeyCode::Enter => {
+                        if let Err(e) = self.apply_pending_update() {
+                            self.state = ImportState::Error(e.to_string());
+                            self.state_timestamp = Some(Instant::now());
+                        }
+                    }
+                    KeyCode::Char('n') | KeyCode::Esc => {
+                        self.pending_update = None;
+                        self.state = ImportState::Selecting;
+                        self.state_timestamp = None;
+                    }
+                    _ => {}
+                },
+                ImportState::ConfirmProfileConflict(_) => {
+                    let resolution = match key.code {
+                        KeyCode::Char('r') | KeyCode::Enter => ConflictResolution::Rename,
+                        KeyCode::Char('o') => ConflictResolution::Overwrite,
+                        KeyCode::Char('s') => ConflictResolution::Skip,
+                        KeyCode::Esc => {
+                            self.pending_profile = None;
+                            self.state = ImportState::Selecting;
+                            self.state_timestamp = None;
+                            return Ok(None);
+                        }
+                        _ => return Ok(None),
+                    };
+                    self.resolve_profile_conflict(resolution);
+                }
+                ImportState::ConfirmDefaults => {
+                    // The extension is saved either way; only the profile waits on the answer
+                    let apply = match key.code {
+                        KeyCode::Char('y') | KeyCode::Enter => true,
+                        KeyCode::Char('n') | KeyCode::Esc => false,
+                        _ => return Ok(None),
+                    };
+                    if let Err(e) = self.resolve_defaults(apply) {
+                        self.state = ImportState::Error(e.to_string());
+                        self.state_timestamp = Some(Instant::now());
+                    }
+                }
                 ImportState::Error(_) => {
                     // Any key press returns to selecting state
                     self.state = ImportState::Selecting;
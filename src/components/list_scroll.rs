@@ -0,0 +1,66 @@
+use crate::icons::Icon;
+
+/// Scroll position of a list of cards that may each take several rows.
+///
+/// Ratatui's `List` keeps the selection visible on its own, but only while
+/// its state lives across frames, and it can't say what it left out. This
+/// keeps the first shown card between draws, moves it as little as possible
+/// to keep the cursor in view, and counts the cards out of view.
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq)]
+pub struct ListScroll {
+    offset: usize, // Index of the first card shown
+}
+
+impl ListScroll {
+    /// Index of the first card shown
+    #[allow(dead_code)]
+    pub fn offset(&self) -> usize {
+        self.offset
+    }
+
+    /// Scroll so the `selected` card fits in `viewport` rows, given each
+    /// card's height. Returns the index of the first card to show.
+    pub fn follow(&mut self, heights: &[usize], selected: usize, viewport: usize) -> usize {
+        let Some(last) = heights.len().checked_sub(1) else {
+            self.offset = 0;
+            return 0;
+        };
+        let selected = selected.min(last);
+
+        self.offset = self.offset.min(selected);
+        while self.offset < selected
+            && heights[self.offset..=selected].iter().sum::<usize>() > viewport
+        {
+            self.offset += 1;
+        }
+        self.offset
+    }
+
+    /// How many cards are out of view above and below `viewport` rows
+    pub fn hidden(&self, heights: &[usize], viewport: usize) -> (usize, usize) {
+        let offset = self.offset.min(heights.len());
+        let mut used = 0;
+        let mut shown = 0;
+        for height in &heights[offset..] {
+            // The first card is shown even when it doesn't fit
+            if shown > 0 && used + height > viewport {
+                break;
+            }
+            used += height;
+            shown += 1;
+        }
+        (offset, heights.len() - offset - shown)
+    }
+
+    /// A note such as "↑ 2 more · ↓ 12 more" for the cards out of view, or
+    /// `None` when every card is shown
+    pub fn hint(&self, heights: &[usize], viewport: usize) -> Option<String> {
+        let (above, below) = self.hidden(heights, viewport);
+        let parts: Vec<String> = [(above, Icon::MoreAbove), (below, Icon::MoreBelow)]
+            .into_iter()
+            .filter(|(count, _)| *count > 0)
+            .map(|(count, icon)| format!("{icon} {count} more"))
+            .collect();
+        (!parts.is_empty()).then(|| parts.join(" · "))
+    }
+}
@@ -1,24 +1,46 @@
+use std::sync::{Arc, RwLock};
+
 use color_eyre::Result;
 use ratatui::{prelude::*, widgets::*};
 use tokio::sync::mpsc::UnboundedSender;
 
-use super::Component;
+use super::{
+    Component,
+    badge::{BadgeKind, ChipRow},
+    settings_view::UserSettings,
+};
 use crate::{
     action::Action,
     config::Config,
+    icons::Icon,
+    launcher::{LaunchPlan, Launcher, environment_preview, secret_references},
     models::{Extension, Profile},
     storage::Storage,
     theme,
+    utils::clipboard::{Clipboard, Osc52Clipboard},
 };
 
 #[derive(Default)]
 pub struct ProfileDetail {
     command_tx: Option<UnboundedSender<Action>>,
     config: Config,
+    settings: Option<Arc<RwLock<UserSettings>>>,
     storage: Option<Storage>,
     profile: Option<Profile>,
+    resolved: Option<Profile>,  // `profile` with what it inherits merged in
+    overridden: Vec<String>,    // Inherited variables the profile replaces
     extensions: Vec<Extension>, // Full extension data for display
     scroll_offset: u16,
+    clipboard: Option<Box<dyn Clipboard>>, // Defaults to OSC 52 when unset
+    launch_review: Option<LaunchReview>,   // Shown instead of the details while open
+    launch_plan: Option<LaunchPlan>,       // Likewise
+}
+
+/// Pre-launch checklist of the profile's extensions
+#[derive(Default)]
+struct LaunchReview {
+    cursor: usize,
+    skipped: Vec<String>, // Extension IDs unchecked for this launch
 }
 
 impl ProfileDetail {
@@ -39,9 +61,17 @@ impl ProfileDetail {
     }
 
     pub fn set_profile(&mut self, profile: Profile) {
+        // Show what a launch would use: inherited extensions and variables
+        // included. A broken inheritance chain is reported when launching.
+        let (resolved, overridden) = self
+            .storage
+            .as_ref()
+            .and_then(|storage| storage.resolve_with_overrides(profile.clone()).ok())
+            .unwrap_or_else(|| (profile.clone(), Vec::new()));
+
         // Load the extensions from storage
         if let Some(storage) = &self.storage {
-            self.extensions = profile
+            self.extensions = resolved
                 .extension_ids
                 .iter()
                 .filter_map(|ext_id| storage.load_extension(ext_id).ok())
@@ -51,7 +81,30 @@ impl ProfileDetail {
         }
 
         self.profile = Some(profile);
+        self.resolved = Some(resolved);
+        self.overridden = overridden;
         self.scroll_offset = 0;
+        self.launch_review = None;
+        self.launch_plan = None;
+    }
+
+    /// A launcher for this view's storage, using the Gemini CLI and
+    /// extensions directory set in Settings
+    fn launcher(&self) -> Launcher {
+        let (gemini_cli, extensions_dir) = self
+            .settings
+            .as_ref()
+            .and_then(|settings| {
+                let settings = settings.read().ok()?;
+                Some((
+                    settings.gemini_cli_path.clone(),
+                    settings.gemini_extensions_dir.clone(),
+                ))
+            })
+            .unwrap_or_default();
+        Launcher::with_storage(self.storage.clone().unwrap_or_default())
+            .with_gemini_cli(gemini_cli.as_deref())
+            .with_extensions_dir(extensions_dir.as_deref())
     }
 
     fn scroll_up(&mut self) {
@@ -63,6 +116,220 @@ impl ProfileDetail {
     fn scroll_down(&mut self) {
         self.scroll_offset = self.scroll_offset.saturating_add(1);
     }
+
+    /// Override the clipboard used for copy actions
+    #[allow(dead_code)]
+    pub fn set_clipboard(&mut self, clipboard: Box<dyn Clipboard>) {
+        self.clipboard = Some(clipboard);
+    }
+
+    /// Keys while the launch review is open. Enter or l launches without the
+    /// unchecked extensions; the profile itself is never changed.
+    fn handle_review_key(&mut self, code: crossterm::event::KeyCode) -> Option<Action> {
+        use crossterm::event::KeyCode;
+
+        let review = self.launch_review.as_mut()?;
+        match code {
+            KeyCode::Up | KeyCode::Char('k') => {
+                review.cursor = review.cursor.saturating_sub(1);
+                Some(Action::Render)
+            }
+            KeyCode::Down | KeyCode::Char('j') => {
+                if review.cursor + 1 < self.extensions.len() {
+                    review.cursor += 1;
+                }
+                Some(Action::Render)
+            }
+            KeyCode::Char(' ') => {
+                let id = &self.extensions.get(review.cursor)?.id;
+                if let Some(pos) = review.skipped.iter().position(|s| s == id) {
+                    review.skipped.remove(pos);
+                } else {
+                    review.skipped.push(id.clone());
+                }
+                Some(Action::Render)
+            }
+            KeyCode::Enter | KeyCode::Char('l') => {
+                let skipped = self.launch_review.take()?.skipped;
+                let profile_id = self.profile.as_ref()?.id.clone();
+                Some(if skipped.is_empty() {
+                    Action::LaunchWithProfile(profile_id)
+                } else {
+                    Action::LaunchWithout(profile_id, skipped)
+                })
+            }
+            KeyCode::Char('b') | KeyCode::Esc => {
+                self.launch_review = None;
+                Some(Action::Render)
+            }
+            KeyCode::Char('q') => Some(Action::Quit),
+            _ => None,
+        }
+    }
+
+    /// Work out what launching the profile would do, with everything it
+    /// inherits, and show it in place of the details
+    fn open_launch_plan(&mut self) -> Option<Action> {
+        let profile = self.profile.as_ref()?;
+        let storage = self.storage.clone().unwrap_or_default();
+        let plan = storage
+            .resolve_profile(&profile.id)
+            .and_then(|profile| self.launcher().plan(&profile));
+
+        match plan {
+            Ok(plan) => {
+                self.launch_plan = Some(plan);
+                self.scroll_offset = 0;
+                Some(Action::Render)
+            }
+            Err(e) => Some(Action::Error(format!("Failed to plan launch: {e}"))),
+        }
+    }
+
+    /// Keys while the launch plan is shown
+    fn handle_plan_key(&mut self, code: crossterm::event::KeyCode) -> Option<Action> {
+        use crossterm::event::KeyCode;
+
+        match code {
+            KeyCode::Up | KeyCode::Char('k') => {
+                self.scroll_up();
+                Some(Action::Render)
+            }
+            KeyCode::Down | KeyCode::Char('j') => {
+                self.scroll_down();
+                Some(Action::Render)
+            }
+            KeyCode::Char('p') | KeyCode::Char('b') | KeyCode::Esc => {
+                self.launch_plan = None;
+                self.scroll_offset = 0;
+                Some(Action::Render)
+            }
+            KeyCode::Char('q') => Some(Action::Quit),
+            _ => None,
+        }
+    }
+
+    /// Lines of the launch plan: the command, where it runs, what gets
+    /// installed, and the environment with secrets masked
+    fn plan_lines(plan: &LaunchPlan) -> Vec<Line<'static>> {
+        let heading = |text: &str| {
+            Line::from(Span::styled(
+                text.to_string(),
+                Style::default()
+                    .fg(theme::info())
+                    .add_modifier(Modifier::BOLD | Modifier::UNDERLINED),
+            ))
+        };
+        let plain = |text: String| {
+            Line::from(Span::styled(
+                text,
+                Style::default().fg(theme::text_primary()),
+            ))
+        };
+
+        let command = std::iter::once(plan.program.display().to_string())
+            .chain(plan.args.iter().cloned())
+            .collect::<Vec<_>>()
+            .join(" ");
+        let mut lines = vec![
+            heading("Command"),
+            plain(format!("  {command}")),
+            Line::from(""),
+            heading("Working Directory"),
+            plain(format!("  {}", plan.working_dir.display())),
+            Line::from(""),
+            heading("Extensions to Install"),
+        ];
+
+        if plan.installs.is_empty() && plan.missing.is_empty() {
+            lines.push(Line::from(Span::styled(
+                "  None",
+                Style::default().fg(theme::text_muted()),
+            )));
+        }
+        for (id, dir) in &plan.installs {
+            lines.push(plain(format!("  {id} → {}", dir.display())));
+        }
+        for id in &plan.missing {
+            lines.push(Line::from(Span::styled(
+                format!("  {} {id}: not found, skipped", Icon::Warning),
+                Style::default().fg(theme::warning()),
+            )));
+        }
+        lines.push(Line::from(""));
+
+        lines.push(heading(&format!(
+            "Environment ({} variables)",
+            plan.environment.len()
+        )));
+        for (key, value) in environment_preview(&plan.environment, &plan.secret_references) {
+            lines.push(Line::from(vec![
+                Span::styled(format!("  {key}"), Style::default().fg(theme::highlight())),
+                Span::styled(" = ", Style::default().fg(theme::text_secondary())),
+                Span::styled(value, Style::default().fg(theme::text_primary())),
+            ]));
+        }
+        lines
+    }
+
+    /// Lines of the launch review checklist
+    fn review_lines(&self, review: &LaunchReview) -> Vec<Line<'_>> {
+        let mut lines = vec![
+            Line::from(Span::styled(
+                "Uncheck extensions to leave them out of this launch only.",
+                Style::default().fg(theme::text_secondary()),
+            )),
+            Line::from(""),
+        ];
+        if self.extensions.is_empty() {
+            lines.push(Line::from(Span::styled(
+                "No extensions in this profile",
+                Style::default().fg(theme::text_muted()),
+            )));
+        }
+        for (i, ext) in self.extensions.iter().enumerate() {
+            let checkbox = if review.skipped.contains(&ext.id) {
+                "[ ]"
+            } else {
+                "[x]"
+            };
+            let style = if i == review.cursor {
+                Style::default()
+                    .bg(theme::selection())
+                    .add_modifier(Modifier::BOLD)
+            } else {
+                Style::default().fg(theme::text_primary())
+            };
+            lines.push(Line::from(Span::styled(
+                format!("{checkbox} {} v{}", ext.name, ext.version),
+                style,
+            )));
+        }
+        lines
+    }
+
+    /// Copy the shell command that reproduces this profile's launch
+    fn copy_launch_command(&mut self) -> Option<Action> {
+        let profile = self.resolved.as_ref()?;
+        let command = match self.launcher().command_line(profile) {
+            Ok(command) => command,
+            Err(e) => {
+                return Some(Action::Error(format!(
+                    "Failed to build launch command: {e}"
+                )));
+            }
+        };
+
+        let result = match &mut self.clipboard {
+            Some(clipboard) => clipboard.set_text(&command),
+            None => Osc52Clipboard.set_text(&command),
+        };
+
+        Some(match result {
+            Ok(()) => Action::Success("Launch command copied to clipboard".to_string()),
+            Err(e) => Action::Error(format!("Failed to copy launch command: {e}")),
+        })
+    }
 }
 
 impl Component for ProfileDetail {
@@ -76,6 +343,11 @@ impl Component for ProfileDetail {
         Ok(())
     }
 
+    fn register_settings_handler(&mut self, settings: Arc<RwLock<UserSettings>>) -> Result<()> {
+        self.settings = Some(settings);
+        Ok(())
+    }
+
     fn update(&mut self, action: Action) -> Result<Option<Action>> {
         match action {
             Action::ViewProfileDetails(id) => {
@@ -136,6 +408,56 @@ impl Component for ProfileDetail {
 
         let inner_area = block.inner(chunks[0]);
 
+        if let Some(review) = &self.launch_review {
+            let block = block.title_bottom(" Launch review ");
+            frame.render_widget(block, chunks[0]);
+            frame.render_widget(Paragraph::new(self.review_lines(review)), inner_area);
+
+            use crate::utils::build_help_text;
+            let help_text = build_help_text(&[
+                ("up", "Move"),
+                ("down", "Move"),
+                ("Space", "Include/skip"),
+                ("launch", "Launch"),
+                ("back", "Cancel"),
+            ]);
+            let help_bar = Paragraph::new(help_text)
+                .style(Style::default().fg(theme::text_muted()))
+                .alignment(Alignment::Center)
+                .block(
+                    Block::default()
+                        .borders(Borders::ALL)
+                        .border_type(BorderType::Rounded),
+                );
+            frame.render_widget(help_bar, chunks[1]);
+            return Ok(());
+        }
+
+        if let Some(plan) = &self.launch_plan {
+            let block = block.title_bottom(" Launch plan ");
+            frame.render_widget(block, chunks[0]);
+            let paragraph = Paragraph::new(Self::plan_lines(plan)).scroll((self.scroll_offset, 0));
+            frame.render_widget(paragraph, inner_area);
+
+            use crate::utils::build_help_text;
+            let help_text = build_help_text(&[
+                ("up", "Scroll"),
+                ("down", "Scroll"),
+                ("p", "Close plan"),
+                ("back", "Close plan"),
+            ]);
+            let help_bar = Paragraph::new(help_text)
+                .style(Style::default().fg(theme::text_muted()))
+                .alignment(Alignment::Center)
+                .block(
+                    Block::default()
+                        .borders(Borders::ALL)
+                        .border_type(BorderType::Rounded),
+                );
+            frame.render_widget(help_bar, chunks[1]);
+            return Ok(());
+        }
+
         // Build content
         let mut content = vec![];
 
@@ -184,18 +506,14 @@ impl Component for ProfileDetail {
 
         // Tags
         if !profile.metadata.tags.is_empty() {
-            content.push(Line::from(vec![
-                Span::styled(
-                    "Tags: ",
-                    Style::default()
-                        .fg(theme::highlight())
-                        .add_modifier(Modifier::BOLD),
-                ),
-                Span::styled(
-                    profile.metadata.tags.join(", "),
-                    Style::default().fg(theme::accent()),
-                ),
-            ]));
+            let mut tags = vec![Span::styled(
+                "Tags: ",
+                Style::default()
+                    .fg(theme::highlight())
+                    .add_modifier(Modifier::BOLD),
+            )];
+            tags.extend(ChipRow::new(&profile.metadata.tags, BadgeKind::Info).spans());
+            content.push(Line::from(tags));
         }
 
         // Working directory
@@ -273,9 +591,9 @@ impl Component for ProfileDetail {
                         Span::styled(
                             format!(
                                 "MCP Servers: {}",
-                                ext.mcp_servers
-                                    .keys()
-                                    .cloned()
+                                ext.sorted_mcp_servers()
+                                    .into_iter()
+                                    .map(|(name, _)| name.as_str())
                                     .collect::<Vec<_>>()
                                     .join(", ")
                             ),
@@ -335,41 +653,79 @@ impl Component for ProfileDetail {
             ),
         ]));
 
+        // Only shown when on, to keep the section short
+        if profile.launch_config.clean_environment {
+            content.push(Line::from(vec![
+                Span::styled(
+                    "  Clean Environment: ",
+                    Style::default().fg(theme::text_primary()),
+                ),
+                Span::styled(
+                    format!(
+                        "Yes (passes {})",
+                        profile.launch_config.env_allowlist.join(", ")
+                    ),
+                    Style::default().fg(theme::success()),
+                ),
+            ]));
+        }
+
         content.push(Line::from(""));
 
-        // Environment Variables section
-        if !profile.environment_variables.is_empty() {
+        // Environment section: what Gemini gets from the profile once `$VAR`
+        // references are expanded, with secrets masked, including ones pulled
+        // in by reference. The variables the manager adds come after the
+        // profile's own.
+        let launcher = Launcher::with_storage(self.storage.clone().unwrap_or_default());
+        let effective = self.resolved.as_ref().unwrap_or(profile);
+        let shadowed = launcher.shadowed_variables(effective, std::env::vars());
+        let mut resolved = launcher.profile_environment(effective);
+        let manager_vars: Vec<(String, String)> = resolved
+            .remove_entry("GEMINI_PROFILE")
+            .into_iter()
+            .collect();
+        let secret_refs = secret_references(&effective.environment_variables);
+        let environment = environment_preview(&resolved, &secret_refs)
+            .into_iter()
+            .chain(manager_vars);
+        content.push(Line::from(Span::styled(
+            "Environment Variables (resolved)",
+            Style::default()
+                .fg(theme::info())
+                .add_modifier(Modifier::BOLD | Modifier::UNDERLINED),
+        )));
+        content.push(Line::from(""));
+
+        if !shadowed.is_empty() {
             content.push(Line::from(Span::styled(
-                "Environment Variables",
-                Style::default()
-                    .fg(theme::info())
-                    .add_modifier(Modifier::BOLD | Modifier::UNDERLINED),
+                format!(
+                    "  {} Overrides inherited: {}",
+                    Icon::Warning,
+                    shadowed.join(", ")
+                ),
+                Style::default().fg(theme::warning()),
             )));
-            content.push(Line::from(""));
-
-            for (key, value) in &profile.environment_variables {
-                // Mask sensitive values
-                let display_value =
-                    if key.contains("TOKEN") || key.contains("KEY") || key.contains("SECRET") {
-                        let len = value.len();
-                        if len > 8 {
-                            format!("{}...{}", &value[..4], &value[len - 4..])
-                        } else {
-                            "***".to_string()
-                        }
-                    } else {
-                        value.clone()
-                    };
+        }
+        if !self.overridden.is_empty() {
+            content.push(Line::from(Span::styled(
+                format!(
+                    "  {} Overrides parent profile: {}",
+                    Icon::Warning,
+                    self.overridden.join(", ")
+                ),
+                Style::default().fg(theme::warning()),
+            )));
+        }
 
-                content.push(Line::from(vec![
-                    Span::styled("  ", Style::default().fg(theme::text_primary())),
-                    Span::styled(key, Style::default().fg(theme::highlight())),
-                    Span::styled(" = ", Style::default().fg(theme::text_secondary())),
-                    Span::styled(display_value, Style::default().fg(theme::text_primary())),
-                ]));
-            }
-            content.push(Line::from(""));
+        for (key, value) in environment {
+            content.push(Line::from(vec![
+                Span::styled("  ", Style::default().fg(theme::text_primary())),
+                Span::styled(key, Style::default().fg(theme::highlight())),
+                Span::styled(" = ", Style::default().fg(theme::text_secondary())),
+                Span::styled(value, Style::default().fg(theme::text_primary())),
+            ]));
         }
+        content.push(Line::from(""));
 
         // Summary
         content.push(Line::from(Span::styled(
@@ -414,9 +770,13 @@ impl Component for ProfileDetail {
             ("up", "Scroll"),
             ("down", "Scroll"),
             ("launch", "Launch"),
+            ("r", "Review launch"),
             ("edit", "Edit"),
             ("delete", "Delete"),
             ("x", "Set default"),
+            ("y", "Copy command"),
+            ("p", "Launch plan"),
+            ("g", "Summary"),
             ("back", "Back"),
             ("quit", "Quit"),
         ]);
@@ -437,6 +797,17 @@ impl Component for ProfileDetail {
     fn handle_events(&mut self, event: Option<crate::tui::Event>) -> Result<Option<Action>> {
         use crossterm::event::KeyCode;
 
+        if let Some(crate::tui::Event::Key(key)) = &event
+            && self.launch_review.is_some()
+        {
+            return Ok(self.handle_review_key(key.code));
+        }
+        if let Some(crate::tui::Event::Key(key)) = &event
+            && self.launch_plan.is_some()
+        {
+            return Ok(self.handle_plan_key(key.code));
+        }
+
         match event {
             Some(crate::tui::Event::Key(key)) => match key.code {
                 KeyCode::Up | KeyCode::Char('k') => {
@@ -477,6 +848,19 @@ impl Component for ProfileDetail {
                     // TODO: Set default profile action not implemented
                     Ok(None)
                 }
+                KeyCode::Char('r') => {
+                    if self.profile.is_none() {
+                        return Ok(None);
+                    }
+                    self.launch_review = Some(LaunchReview::default());
+                    Ok(Some(Action::Render))
+                }
+                KeyCode::Char('y') => Ok(self.copy_launch_command()),
+                KeyCode::Char('p') => Ok(self.open_launch_plan()),
+                KeyCode::Char('g') => Ok(self
+                    .profile
+                    .as_ref()
+                    .map(|profile| Action::PreviewConfig(profile.id.clone()))),
                 KeyCode::Char('q') => Ok(Some(Action::Quit)),
                 _ => Ok(None),
             },
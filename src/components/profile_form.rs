@@ -1,18 +1,19 @@
 use chrono::Utc;
-use color_eyre::Result;
+use color_eyre::{Result, eyre::eyre};
 use ratatui::{prelude::*, widgets::*};
 use std::collections::HashMap;
 use tokio::sync::mpsc::UnboundedSender;
 use tui_input::Input;
 use tui_input::backend::crossterm::EventHandler;
 
-use super::Component;
+use super::{Component, icon_picker::IconPicker};
 use crate::{
     action::Action,
     config::Config,
+    icons::Icon,
     models::{
         Extension, Profile,
-        profile::{LaunchConfig, ProfileMetadata},
+        profile::{LaunchConfig, ProfileMetadata, default_env_allowlist, profile_id_from_name},
     },
     storage::Storage,
     theme,
@@ -43,7 +44,13 @@ pub struct ProfileForm {
     // Launch configuration
     clean_launch: bool,
     cleanup_on_exit: bool,
-    launch_config_cursor: usize, // 0 = clean_launch, 1 = cleanup_on_exit
+    clean_environment: bool,
+    env_allowlist: Vec<String>,
+    launch_config_cursor: usize, // 0 = clean_launch, 1 = cleanup_on_exit, 2 = clean_environment
+
+    // Profile icon and the picker used to choose it
+    icon: Option<String>,
+    icon_picker: Option<IconPicker>,
 
     // Available extensions
     available_extensions: Vec<Extension>,
@@ -57,9 +64,24 @@ pub struct ProfileForm {
     edit_profile_id: Option<String>,
 }
 
+/// Checkbox prefix for list and toggle rows
+fn checkbox(checked: bool) -> String {
+    if checked {
+        format!("[{}] ", Icon::Checked)
+    } else {
+        "[ ] ".to_string()
+    }
+}
+
 impl ProfileForm {
     pub fn new(storage: Storage) -> Self {
         let available_extensions = storage.list_extensions().unwrap_or_default();
+        // New profiles start with the extensions that ask to be on by default
+        let selected_extensions = available_extensions
+            .iter()
+            .filter(|ext| ext.enabled_by_default)
+            .map(|ext| ext.id.clone())
+            .collect();
 
         Self {
             command_tx: None,
@@ -69,10 +91,14 @@ impl ProfileForm {
             description_input: Input::default(),
             working_directory_input: Input::default(),
             tags_input: Input::default(),
-            selected_extensions: Vec::new(),
+            selected_extensions,
             clean_launch: false,
             cleanup_on_exit: true, // Default to cleaning up
+            clean_environment: false,
+            env_allowlist: default_env_allowlist(),
             launch_config_cursor: 0,
+            icon: None,
+            icon_picker: None,
             available_extensions,
             extension_cursor: 0,
             current_field: FormField::Name,
@@ -101,7 +127,11 @@ impl ProfileForm {
             selected_extensions: profile.extension_ids.clone(),
             clean_launch: profile.launch_config.clean_launch,
             cleanup_on_exit: profile.launch_config.cleanup_on_exit,
+            clean_environment: profile.launch_config.clean_environment,
+            env_allowlist: profile.launch_config.env_allowlist.clone(),
             launch_config_cursor: 0,
+            icon: profile.metadata.icon.clone(),
+            icon_picker: None,
             available_extensions,
             extension_cursor: 0,
             current_field: FormField::Name,
@@ -114,28 +144,7 @@ impl ProfileForm {
         let profile_id = if let Some(id) = &self.edit_profile_id {
             id.clone()
         } else {
-            // Generate a simple ID from the name
-            // Replace spaces with hyphens and remove special characters
-            self.name_input
-                .value()
-                .to_lowercase()
-                .chars()
-                .map(|c| {
-                    if c.is_alphanumeric() {
-                        c
-                    } else if c == ' ' || c == '-' || c == '_' || c == '.' {
-                        '-'
-                    } else {
-                        // Remove other special characters
-                        '\0'
-                    }
-                })
-                .filter(|c| *c != '\0')
-                .collect::<String>()
-                .split('-')
-                .filter(|s| !s.is_empty())
-                .collect::<Vec<_>>()
-                .join("-")
+            profile_id_from_name(self.name_input.value()).map_err(|e| eyre!(e))?
         };
 
         let tags: Vec<String> = self
@@ -154,6 +163,12 @@ impl ProfileForm {
             } else {
                 Some(self.description_input.value().to_string())
             },
+            // The form has no field for this, so keep what the file says
+            inherits: self
+                .edit_profile_id
+                .as_ref()
+                .and_then(|id| self.storage.load_profile(id).ok())
+                .and_then(|p| p.inherits),
             extension_ids: self.selected_extensions.clone(),
             environment_variables: HashMap::new(), // TODO: Add env var editor
             working_directory: if self.working_directory_input.value().is_empty() {
@@ -164,6 +179,8 @@ impl ProfileForm {
             launch_config: LaunchConfig {
                 clean_launch: self.clean_launch,
                 cleanup_on_exit: self.cleanup_on_exit,
+                clean_environment: self.clean_environment,
+                env_allowlist: self.env_allowlist.clone(),
             },
             metadata: ProfileMetadata {
                 created_at: if self.edit_mode {
@@ -178,7 +195,7 @@ impl ProfileForm {
                 updated_at: Utc::now(),
                 tags,
                 is_default: false,
-                icon: None,
+                icon: self.icon.clone(),
             },
         };
 
@@ -278,8 +295,12 @@ impl Component for ProfileForm {
         } else {
             Style::default().fg(theme::text_secondary())
         };
+        let name_title = match &self.icon {
+            Some(icon) => format!("Name {icon}"),
+            None => "Name".to_string(),
+        };
         let name_block = Block::default()
-            .title("Name")
+            .title(name_title)
             .borders(Borders::ALL)
             .border_style(name_style);
         frame.render_widget(name_block.clone(), chunks[0]);
@@ -361,7 +382,7 @@ impl Component for ProfileForm {
                 let is_cursor = i == self.extension_cursor
                     && matches!(self.current_field, FormField::Extensions);
 
-                let prefix = if is_selected { "[✓] " } else { "[ ] " };
+                let prefix = checkbox(is_selected);
                 let style = if is_cursor {
                     Style::default()
                         .bg(theme::selection())
@@ -432,10 +453,7 @@ impl Component for ProfileForm {
             Style::default().fg(theme::text_primary())
         };
         launch_config_lines.push(Line::from(vec![
-            Span::styled(
-                if self.clean_launch { "[✓] " } else { "[ ] " },
-                clean_launch_style,
-            ),
+            Span::styled(checkbox(self.clean_launch), clean_launch_style),
             Span::styled("Clean Launch", clean_launch_style),
             Span::styled(
                 " - Remove existing configuration before starting",
@@ -456,14 +474,7 @@ impl Component for ProfileForm {
             Style::default().fg(theme::text_primary())
         };
         launch_config_lines.push(Line::from(vec![
-            Span::styled(
-                if self.cleanup_on_exit {
-                    "[✓] "
-                } else {
-                    "[ ] "
-                },
-                cleanup_style,
-            ),
+            Span::styled(checkbox(self.cleanup_on_exit), cleanup_style),
             Span::styled("Cleanup on Exit", cleanup_style),
             Span::styled(
                 " - Remove extensions after Gemini exits",
@@ -471,6 +482,27 @@ impl Component for ProfileForm {
             ),
         ]));
 
+        // Clean environment option
+        let clean_env_style = if matches!(self.current_field, FormField::LaunchConfig)
+            && self.launch_config_cursor == 2
+        {
+            Style::default()
+                .bg(theme::selection())
+                .fg(theme::text_primary())
+        } else if self.clean_environment {
+            Style::default().fg(theme::success())
+        } else {
+            Style::default().fg(theme::text_primary())
+        };
+        launch_config_lines.push(Line::from(vec![
+            Span::styled(checkbox(self.clean_environment), clean_env_style),
+            Span::styled("Clean Environment", clean_env_style),
+            Span::styled(
+                format!(" - Only pass through {}", self.env_allowlist.join(", ")),
+                Style::default().fg(theme::text_muted()),
+            ),
+        ]));
+
         let launch_config_paragraph = Paragraph::new(launch_config_lines);
         frame.render_widget(launch_config_paragraph, launch_config_inner);
 
@@ -495,6 +527,7 @@ impl Component for ProfileForm {
             _ => build_help_text(&[
                 ("tab", "Next field"),
                 ("Type", "Edit"),
+                ("Ctrl+P", "Icon"),
                 ("Ctrl+S", "Save"),
                 ("back", "Cancel"),
             ]),
@@ -507,17 +540,42 @@ impl Component for ProfileForm {
             chunks[6],
         );
 
+        // The icon picker floats over the form while it is open
+        if let Some(picker) = &mut self.icon_picker {
+            picker.draw(frame, area)?;
+        }
+
         Ok(())
     }
 
     fn handle_events(&mut self, event: Option<crate::tui::Event>) -> Result<Option<Action>> {
         use crossterm::event::{KeyCode, KeyModifiers};
 
+        // The icon picker takes every key while it is open
+        if let Some(picker) = &mut self.icon_picker {
+            return Ok(match picker.handle_events(event)? {
+                Some(Action::IconPicked(icon)) => {
+                    self.icon = icon;
+                    self.icon_picker = None;
+                    Some(Action::Render)
+                }
+                Some(Action::NavigateBack) => {
+                    self.icon_picker = None;
+                    Some(Action::Render)
+                }
+                other => other,
+            });
+        }
+
         if let Some(crate::tui::Event::Key(key)) = event {
             match (key.code, key.modifiers) {
                 (KeyCode::Esc, _) => {
                     return Ok(Some(Action::NavigateBack));
                 }
+                (KeyCode::Char('p'), KeyModifiers::CONTROL) => {
+                    self.icon_picker = Some(IconPicker::with_selected(self.icon.as_deref()));
+                    return Ok(Some(Action::Render));
+                }
                 (KeyCode::Char('s'), KeyModifiers::CONTROL) => {
                     // Save profile
                     if !self.name_input.value().is_empty() {
@@ -627,7 +685,7 @@ impl Component for ProfileForm {
                                 }
                             }
                             KeyCode::Down => {
-                                if self.launch_config_cursor < 1 {
+                                if self.launch_config_cursor < 2 {
                                     self.launch_config_cursor += 1;
                                     return Ok(Some(Action::Render));
                                 }
@@ -636,6 +694,7 @@ impl Component for ProfileForm {
                                 match self.launch_config_cursor {
                                     0 => self.clean_launch = !self.clean_launch,
                                     1 => self.cleanup_on_exit = !self.cleanup_on_exit,
+                                    2 => self.clean_environment = !self.clean_environment,
                                     _ => {}
                                 }
                                 return Ok(Some(Action::Render));
@@ -6,9 +6,12 @@ use tokio::sync::mpsc::UnboundedSender;
 use tui_input::Input;
 use tui_input::backend::crossterm::EventHandler;
 
-use super::Component;
+use super::{Component, badge::Badge, highlight::highlight_matches, list_scroll::ListScroll};
 use crate::components::settings_view::UserSettings;
-use crate::{action::Action, config::Config, models::Profile, storage::Storage, theme};
+use crate::{
+    action::Action, config::Config, icons::Icon, models::Profile, storage::Storage, theme,
+    utils::fuzzy::fuzzy_match,
+};
 
 #[derive(Default)]
 pub struct ProfileList {
@@ -21,6 +24,8 @@ pub struct ProfileList {
     search_mode: bool,
     search_input: Input,
     settings: Option<Arc<RwLock<UserSettings>>>,
+    compact: bool,      // Show only name and description on each card
+    scroll: ListScroll, // First card shown, kept between draws
 }
 
 impl ProfileList {
@@ -46,25 +51,16 @@ impl ProfileList {
             self.filtered_profiles = (0..self.profiles.len()).collect();
         } else {
             // Filter profiles based on search query
-            let query = search_query.to_lowercase();
-            self.filtered_profiles = self
+            let mut ranked: Vec<(Option<i32>, usize)> = self
                 .profiles
                 .iter()
                 .enumerate()
-                .filter(|(_, profile)| {
-                    profile.name.to_lowercase().contains(&query)
-                        || profile
-                            .description
-                            .as_ref()
-                            .is_some_and(|d| d.to_lowercase().contains(&query))
-                        || profile
-                            .metadata
-                            .tags
-                            .iter()
-                            .any(|tag| tag.to_lowercase().contains(&query))
-                })
-                .map(|(i, _)| i)
+                .filter_map(|(i, profile)| search_rank(profile, search_query).map(|rank| (rank, i)))
                 .collect();
+
+            // Best matches first; the sort is stable, so ties keep their order
+            ranked.sort_by_key(|&(rank, _)| std::cmp::Reverse(rank));
+            self.filtered_profiles = ranked.into_iter().map(|(_, i)| i).collect();
         }
 
         // Adjust selection if needed
@@ -91,13 +87,85 @@ impl ProfileList {
         }
     }
 
+    /// Put the cursor back on `id` after a reload. If it's gone the cursor
+    /// keeps its position, landing on the profile that took its place.
+    fn reselect(&mut self, id: Option<&str>) {
+        if let Some(pos) = self
+            .filtered_profiles
+            .iter()
+            .position(|&i| Some(self.profiles[i].id.as_str()) == id)
+        {
+            self.selected = pos;
+        }
+    }
+
     fn get_selected_profile(&self) -> Option<&Profile> {
         self.filtered_profiles
             .get(self.selected)
             .and_then(|&idx| self.profiles.get(idx))
     }
 
+    /// Point profile `id` at exactly the extensions enabled by default, once
+    /// the user has confirmed
+    fn capture_defaults(&mut self, id: &str) -> Option<Action> {
+        let profile = self.profiles.iter().find(|p| p.id == id)?.clone();
+        let storage = self.storage.as_ref()?;
+
+        Some(match storage.capture_defaults_into_profile(&profile) {
+            Ok(saved) => {
+                let message = format!(
+                    "'{}' now uses the {} extension(s) enabled by default",
+                    saved.name,
+                    saved.extension_ids.len()
+                );
+                if let Some(p) = self.profiles.iter_mut().find(|p| p.id == saved.id) {
+                    *p = saved;
+                }
+                // Let other views pick up the new extension set
+                if let Some(tx) = &self.command_tx {
+                    let _ = tx.send(Action::RefreshProfiles);
+                }
+                Action::Success(message)
+            }
+            Err(e) => Action::Error(format!("Failed to capture default extensions: {e}")),
+        })
+    }
+
+    /// Make the selected profile the default. Storage rewrites the flags
+    /// under its lock from what is on disk, so profiles changed elsewhere
+    /// since this list was loaded aren't overwritten.
+    fn set_default(&mut self) -> Option<Action> {
+        let id = self.get_selected_profile()?.id.clone();
+        let storage = self.storage.as_ref()?;
+
+        if let Err(e) = storage.set_default_profile(&id) {
+            return Some(Action::Error(format!(
+                "Failed to set the default profile: {e}"
+            )));
+        }
+        self.reload();
+        // Let other views pick up the new default
+        Some(Action::RefreshProfiles)
+    }
+
+    /// Reload profiles from storage, keeping the cursor on the same one
+    fn reload(&mut self) {
+        let selected_id = self.get_selected_profile().map(|p| p.id.clone());
+        if let Some(storage) = &self.storage
+            && let Ok(profiles) = storage.list_profiles()
+        {
+            self.profiles = profiles;
+            self.update_filter();
+            self.reselect(selected_id.as_deref());
+        }
+    }
+
     // Public methods for testing
+    #[allow(dead_code)]
+    pub fn is_compact(&self) -> bool {
+        self.compact
+    }
+
     #[allow(dead_code)]
     pub fn selected_index(&self) -> usize {
         self.selected
@@ -192,6 +260,22 @@ fn format_key_event(key: &crossterm::event::KeyEvent) -> String {
     }
 }
 
+/// How `profile` ranks in a search for `query`: `Some(score)` when its name
+/// matches fuzzily, `None` when only its description or a tag contains the
+/// query, so those come after every name match. Returns no rank at all when
+/// nothing matches.
+fn search_rank(profile: &Profile, query: &str) -> Option<Option<i32>> {
+    if let Some(name_match) = fuzzy_match(query, &profile.name) {
+        return Some(Some(name_match.score));
+    }
+
+    let query = query.to_lowercase();
+    let contains = |text: &str| text.to_lowercase().contains(&query);
+    (profile.description.as_deref().is_some_and(contains)
+        || profile.metadata.tags.iter().any(|tag| contains(tag)))
+    .then_some(None)
+}
+
 impl Component for ProfileList {
     fn register_action_handler(&mut self, tx: UnboundedSender<Action>) -> Result<()> {
         self.command_tx = Some(tx);
@@ -204,6 +288,9 @@ impl Component for ProfileList {
     }
 
     fn register_settings_handler(&mut self, settings: Arc<RwLock<UserSettings>>) -> Result<()> {
+        if let Ok(settings) = settings.read() {
+            self.compact = settings.compact_cards;
+        }
         self.settings = Some(settings);
         Ok(())
     }
@@ -216,15 +303,9 @@ impl Component for ProfileList {
             Action::Render => {
                 // No render-specific logic needed
             }
-            Action::RefreshProfiles => {
-                // Reload profiles from storage
-                if let Some(storage) = &self.storage
-                    && let Ok(profiles) = storage.list_profiles()
-                {
-                    self.profiles = profiles;
-                    self.update_filter();
-                }
-            }
+            Action::RefreshProfiles => self.reload(),
+            Action::SetCompactCards(compact) => self.compact = compact,
+            Action::CaptureDefaultsConfirmed(id) => return Ok(self.capture_defaults(&id)),
             _ => {}
         }
         Ok(None)
@@ -290,6 +371,8 @@ impl Component for ProfileList {
             .border_type(BorderType::Rounded)
             .border_style(Style::default().fg(theme::text_secondary()));
 
+        let search_query = self.search_input.value();
+
         // Create list items
         let items: Vec<ListItem> = self
             .filtered_profiles
@@ -301,23 +384,31 @@ impl Component for ProfileList {
                     let is_default = profile.metadata.is_default;
 
                     // Build the display string
-                    let mut lines = vec![Line::from(vec![
-                        Span::styled(
-                            profile.display_name(),
-                            if is_selected {
-                                Style::default()
-                                    .fg(theme::text_primary())
-                                    .add_modifier(Modifier::BOLD)
-                            } else {
-                                Style::default().fg(theme::text_primary())
-                            },
-                        ),
-                        if is_default {
-                            Span::styled(" (default)", Style::default().fg(theme::primary()))
-                        } else {
-                            Span::styled("", Style::default().fg(theme::text_primary()))
-                        },
-                    ])];
+                    let name_style = if is_selected {
+                        Style::default()
+                            .fg(theme::text_primary())
+                            .add_modifier(Modifier::BOLD)
+                    } else {
+                        Style::default().fg(theme::text_primary())
+                    };
+                    // Matches are found in the name; shift them past any icon
+                    let name_offset =
+                        profile.display_name().chars().count() - profile.name.chars().count();
+                    let matched: Vec<usize> = Some(search_query)
+                        .filter(|query| !query.is_empty())
+                        .and_then(|query| fuzzy_match(query, &profile.name))
+                        .map(|name_match| name_match.positions)
+                        .unwrap_or_default()
+                        .into_iter()
+                        .map(|position| position + name_offset)
+                        .collect();
+
+                    let mut title =
+                        highlight_matches(&profile.display_name(), &matched, name_style);
+                    if is_default {
+                        title.push(Badge::info(" (default)").span());
+                    }
+                    let mut lines = vec![Line::from(title)];
 
                     // Add description
                     if let Some(desc) = &profile.description {
@@ -327,22 +418,27 @@ impl Component for ProfileList {
                         ]));
                     }
 
+                    // Compact cards stop at the name and description
+                    if self.compact {
+                        return ListItem::new(lines);
+                    }
+
                     // Add summary
                     lines.push(Line::from(vec![
                         Span::styled("  ", Style::default().fg(theme::text_primary())),
                         Span::styled(profile.summary(), Style::default().fg(theme::text_muted())),
                         Span::styled(" | ", Style::default().fg(theme::text_secondary())),
-                        Span::styled(
-                            format!("{} tags", profile.metadata.tags.len()),
-                            Style::default().fg(theme::accent()),
-                        ),
+                        Badge::neutral(format!("{} tags", profile.metadata.tags.len())).span(),
                     ]));
 
                     // Add working directory if specified
                     if let Some(dir) = &profile.working_directory {
                         lines.push(Line::from(vec![
                             Span::styled("  ", Style::default().fg(theme::text_primary())),
-                            Span::styled("📂 ", Style::default().fg(theme::info())),
+                            Span::styled(
+                                format!("{} ", Icon::Folder),
+                                Style::default().fg(theme::info()),
+                            ),
                             Span::styled(dir, Style::default().fg(theme::info())),
                         ]));
                     }
@@ -382,6 +478,20 @@ impl Component for ProfileList {
 
             frame.render_widget(empty_widget, list_area);
         } else {
+            // Show the cards that fit, keeping the selected one in view, and
+            // say how many are out of view
+            let heights: Vec<usize> = items.iter().map(ListItem::height).collect();
+            let viewport = list_area.height.saturating_sub(2) as usize;
+            let offset = self.scroll.follow(&heights, self.selected, viewport);
+            let block = match self.scroll.hint(&heights, viewport) {
+                Some(hint) => block.title(
+                    Line::from(format!(" {hint} "))
+                        .style(Style::default().fg(theme::text_muted()))
+                        .right_aligned(),
+                ),
+                None => block,
+            };
+
             // Create the list widget
             let list = List::new(items)
                 .block(block)
@@ -389,8 +499,9 @@ impl Component for ProfileList {
                 .highlight_symbol("│ ");
 
             // Create a stateful list to track selection
-            let mut state = ListState::default();
-            state.select(Some(self.selected));
+            let mut state = ListState::default()
+                .with_offset(offset)
+                .with_selected(Some(self.selected));
 
             // Render the list
             frame.render_stateful_widget(list, list_area, &mut state);
@@ -418,6 +529,9 @@ impl Component for ProfileList {
                     ("create", "New"),
                     ("delete", "Delete"),
                     ("search", "Search"),
+                    ("m", "Compact"),
+                    ("c", "Capture defaults"),
+                    ("o", "Open in $EDITOR"),
                     ("tab", "Extensions"),
                     ("quit", "Quit"),
                 ])
@@ -570,24 +684,18 @@ impl Component for ProfileList {
                             self.search_input.reset();
                             Ok(Some(Action::Render))
                         }
-                        KeyCode::Char('x') => {
-                            if let Some(profile) = self.get_selected_profile() {
-                                let profile_id = profile.id.clone();
-                                // Set this profile as default
-                                for p in &mut self.profiles {
-                                    p.metadata.is_default = p.id == profile_id;
-                                }
-                                // Save the updated profiles
-                                if let Some(storage) = &self.storage {
-                                    for p in &self.profiles {
-                                        let _ = storage.save_profile(p);
-                                    }
-                                }
-                                Ok(Some(Action::Render))
-                            } else {
-                                Ok(None)
-                            }
+                        KeyCode::Char('x') => Ok(self.set_default()),
+                        KeyCode::Char('m') => {
+                            self.compact = !self.compact;
+                            Ok(Some(Action::Render))
                         }
+                        // Replacing the extensions is asked about first
+                        KeyCode::Char('c') => Ok(self
+                            .get_selected_profile()
+                            .map(|profile| Action::CaptureDefaults(profile.id.clone()))),
+                        KeyCode::Char('o') => Ok(self
+                            .get_selected_profile()
+                            .map(|profile| Action::EditProfileFile(profile.id.clone()))),
                         KeyCode::Tab => Ok(Some(Action::NavigateToSettings)),
                         _ => Ok(None),
                     }
@@ -2,8 +2,18 @@ use color_eyre::Result;
 use ratatui::{prelude::*, widgets::*};
 use tokio::sync::mpsc::UnboundedSender;
 
+use tui_input::Input;
+use tui_input::backend::crossterm::EventHandler;
+
 use super::Component;
-use crate::{action::Action, config::Config, theme, utils::KeybindingManager};
+use crate::{
+    action::Action,
+    config::Config,
+    icons::Icon,
+    launcher::{GEMINI_HOME_ENV, expand_home, resolve_gemini_ext_dir},
+    theme,
+    utils::KeybindingManager,
+};
 
 // NOTE: There's a complex module import resolution issue with the settings module
 // The settings module compiles fine on its own, but importing from it causes circular
@@ -36,6 +46,26 @@ impl SettingsManager {
         self.save()
     }
 
+    pub fn update_no_emoji(&mut self, no_emoji: bool) -> color_eyre::Result<()> {
+        self.settings.no_emoji = no_emoji;
+        self.save()
+    }
+
+    pub fn update_compact_cards(&mut self, compact_cards: bool) -> color_eyre::Result<()> {
+        self.settings.compact_cards = compact_cards;
+        self.save()
+    }
+
+    pub fn update_gemini_extensions_dir(&mut self, dir: Option<String>) -> color_eyre::Result<()> {
+        self.settings.gemini_extensions_dir = dir;
+        self.save()
+    }
+
+    pub fn update_gemini_cli_path(&mut self, path: Option<String>) -> color_eyre::Result<()> {
+        self.settings.gemini_cli_path = path;
+        self.save()
+    }
+
     pub fn reset_keybindings(&mut self) -> color_eyre::Result<()> {
         self.settings.keybindings = KeybindingConfig::default();
         self.save()
@@ -95,6 +125,59 @@ impl SettingsManager {
 pub struct UserSettings {
     pub theme: String,
     pub keybindings: KeybindingConfig,
+    /// Overrides where the Gemini CLI's user-level extensions live
+    #[serde(default)]
+    pub gemini_extensions_dir: Option<String>,
+    /// The Gemini CLI to launch, when it isn't the `gemini` on the PATH
+    #[serde(default)]
+    pub gemini_cli_path: Option<String>,
+    /// Draw icons with plain ASCII for terminals with poor emoji support
+    #[serde(default)]
+    pub no_emoji: bool,
+    /// Ask before launching a profile that has no extensions
+    #[serde(default = "default_true")]
+    pub confirm_empty_launch: bool,
+    /// Draw list cards without metadata or spacer lines
+    #[serde(default)]
+    pub compact_cards: bool,
+    /// Reload the lists when files in the data directory change
+    #[serde(default)]
+    pub auto_reload: bool,
+    /// What a quit key does: quit, ask first, or wait for a second press
+    #[serde(default)]
+    pub quit_behavior: QuitBehavior,
+    /// How soon the second press must follow with `double_press`
+    #[serde(default = "default_quit_window_ms")]
+    pub quit_window_ms: u64,
+    /// Where extensions are stored, relative to the data directory unless absolute.
+    /// Applies to the default data directory only, not one given with `--data-dir`.
+    #[serde(default)]
+    pub extensions_dir: Option<String>,
+    /// Where profiles are stored, relative to the data directory unless absolute.
+    /// Applies to the default data directory only, like `extensions_dir`.
+    #[serde(default)]
+    pub profiles_dir: Option<String>,
+}
+
+fn default_true() -> bool {
+    true
+}
+
+fn default_quit_window_ms() -> u64 {
+    500
+}
+
+/// How a quit key press is handled
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq, serde::Serialize, serde::Deserialize)]
+#[serde(rename_all = "snake_case")]
+pub enum QuitBehavior {
+    /// Quit on the first press
+    #[default]
+    Immediate,
+    /// Ask for confirmation first
+    Confirm,
+    /// Quit only when a second press follows within `quit_window_ms`
+    DoublePress,
 }
 
 impl Default for UserSettings {
@@ -102,6 +185,16 @@ impl Default for UserSettings {
         Self {
             theme: "mocha".to_string(),
             keybindings: KeybindingConfig::default(),
+            gemini_extensions_dir: None,
+            gemini_cli_path: None,
+            no_emoji: false,
+            confirm_empty_launch: true,
+            compact_cards: false,
+            auto_reload: false,
+            quit_behavior: QuitBehavior::Immediate,
+            quit_window_ms: default_quit_window_ms(),
+            extensions_dir: None,
+            profiles_dir: None,
         }
     }
 }
@@ -165,7 +258,61 @@ impl Default for ActionKeys {
     }
 }
 
+/// Actions whose keys can be customized, in the order they are listed
+pub const KEYBINDING_ACTIONS: [&str; 13] = [
+    "up", "down", "left", "right", "back", "quit", "edit", "delete", "create", "import", "launch",
+    "select", "search",
+];
+
+/// Keys that can't be customized yet, as actions for `get_keys_for_action`
+/// with what they do
+pub const FIXED_KEY_ACTIONS: [(&str, &str); 13] = [
+    ("s", "Sort extensions"),
+    ("m", "Compact cards"),
+    ("g", "Launch summary"),
+    ("t", "Test MCP servers"),
+    ("o", "Open profile in $EDITOR"),
+    ("p", "Sync defaults, launch plan"),
+    ("u", "Unused extensions"),
+    ("f", "Extension files"),
+    ("n", "Extension notes"),
+    ("x", "Set default profile"),
+    ("c", "Capture defaults, compact cards setting"),
+    ("Space", "Toggle, enable or disable"),
+    ("tab", "Next tab or field"),
+];
+
 impl KeybindingConfig {
+    /// Render the customizable bindings as a markdown table
+    pub fn to_markdown(&self) -> String {
+        let mut out = String::from("| Action | Keys |\n| --- | --- |\n");
+        for action in KEYBINDING_ACTIONS {
+            let keys = self
+                .get_keys_for_action(action)
+                .iter()
+                .map(|k| format!("`{}`", k.replace('|', "\\|")))
+                .collect::<Vec<_>>()
+                .join(", ");
+            out.push_str(&format!("| {action} | {keys} |\n"));
+        }
+        out
+    }
+
+    /// Render the keys that can't be customized as a markdown table
+    pub fn fixed_keys_markdown(&self) -> String {
+        let mut out = String::from("| Key | Action |\n| --- | --- |\n");
+        for (action, description) in FIXED_KEY_ACTIONS {
+            let keys = self
+                .get_keys_for_action(action)
+                .iter()
+                .map(|k| format!("`{k}`"))
+                .collect::<Vec<_>>()
+                .join(", ");
+            out.push_str(&format!("| {keys} | {description} |\n"));
+        }
+        out
+    }
+
     pub fn get_keys_for_action(&self, action: &str) -> Vec<String> {
         // This will be replaced by KeybindingManager
         match action {
@@ -184,12 +331,27 @@ impl KeybindingConfig {
             "search" => self.actions.search.clone(),
             "tab" => vec!["Tab".to_string()], // Hardcoded for now
             "x" => vec!["x".to_string()],     // Hardcoded for now
+            "y" => vec!["y".to_string()],     // Hardcoded for now - copy to clipboard
             "Space" => vec!["Space".to_string()], // Hardcoded for now
             "Ctrl+S" => vec!["Ctrl+S".to_string()], // Hardcoded for now
+            "Ctrl+P" => vec!["Ctrl+P".to_string()], // Hardcoded for now - pick an icon
             "Type" => vec!["Type".to_string()], // Hardcoded for now - represents typing text
             "r" => vec!["r".to_string()],     // Hardcoded for now - reset keybindings
             "e" => vec!["e".to_string()],     // Hardcoded for now - export settings
             "i" => vec!["i".to_string()],     // Hardcoded for now - import settings
+            "a" => vec!["a".to_string()],     // Hardcoded for now - toggle ASCII icons
+            "c" => vec!["c".to_string()],     // Hardcoded for now - card density setting
+            "s" => vec!["s".to_string()],     // Hardcoded for now - toggle sort mode
+            "m" => vec!["m".to_string()],     // Hardcoded for now - toggle compact cards
+            "g" => vec!["g".to_string()],     // Hardcoded for now - launch summary
+            "t" => vec!["t".to_string()],     // Hardcoded for now - test MCP servers
+            "o" => vec!["o".to_string()],     // Hardcoded for now - open profile in $EDITOR
+            "p" => vec!["p".to_string()],     // Hardcoded for now - sync defaults, launch plan
+            "u" => vec!["u".to_string()],     // Hardcoded for now - show unused extensions
+            "f" => vec!["f".to_string()],     // Hardcoded for now - show extension files
+            "n" => vec!["n".to_string()],     // Hardcoded for now - edit extension notes
+            // Hardcoded for now - Tab and Shift+Tab move focus in forms
+            "focus" => vec!["Tab".to_string(), "Shift+Tab".to_string()],
             _ => vec![],
         }
     }
@@ -225,8 +387,9 @@ pub struct ThemeInfo {
     pub variant: String,
 }
 
+/// The built-in themes, then any custom themes loaded from files
 pub fn available_themes() -> Vec<ThemeInfo> {
-    vec![
+    let mut themes = vec![
         ThemeInfo {
             name: "mocha".to_string(),
             display_name: "Mocha".to_string(),
@@ -247,13 +410,50 @@ pub fn available_themes() -> Vec<ThemeInfo> {
             display_name: "Latte".to_string(),
             variant: "Light".to_string(),
         },
-    ]
+    ];
+    themes.extend(
+        crate::theme::custom_themes()
+            .into_iter()
+            .map(|theme| ThemeInfo {
+                name: theme.name,
+                display_name: theme.display_name,
+                variant: theme.variant,
+            }),
+    );
+    themes
 }
 
 #[derive(Debug, PartialEq)]
 enum SettingsSection {
     Appearance,
     Keybindings,
+    Paths,
+}
+
+/// A single selectable row, across every settings section
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum SettingsRow {
+    Theme(usize),
+    AsciiIcons,
+    CompactCards,
+    Keybinding(usize),
+    GeminiCliPath,
+    ExtensionsDir,
+}
+
+/// The rows of the paths section, in order
+const PATH_ROWS: [SettingsRow; 2] = [SettingsRow::GeminiCliPath, SettingsRow::ExtensionsDir];
+
+impl SettingsRow {
+    fn section(self) -> SettingsSection {
+        match self {
+            SettingsRow::Theme(_) | SettingsRow::AsciiIcons | SettingsRow::CompactCards => {
+                SettingsSection::Appearance
+            }
+            SettingsRow::Keybinding(_) => SettingsSection::Keybindings,
+            SettingsRow::GeminiCliPath | SettingsRow::ExtensionsDir => SettingsSection::Paths,
+        }
+    }
 }
 
 #[derive(Debug, PartialEq)]
@@ -274,8 +474,14 @@ pub struct Settings {
     focused_pane: FocusedPane,
     selected_theme: usize,
     selected_keybinding: usize,
+    selected_path: usize, // Index into PATH_ROWS
     editing_keybinding: bool,
     captured_keys: Vec<String>,
+    editing_path: bool,
+    path_input: Input,
+    search_mode: bool,
+    search_input: Input,
+    search_selected: usize, // Index into matching_rows()
 
     // Data
     available_themes: Vec<ThemeInfo>,
@@ -294,24 +500,16 @@ impl Default for Settings {
             focused_pane: FocusedPane::Sections,
             selected_theme: 0,
             selected_keybinding: 0,
+            selected_path: 0,
             editing_keybinding: false,
             captured_keys: Vec::new(),
+            editing_path: false,
+            path_input: Input::default(),
+            search_mode: false,
+            search_input: Input::default(),
+            search_selected: 0,
             available_themes: available_themes(),
-            keybinding_actions: vec![
-                "up".to_string(),
-                "down".to_string(),
-                "left".to_string(),
-                "right".to_string(),
-                "back".to_string(),
-                "quit".to_string(),
-                "edit".to_string(),
-                "delete".to_string(),
-                "create".to_string(),
-                "import".to_string(),
-                "launch".to_string(),
-                "select".to_string(),
-                "search".to_string(),
-            ],
+            keybinding_actions: KEYBINDING_ACTIONS.iter().map(|a| a.to_string()).collect(),
         }
     }
 }
@@ -354,15 +552,16 @@ impl Settings {
     }
 
     fn get_sections() -> Vec<&'static str> {
-        vec!["Appearance", "Keybindings"]
+        vec!["Appearance", "Keybindings", "Paths"]
+    }
+
+    fn section_index(&self) -> usize {
+        section_position(&self.current_section)
     }
 
     fn navigate_sections(&mut self, direction: isize) {
         let sections = Self::get_sections();
-        let current_index = match self.current_section {
-            SettingsSection::Appearance => 0,
-            SettingsSection::Keybindings => 1,
-        };
+        let current_index = self.section_index();
 
         let new_index = (current_index as isize + direction)
             .max(0)
@@ -371,10 +570,281 @@ impl Settings {
         self.current_section = match new_index {
             0 => SettingsSection::Appearance,
             1 => SettingsSection::Keybindings,
+            2 => SettingsSection::Paths,
             _ => SettingsSection::Appearance,
         };
     }
 
+    /// Every settings row, in section order
+    fn rows(&self) -> Vec<SettingsRow> {
+        let themes = (0..self.available_themes.len()).map(SettingsRow::Theme);
+        let keybindings = (0..self.keybinding_actions.len()).map(SettingsRow::Keybinding);
+        themes
+            .chain([SettingsRow::AsciiIcons, SettingsRow::CompactCards])
+            .chain(keybindings)
+            .chain(PATH_ROWS)
+            .collect()
+    }
+
+    fn row_label(&self, row: SettingsRow) -> String {
+        match row {
+            SettingsRow::Theme(i) => self
+                .available_themes
+                .get(i)
+                .map(|t| format!("{} ({})", t.display_name, t.variant))
+                .unwrap_or_default(),
+            SettingsRow::AsciiIcons => "ASCII icons".to_string(),
+            SettingsRow::CompactCards => "Compact cards".to_string(),
+            SettingsRow::Keybinding(i) => {
+                self.keybinding_actions.get(i).cloned().unwrap_or_default()
+            }
+            SettingsRow::GeminiCliPath => "Gemini CLI path".to_string(),
+            SettingsRow::ExtensionsDir => "Gemini extensions directory".to_string(),
+        }
+    }
+
+    /// Rows whose label or section name contains the search query
+    pub fn matching_rows(&self) -> Vec<SettingsRow> {
+        let query = self.search_input.value().trim().to_lowercase();
+        let sections = Self::get_sections();
+        self.rows()
+            .into_iter()
+            .filter(|&row| {
+                let section = sections[section_position(&row.section())];
+                query.is_empty()
+                    || self.row_label(row).to_lowercase().contains(&query)
+                    || section.to_lowercase().contains(&query)
+            })
+            .collect()
+    }
+
+    /// The row the cursor is on in the current section
+    #[allow(dead_code)]
+    pub fn current_row(&self) -> SettingsRow {
+        match self.current_section {
+            SettingsSection::Appearance => SettingsRow::Theme(self.selected_theme),
+            SettingsSection::Keybindings => SettingsRow::Keybinding(self.selected_keybinding),
+            SettingsSection::Paths => PATH_ROWS[self.selected_path],
+        }
+    }
+
+    /// Move the cursor to `row`, switching to its section
+    fn select_row(&mut self, row: SettingsRow) {
+        match row {
+            SettingsRow::Theme(i) => self.selected_theme = i,
+            SettingsRow::Keybinding(i) => self.selected_keybinding = i,
+            SettingsRow::GeminiCliPath | SettingsRow::ExtensionsDir => {
+                self.selected_path = PATH_ROWS.iter().position(|&r| r == row).unwrap_or(0);
+            }
+            SettingsRow::AsciiIcons | SettingsRow::CompactCards => {}
+        }
+        self.current_section = row.section();
+        self.focused_pane = FocusedPane::Content;
+    }
+
+    fn start_search(&mut self) {
+        self.search_mode = true;
+        self.search_input.reset();
+        self.search_selected = 0;
+    }
+
+    fn handle_search_key(&mut self, key: crossterm::event::KeyEvent) -> Option<Action> {
+        use crossterm::event::KeyCode;
+
+        let matches = self.matching_rows();
+        match key.code {
+            KeyCode::Esc => self.search_mode = false,
+            KeyCode::Enter => {
+                if let Some(&row) = matches.get(self.search_selected) {
+                    self.select_row(row);
+                }
+                self.search_mode = false;
+            }
+            KeyCode::Up | KeyCode::Down => {
+                if !matches.is_empty() {
+                    let direction = if key.code == KeyCode::Up { -1 } else { 1 };
+                    self.search_selected = ((self.search_selected as isize + direction)
+                        .rem_euclid(matches.len() as isize))
+                        as usize;
+                }
+            }
+            _ => {
+                if self
+                    .search_input
+                    .handle_event(&crossterm::event::Event::Key(key))
+                    .is_none()
+                {
+                    return None;
+                }
+                self.search_selected = 0;
+            }
+        }
+        Some(Action::Render)
+    }
+
+    fn render_search(&self, frame: &mut Frame, area: Rect) {
+        let chunks = Layout::default()
+            .direction(Direction::Vertical)
+            .constraints([Constraint::Length(3), Constraint::Min(0)])
+            .split(area);
+
+        let search_block = Block::default()
+            .title(" Search settings (Esc to close) ")
+            .borders(Borders::ALL)
+            .border_type(BorderType::Rounded)
+            .border_style(Style::default().fg(theme::highlight()));
+        frame.render_widget(
+            Paragraph::new(self.search_input.value())
+                .style(Style::default().fg(theme::text_primary()))
+                .block(search_block),
+            chunks[0],
+        );
+        frame.set_cursor_position((
+            chunks[0].x + self.search_input.visual_cursor() as u16 + 1,
+            chunks[0].y + 1,
+        ));
+
+        let sections = Self::get_sections();
+        let matches = self.matching_rows();
+        let items: Vec<ListItem> = matches
+            .iter()
+            .map(|&row| {
+                ListItem::new(Line::from(vec![
+                    Span::styled(
+                        format!("{:<12}", sections[section_position(&row.section())]),
+                        Style::default().fg(theme::text_muted()),
+                    ),
+                    Span::styled(
+                        self.row_label(row),
+                        Style::default().fg(theme::text_primary()),
+                    ),
+                ]))
+            })
+            .collect();
+
+        let block = Block::default()
+            .title(format!(" Results ({}) ", matches.len()))
+            .borders(Borders::ALL)
+            .border_style(Style::default().fg(theme::border_focused()))
+            .border_type(BorderType::Rounded);
+
+        if items.is_empty() {
+            frame.render_widget(
+                Paragraph::new("No settings match your search")
+                    .style(Style::default().fg(theme::text_secondary()))
+                    .alignment(Alignment::Center)
+                    .block(block),
+                chunks[1],
+            );
+        } else {
+            let mut state = ListState::default();
+            state.select(Some(self.search_selected));
+            let list = List::new(items)
+                .block(block)
+                .highlight_style(Style::default().bg(theme::selection()))
+                .highlight_symbol("│ ");
+            frame.render_stateful_widget(list, chunks[1], &mut state);
+        }
+    }
+
+    /// Whether the settings search is open
+    #[allow(dead_code)]
+    pub fn is_search_mode(&self) -> bool {
+        self.search_mode
+    }
+
+    /// The Gemini extensions directory configured in settings, if any
+    fn configured_ext_dir(&self) -> Option<String> {
+        if let Some(ref shared_settings) = self.shared_settings
+            && let Ok(settings_guard) = shared_settings.read()
+        {
+            return settings_guard.gemini_extensions_dir.clone();
+        }
+
+        self.settings_manager
+            .as_ref()
+            .and_then(|m| m.get_settings().gemini_extensions_dir.clone())
+    }
+
+    /// The Gemini CLI path configured in settings, if any
+    fn configured_cli_path(&self) -> Option<String> {
+        if let Some(ref shared_settings) = self.shared_settings
+            && let Ok(settings_guard) = shared_settings.read()
+        {
+            return settings_guard.gemini_cli_path.clone();
+        }
+
+        self.settings_manager
+            .as_ref()
+            .and_then(|m| m.get_settings().gemini_cli_path.clone())
+    }
+
+    /// The directory a launch from the current directory installs extensions
+    /// into, as shown in the paths section
+    pub fn extensions_dir(&self) -> std::path::PathBuf {
+        resolve_gemini_ext_dir(
+            self.configured_ext_dir().as_deref(),
+            &std::env::current_dir().unwrap_or_default(),
+        )
+    }
+
+    fn start_path_edit(&mut self) {
+        let current = match PATH_ROWS[self.selected_path] {
+            SettingsRow::GeminiCliPath => self.configured_cli_path().unwrap_or_default(),
+            // Left empty when unset, so saving untouched keeps the default
+            _ => self.configured_ext_dir().unwrap_or_default(),
+        };
+        self.path_input = Input::from(current);
+        self.editing_path = true;
+    }
+
+    /// Save the edited path, or report why it can't be used and keep editing
+    fn save_path_edit(&mut self) {
+        let row = PATH_ROWS[self.selected_path];
+        let value = self.path_input.value().trim().to_string();
+        if let Err(problem) = validate_path(row, &value) {
+            if let Some(tx) = &self.command_tx {
+                let _ = tx.send(Action::Error(problem));
+            }
+            return;
+        }
+        self.editing_path = false;
+
+        let path = if value.is_empty() { None } else { Some(value) };
+
+        // Update shared settings first
+        if let Some(ref shared_settings) = self.shared_settings
+            && let Ok(mut settings_guard) = shared_settings.write()
+        {
+            match row {
+                SettingsRow::GeminiCliPath => settings_guard.gemini_cli_path = path.clone(),
+                _ => settings_guard.gemini_extensions_dir = path.clone(),
+            }
+        }
+
+        // Then persist to disk
+        if let Some(manager) = &mut self.settings_manager {
+            let (result, label) = match row {
+                SettingsRow::GeminiCliPath => {
+                    (manager.update_gemini_cli_path(path), "Gemini CLI path")
+                }
+                _ => (
+                    manager.update_gemini_extensions_dir(path),
+                    "Gemini extensions directory",
+                ),
+            };
+            if let Some(tx) = &self.command_tx {
+                let _ = match result {
+                    Ok(()) => tx.send(Action::Success(format!("{label} updated"))),
+                    Err(e) => tx.send(Action::Error(format!(
+                        "Failed to save {}: {e}",
+                        label.to_lowercase()
+                    ))),
+                };
+            }
+        }
+    }
+
     fn navigate_content(&mut self, direction: isize) {
         match self.current_section {
             SettingsSection::Appearance => {
@@ -393,6 +863,11 @@ impl Settings {
                         as usize;
                 }
             }
+            SettingsSection::Paths => {
+                self.selected_path = ((self.selected_path as isize + direction)
+                    .rem_euclid(PATH_ROWS.len() as isize))
+                    as usize;
+            }
         }
     }
 
@@ -400,7 +875,7 @@ impl Settings {
         if let Some(theme) = self.available_themes.get(self.selected_theme) {
             // Apply theme immediately for live preview
             if let Err(e) = crate::theme::set_theme_by_name(&theme.name) {
-                eprintln!("Error setting theme: {e}");
+                tracing::error!("Error setting theme: {e}");
                 return Ok(());
             }
 
@@ -440,15 +915,73 @@ impl Settings {
         Ok(())
     }
 
+    fn toggle_no_emoji(&mut self) {
+        let no_emoji = !crate::icons::ascii_only();
+        crate::icons::set_ascii_only(no_emoji);
+
+        // Update shared settings first
+        if let Some(ref shared_settings) = self.shared_settings
+            && let Ok(mut settings_guard) = shared_settings.write()
+        {
+            settings_guard.no_emoji = no_emoji;
+        }
+
+        // Then persist to disk
+        if let Some(manager) = &mut self.settings_manager {
+            let result = manager.update_no_emoji(no_emoji);
+            if let Some(tx) = &self.command_tx {
+                let _ = match result {
+                    Ok(()) => tx.send(Action::Success(format!(
+                        "ASCII icons {}",
+                        if no_emoji { "enabled" } else { "disabled" }
+                    ))),
+                    Err(e) => tx.send(Action::Error(format!("Failed to save icon setting: {e}"))),
+                };
+            }
+        }
+    }
+
+    /// Whether lists draw compact cards
+    pub fn compact_cards(&self) -> bool {
+        self.shared_settings
+            .as_ref()
+            .and_then(|settings| settings.read().ok().map(|s| s.compact_cards))
+            .or_else(|| {
+                self.settings_manager
+                    .as_ref()
+                    .map(|m| m.get_settings().compact_cards)
+            })
+            .unwrap_or(false)
+    }
+
+    /// Flip card density, save it and tell the lists
+    fn toggle_compact_cards(&mut self) -> Action {
+        let compact = !self.compact_cards();
+
+        // Update shared settings first
+        if let Some(ref shared_settings) = self.shared_settings
+            && let Ok(mut settings_guard) = shared_settings.write()
+        {
+            settings_guard.compact_cards = compact;
+        }
+
+        // Then persist to disk
+        if let Some(manager) = &mut self.settings_manager
+            && let Err(e) = manager.update_compact_cards(compact)
+        {
+            return Action::Error(format!("Failed to save card density: {e}"));
+        }
+
+        Action::SetCompactCards(compact)
+    }
+
     fn render_sections(&self, frame: &mut Frame, area: Rect) {
         let sections = Self::get_sections();
         let items: Vec<ListItem> = sections
             .iter()
             .enumerate()
             .map(|(i, &section)| {
-                let style = if (i == 0 && self.current_section == SettingsSection::Appearance)
-                    || (i == 1 && self.current_section == SettingsSection::Keybindings)
-                {
+                let style = if i == self.section_index() {
                     Style::default()
                         .fg(theme::primary())
                         .add_modifier(Modifier::BOLD)
@@ -499,7 +1032,13 @@ impl Settings {
 
                 // Show current selection indicator
                 if i == self.selected_theme {
-                    content.insert(0, Span::styled("● ", Style::default().fg(theme::success())));
+                    content.insert(
+                        0,
+                        Span::styled(
+                            format!("{} ", Icon::Selected),
+                            Style::default().fg(theme::success()),
+                        ),
+                    );
                 } else {
                     content.insert(0, Span::styled("  ", Style::default()));
                 }
@@ -515,6 +1054,15 @@ impl Settings {
             .block(
                 Block::default()
                     .title(" Theme Selection ")
+                    .title_bottom(format!(
+                        " ASCII icons: {} (a) · Compact cards: {} (c) ",
+                        if crate::icons::ascii_only() {
+                            "on"
+                        } else {
+                            "off"
+                        },
+                        if self.compact_cards() { "on" } else { "off" }
+                    ))
                     .borders(Borders::ALL)
                     .border_style(Style::default().fg(
                         if self.focused_pane == FocusedPane::Content
@@ -630,10 +1178,127 @@ impl Settings {
         frame.render_widget(reset_button, chunks[1]);
     }
 
+    fn render_paths(&self, frame: &mut Frame, area: Rect) {
+        let chunks = Layout::default()
+            .direction(Direction::Vertical)
+            .constraints([Constraint::Length(6), Constraint::Min(6)])
+            .split(area);
+
+        let configured_cli = self.configured_cli_path();
+        let (cli, cli_source) = match configured_cli.as_deref().map(str::trim) {
+            Some(path) if !path.is_empty() => (path.to_string(), "Settings"),
+            _ => ("gemini".to_string(), "PATH"),
+        };
+        self.render_path_row(
+            frame,
+            chunks[0],
+            SettingsRow::GeminiCliPath,
+            cli,
+            cli_source.to_string(),
+            "Run when launching a profile",
+        );
+
+        let configured = self.configured_ext_dir();
+        let (source, hint) = if configured.as_deref().is_some_and(|d| !d.trim().is_empty()) {
+            ("Settings".to_string(), "Launches install extensions here")
+        } else if std::env::var(GEMINI_HOME_ENV).is_ok_and(|d| !d.trim().is_empty()) {
+            (
+                format!("${GEMINI_HOME_ENV}"),
+                "Launches install extensions here",
+            )
+        } else {
+            (
+                "Default".to_string(),
+                "Each launch installs into its own working directory",
+            )
+        };
+        self.render_path_row(
+            frame,
+            chunks[1],
+            SettingsRow::ExtensionsDir,
+            self.extensions_dir().to_string_lossy().to_string(),
+            source,
+            hint,
+        );
+    }
+
+    /// One editable path with where its value comes from
+    fn render_path_row(
+        &self,
+        frame: &mut Frame,
+        area: Rect,
+        row: SettingsRow,
+        value: String,
+        source: String,
+        hint: &str,
+    ) {
+        let selected = PATH_ROWS[self.selected_path] == row;
+        let editing = selected && self.editing_path;
+        let title = match row {
+            SettingsRow::GeminiCliPath => " Gemini CLI ",
+            _ => " Gemini Extensions Directory ",
+        };
+        let block = Block::default()
+            .title(title)
+            .borders(Borders::ALL)
+            .border_style(Style::default().fg(
+                if selected
+                    && self.focused_pane == FocusedPane::Content
+                    && self.current_section == SettingsSection::Paths
+                {
+                    theme::border_focused()
+                } else {
+                    theme::border()
+                },
+            ))
+            .border_type(BorderType::Rounded);
+        let inner = block.inner(area);
+        frame.render_widget(block, area);
+
+        let value = if editing {
+            Span::styled(
+                self.path_input.value().to_string(),
+                Style::default()
+                    .fg(theme::warning())
+                    .add_modifier(Modifier::ITALIC),
+            )
+        } else {
+            Span::styled(value, Style::default().fg(theme::text_primary()))
+        };
+
+        let lines = vec![
+            Line::from(vec![
+                Span::styled("Path:   ", Style::default().fg(theme::highlight())),
+                value,
+            ]),
+            Line::from(vec![
+                Span::styled("Source: ", Style::default().fg(theme::highlight())),
+                Span::styled(source, Style::default().fg(theme::text_muted())),
+            ]),
+            Line::from(""),
+            Line::from(Span::styled(
+                if editing {
+                    "Enter: save | Esc: cancel | Clear to use the default"
+                } else {
+                    hint
+                },
+                Style::default().fg(theme::text_muted()),
+            )),
+        ];
+
+        frame.render_widget(Paragraph::new(lines), inner);
+
+        if editing {
+            let offset = "Path:   ".len() as u16 + self.path_input.visual_cursor() as u16;
+            frame.set_cursor_position((inner.x + offset, inner.y));
+        }
+    }
+
     fn render_content(&self, frame: &mut Frame, area: Rect) {
         match self.current_section {
             SettingsSection::Appearance => self.render_appearance(frame, area),
             SettingsSection::Keybindings => self.render_keybindings(frame, area),
+            SettingsSection::Paths => self.render_paths(frame, area),
         }
     }
 }
@@ -730,8 +1395,12 @@ impl Component for Settings {
         // Render sections
         self.render_sections(frame, chunks[0]);
 
-        // Render content
-        self.render_content(frame, chunks[1]);
+        // Render content, or the search results across every section
+        if self.search_mode {
+            self.render_search(frame, chunks[1]);
+        } else {
+            self.render_content(frame, chunks[1]);
+        }
 
         // Help text at the bottom
         let help_area = Rect {
@@ -742,39 +1411,61 @@ impl Component for Settings {
         };
 
         use crate::utils::build_help_text;
-        let help_text = match self.focused_pane {
-            FocusedPane::Sections => build_help_text(&[
-                ("up", "Navigate sections"),
-                ("down", "Navigate sections"),
-                ("right", "Enter section"),
-                ("tab", "Next tab"),
-                ("quit", "Quit"),
-            ]),
-            FocusedPane::Content => match self.current_section {
-                SettingsSection::Appearance => build_help_text(&[
-                    ("up", "Select theme"),
-                    ("down", "Select theme"),
-                    ("select", "Apply"),
-                    ("left", "Back"),
+        let help_text = if self.search_mode {
+            " Type to filter | ↑/↓: Navigate | Enter: Go to setting | Esc: Close search "
+                .to_string()
+        } else {
+            match self.focused_pane {
+                FocusedPane::Sections => build_help_text(&[
+                    ("up", "Navigate sections"),
+                    ("down", "Navigate sections"),
+                    ("right", "Enter section"),
+                    ("search", "Search"),
                     ("tab", "Next tab"),
                     ("quit", "Quit"),
                 ]),
-                SettingsSection::Keybindings => {
-                    if self.editing_keybinding {
-                        " Press any key to add | Backspace: Remove last | Ctrl+S: Save | Esc: Cancel ".to_string()
-                    } else {
-                        build_help_text(&[
-                            ("up", "Select action"),
-                            ("down", "Select action"),
-                            ("select", "Edit keybinding"),
-                            ("r", "Reset to defaults"),
-                            ("left", "Back"),
-                            ("tab", "Next tab"),
-                            ("quit", "Quit"),
-                        ])
+                FocusedPane::Content => match self.current_section {
+                    SettingsSection::Appearance => build_help_text(&[
+                        ("up", "Select theme"),
+                        ("down", "Select theme"),
+                        ("select", "Apply"),
+                        ("a", "ASCII icons"),
+                        ("c", "Compact cards"),
+                        ("left", "Back"),
+                        ("tab", "Next tab"),
+                        ("quit", "Quit"),
+                    ]),
+                    SettingsSection::Paths => {
+                        if self.editing_path {
+                            " Type path | Enter: Save | Esc: Cancel ".to_string()
+                        } else {
+                            build_help_text(&[
+                                ("up", "Select path"),
+                                ("down", "Select path"),
+                                ("select", "Edit path"),
+                                ("left", "Back"),
+                                ("tab", "Next tab"),
+                                ("quit", "Quit"),
+                            ])
+                        }
                     }
-                }
-            },
+                    SettingsSection::Keybindings => {
+                        if self.editing_keybinding {
+                            " Press any key to add | Backspace: Remove last | Ctrl+S: Save | Esc: Cancel ".to_string()
+                        } else {
+                            build_help_text(&[
+                                ("up", "Select action"),
+                                ("down", "Select action"),
+                                ("select", "Edit keybinding"),
+                                ("r", "Reset to defaults"),
+                                ("left", "Back"),
+                                ("tab", "Next tab"),
+                                ("quit", "Quit"),
+                            ])
+                        }
+                    }
+                },
+            }
         };
 
         let help_bar = Paragraph::new(help_text)
@@ -911,13 +1602,45 @@ impl Component for Settings {
             return Ok(None);
         }
 
+        // Handle path editing next
+        if self.editing_path {
+            if let Some(crate::tui::Event::Key(key)) = event {
+                match key.code {
+                    KeyCode::Esc => self.editing_path = false,
+                    KeyCode::Enter => self.save_path_edit(),
+                    _ => {
+                        if self
+                            .path_input
+                            .handle_event(&crossterm::event::Event::Key(key))
+                            .is_none()
+                        {
+                            return Ok(None);
+                        }
+                    }
+                }
+                return Ok(Some(Action::Render));
+            }
+            return Ok(None);
+        }
+
+        // Then the settings search
+        if self.search_mode {
+            if let Some(crate::tui::Event::Key(key)) = event {
+                return Ok(self.handle_search_key(key));
+            }
+            return Ok(None);
+        }
+
         // Normal mode handling
         match event {
             Some(crate::tui::Event::Key(key)) => {
                 // Use keybinding manager if available
                 if let Some(ref kb_manager) = self.keybinding_manager {
                     // Check navigation keybindings
-                    if kb_manager.matches(&key, "quit") {
+                    if kb_manager.matches(&key, "search") {
+                        self.start_search();
+                        return Ok(Some(Action::Render));
+                    } else if kb_manager.matches(&key, "quit") {
                         return Ok(Some(Action::Quit));
                     } else if kb_manager.matches(&key, "back") {
                         return Ok(Some(Action::NavigateBack));
@@ -957,6 +1680,23 @@ impl Component for Settings {
                                 self.captured_keys.clear();
                                 return Ok(Some(Action::Render));
                             }
+                            SettingsSection::Paths => {
+                                self.start_path_edit();
+                                return Ok(Some(Action::Render));
+                            }
+                        }
+                    } else if key.code == KeyCode::Char('a') {
+                        if self.current_section == SettingsSection::Appearance
+                            && self.focused_pane == FocusedPane::Content
+                        {
+                            self.toggle_no_emoji();
+                            return Ok(Some(Action::Render));
+                        }
+                    } else if key.code == KeyCode::Char('c') {
+                        if self.current_section == SettingsSection::Appearance
+                            && self.focused_pane == FocusedPane::Content
+                        {
+                            return Ok(Some(self.toggle_compact_cards()));
                         }
                     } else if key.code == KeyCode::Char('r') {
                         // Only handle reset when in keybindings section and content pane is focused
@@ -974,6 +1714,11 @@ impl Component for Settings {
                         KeyCode::Esc => return Ok(Some(Action::NavigateBack)),
                         KeyCode::Tab => return Ok(Some(Action::NavigateToExtensions)),
 
+                        KeyCode::Char('/') => {
+                            self.start_search();
+                            return Ok(Some(Action::Render));
+                        }
+
                         KeyCode::Up | KeyCode::Char('k') => {
                             match self.focused_pane {
                                 FocusedPane::Sections => self.navigate_sections(-1),
@@ -1016,6 +1761,27 @@ impl Component for Settings {
                                     self.captured_keys.clear();
                                     return Ok(Some(Action::Render));
                                 }
+                                SettingsSection::Paths => {
+                                    self.start_path_edit();
+                                    return Ok(Some(Action::Render));
+                                }
+                            }
+                        }
+
+                        KeyCode::Char('a') => {
+                            if self.current_section == SettingsSection::Appearance
+                                && self.focused_pane == FocusedPane::Content
+                            {
+                                self.toggle_no_emoji();
+                                return Ok(Some(Action::Render));
+                            }
+                        }
+
+                        KeyCode::Char('c') => {
+                            if self.current_section == SettingsSection::Appearance
+                                && self.focused_pane == FocusedPane::Content
+                            {
+                                return Ok(Some(self.toggle_compact_cards()));
                             }
                         }
 
@@ -1039,6 +1805,40 @@ impl Component for Settings {
     }
 }
 
+/// Check a path typed for `row` before it's saved. A blank value is always
+/// fine: it goes back to the default.
+///
+/// The Gemini CLI must be an existing file. The extensions directory is
+/// created if it doesn't exist yet.
+fn validate_path(row: SettingsRow, value: &str) -> std::result::Result<(), String> {
+    if value.is_empty() {
+        return Ok(());
+    }
+
+    let path = expand_home(value);
+    match row {
+        SettingsRow::GeminiCliPath if !path.is_file() => {
+            Err(format!("No Gemini CLI found at '{value}'"))
+        }
+        SettingsRow::ExtensionsDir if path.exists() && !path.is_dir() => {
+            Err(format!("'{value}' is not a directory"))
+        }
+        SettingsRow::ExtensionsDir => {
+            std::fs::create_dir_all(&path).map_err(|e| format!("Can't create '{value}': {e}"))
+        }
+        _ => Ok(()),
+    }
+}
+
+/// Position of a section in the sidebar
+fn section_position(section: &SettingsSection) -> usize {
+    match section {
+        SettingsSection::Appearance => 0,
+        SettingsSection::Keybindings => 1,
+        SettingsSection::Paths => 2,
+    }
+}
+
 fn format_key_event(key: &crossterm::event::KeyEvent) -> String {
     use crossterm::event::{KeyCode, KeyModifiers};
 
@@ -1,12 +1,28 @@
+use std::time::{Duration, Instant};
+
 use color_eyre::Result;
 use ratatui::{prelude::*, widgets::*};
+use tracing::warn;
+
+use super::{Component, badge::Badge};
+use crate::{theme, utils::truncate_to_width, view::ViewType};
+
+/// Segments slower than this are disabled so they can't stall rendering
+const SEGMENT_TIME_BUDGET: Duration = Duration::from_millis(50);
 
-use super::Component;
-use crate::{theme, view::ViewType};
+/// Widest text, in columns, a single segment may contribute
+const SEGMENT_MAX_WIDTH: usize = 32;
+
+/// A custom piece of text shown in the middle of the tab bar
+struct StatusSegment {
+    render: Box<dyn Fn() -> Result<String>>,
+    disabled: bool,
+}
 
 pub struct TabBar {
     current_view: ViewType,
     tabs: Vec<(String, ViewType)>,
+    segments: Vec<StatusSegment>,
 }
 
 impl Default for TabBar {
@@ -18,6 +34,7 @@ impl Default for TabBar {
                 ("Profiles".to_string(), ViewType::ProfileList),
                 ("Settings".to_string(), ViewType::Settings),
             ],
+            segments: Vec::new(),
         }
     }
 }
@@ -30,6 +47,57 @@ impl TabBar {
     pub fn set_current_view(&mut self, view: ViewType) {
         self.current_view = view;
     }
+
+    /// Register a custom segment, evaluated on every render.
+    ///
+    /// Segments that return an error are skipped for that frame. Segments that
+    /// take longer than the render budget are disabled for the rest of the session.
+    pub fn add_segment<F>(&mut self, segment: F)
+    where
+        F: Fn() -> Result<String> + 'static,
+    {
+        self.segments.push(StatusSegment {
+            render: Box::new(segment),
+            disabled: false,
+        });
+    }
+
+    /// Evaluate all enabled segments, returning the non-empty results
+    fn render_segments(&mut self) -> Vec<String> {
+        let mut rendered = Vec::new();
+
+        for (index, segment) in self.segments.iter_mut().enumerate() {
+            if segment.disabled {
+                continue;
+            }
+
+            let started = Instant::now();
+            let result = (segment.render)();
+            if started.elapsed() > SEGMENT_TIME_BUDGET {
+                warn!("Status segment {index} exceeded its time budget and was disabled");
+                segment.disabled = true;
+            }
+
+            match result {
+                Ok(text) => {
+                    let text = text.lines().next().unwrap_or("").trim();
+                    if !text.is_empty() {
+                        rendered.push(truncate_to_width(text, SEGMENT_MAX_WIDTH));
+                    }
+                }
+                Err(e) => warn!("Status segment {index} failed: {e}"),
+            }
+        }
+
+        rendered
+    }
+
+    /// Width taken up by the tab titles, including padding and dividers
+    fn tabs_width(&self) -> u16 {
+        let titles: usize = self.tabs.iter().map(|(title, _)| title.len() + 4).sum();
+        let dividers = self.tabs.len().saturating_sub(1) * 3;
+        (titles + dividers) as u16
+    }
 }
 
 impl Component for TabBar {
@@ -84,6 +152,7 @@ impl Component for TabBar {
                     (
                         ViewType::ProfileList
                         | ViewType::ProfileDetail
+                        | ViewType::ProfileConfig
                         | ViewType::ProfileCreate
                         | ViewType::ProfileEdit,
                         ViewType::ProfileList,
@@ -137,6 +206,7 @@ impl Component for TabBar {
             ViewType::ExtensionList | ViewType::ExtensionDetail => 0,
             ViewType::ProfileList
             | ViewType::ProfileDetail
+            | ViewType::ProfileConfig
             | ViewType::ProfileCreate
             | ViewType::ProfileEdit => 1,
             ViewType::Settings => 2,
@@ -146,17 +216,22 @@ impl Component for TabBar {
         // Render with selection
         frame.render_widget(tabs.select(selected), area);
 
+        // Reserve the right side for the breadcrumb, if one is shown
+        let mut middle_end = area.x + area.width.saturating_sub(1);
+
         // Add breadcrumb for detail/form views
         if matches!(
             self.current_view,
             ViewType::ExtensionDetail
                 | ViewType::ProfileDetail
+                | ViewType::ProfileConfig
                 | ViewType::ProfileCreate
                 | ViewType::ProfileEdit
         ) {
             let breadcrumb = match self.current_view {
                 ViewType::ExtensionDetail => " > Extension Details",
                 ViewType::ProfileDetail => " > Profile Details",
+                ViewType::ProfileConfig => " > Launch Summary",
                 ViewType::ProfileCreate => " > Create Profile",
                 ViewType::ProfileEdit => " > Edit Profile",
                 _ => "",
@@ -169,6 +244,7 @@ impl Component for TabBar {
                     width: breadcrumb.len() as u16,
                     height: 1,
                 };
+                middle_end = breadcrumb_area.x;
 
                 frame.render_widget(
                     Paragraph::new(breadcrumb).style(Style::default().fg(theme::text_secondary())),
@@ -177,6 +253,35 @@ impl Component for TabBar {
             }
         }
 
+        // Custom segments go in the space between the tabs and the breadcrumb
+        if !self.segments.is_empty() {
+            let segments = self.render_segments();
+            let middle_start = area.x + 1 + self.tabs_width();
+
+            if !segments.is_empty() && middle_end > middle_start {
+                let middle_area = Rect {
+                    x: middle_start,
+                    y: area.y + 1,
+                    width: middle_end - middle_start,
+                    height: 1,
+                };
+
+                let divider = Span::styled(" │ ", Style::default().fg(theme::text_secondary()));
+                let mut spans = Vec::new();
+                for (i, segment) in segments.into_iter().enumerate() {
+                    if i > 0 {
+                        spans.push(divider.clone());
+                    }
+                    spans.push(Badge::neutral(segment).span());
+                }
+
+                frame.render_widget(
+                    Paragraph::new(Line::from(spans)).alignment(Alignment::Center),
+                    middle_area,
+                );
+            }
+        }
+
         Ok(())
     }
 }
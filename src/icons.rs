@@ -0,0 +1,106 @@
+use std::fmt;
+use std::sync::atomic::{AtomicBool, Ordering};
+
+/// Whether icons should be drawn with plain ASCII instead of emoji/symbols
+static ASCII_ONLY: AtomicBool = AtomicBool::new(false);
+
+/// Icons used across the UI and launcher output
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum Icon {
+    Success,
+    Error,
+    Warning,
+    Selected,
+    Checked,
+    Folder,
+    Launch,
+    Cleanup,
+    Extensions,
+    Done,
+    Failed,
+    MoreAbove,
+    MoreBelow,
+}
+
+impl Icon {
+    /// Every icon, for iterating in tests and previews
+    #[allow(dead_code)]
+    pub const ALL: [Icon; 13] = [
+        Icon::Success,
+        Icon::Error,
+        Icon::Warning,
+        Icon::Selected,
+        Icon::Checked,
+        Icon::Folder,
+        Icon::Launch,
+        Icon::Cleanup,
+        Icon::Extensions,
+        Icon::Done,
+        Icon::Failed,
+        Icon::MoreAbove,
+        Icon::MoreBelow,
+    ];
+
+    /// The default, emoji/symbol form of the icon
+    pub fn emoji(self) -> &'static str {
+        match self {
+            Icon::Success => "✓",
+            Icon::Error => "✗",
+            Icon::Warning => "⚠",
+            Icon::Selected => "●",
+            Icon::Checked => "✓",
+            Icon::Folder => "📂",
+            Icon::Launch => "🚀",
+            Icon::Cleanup => "🧹",
+            Icon::Extensions => "🔧",
+            Icon::Done => "✅",
+            Icon::Failed => "❌",
+            Icon::MoreAbove => "↑",
+            Icon::MoreBelow => "↓",
+        }
+    }
+
+    /// Plain ASCII replacement for terminals with poor emoji support
+    pub fn ascii(self) -> &'static str {
+        match self {
+            Icon::Success => "+",
+            Icon::Error => "x",
+            Icon::Warning => "!",
+            Icon::Selected => "*",
+            Icon::Checked => "x",
+            Icon::Folder => ">",
+            Icon::Launch => ">>",
+            Icon::Cleanup => "--",
+            Icon::Extensions => "::",
+            Icon::Done => "[ok]",
+            Icon::Failed => "[error]",
+            Icon::MoreAbove => "^",
+            Icon::MoreBelow => "v",
+        }
+    }
+
+    /// The icon in the currently configured style
+    pub fn as_str(self) -> &'static str {
+        if ascii_only() {
+            self.ascii()
+        } else {
+            self.emoji()
+        }
+    }
+}
+
+impl fmt::Display for Icon {
+    fn fmt(&self, f: &mut fmt::Formatter<'_>) -> fmt::Result {
+        f.write_str(self.as_str())
+    }
+}
+
+/// Switch between emoji and ASCII icons
+pub fn set_ascii_only(enabled: bool) {
+    ASCII_ONLY.store(enabled, Ordering::Relaxed);
+}
+
+/// Whether ASCII icons are currently in use
+pub fn ascii_only() -> bool {
+    ASCII_ONLY.load(Ordering::Relaxed)
+}
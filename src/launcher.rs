@@ -1,21 +1,54 @@
 use std::collections::HashMap;
 use std::env;
 use std::fs;
-use std::io::Write;
-use std::path::{Path, PathBuf};
+use std::io::{Read, Write};
+use std::path::{Component, Path, PathBuf};
 use std::process::{Command, Stdio};
+use std::sync::{Arc, Mutex, mpsc};
+use std::thread;
+use std::time::{Duration, Instant};
 
 use color_eyre::{Result, eyre::eyre};
+use semver::Version;
 use serde_json::json;
+use tracing::{debug, info, warn};
 
 use crate::{
-    models::{Extension, Profile},
+    icons::Icon,
+    models::{
+        Extension, Profile,
+        extension::{McpServerConfig, sorted_entries},
+        profile::{EnvPart, is_variable_name, parse_env_value},
+    },
     storage::Storage,
 };
 
 #[derive(Default)]
 pub struct Launcher {
     pub storage: Storage,
+    gemini_cli: Option<PathBuf>, // Set in Settings; otherwise `gemini` on the PATH
+    extensions_dir: Option<String>, // Set in Settings; see `resolve_gemini_ext_dir`
+}
+
+/// What a launch would do, worked out without running anything or writing
+/// to disk
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub struct LaunchPlan {
+    /// Directory Gemini is started in
+    pub working_dir: PathBuf,
+    /// The Gemini executable set in Settings or found on the PATH, or
+    /// `gemini` if there is neither
+    pub program: PathBuf,
+    pub args: Vec<String>,
+    /// The complete environment Gemini starts with
+    pub environment: HashMap<String, String>,
+    /// Variables in `environment` that copy a secret from another variable,
+    /// so previews mask them like the secret itself
+    pub secret_references: Vec<String>,
+    /// Extension IDs with the directory each is installed into, in order
+    pub installs: Vec<(String, PathBuf)>,
+    /// Extensions the profile names that aren't stored; the launch skips them
+    pub missing: Vec<String>,
 }
 
 impl Launcher {
@@ -25,35 +58,57 @@ impl Launcher {
     }
 
     pub fn with_storage(storage: Storage) -> Self {
-        Self { storage }
+        Self {
+            storage,
+            gemini_cli: None,
+            extensions_dir: None,
+        }
+    }
+
+    /// Run the Gemini CLI at `path` rather than the `gemini` on the PATH. A
+    /// leading `~` is expanded; `None` or a blank path keeps the PATH lookup.
+    pub fn with_gemini_cli(mut self, path: Option<&str>) -> Self {
+        self.gemini_cli = path
+            .map(str::trim)
+            .filter(|path| !path.is_empty())
+            .map(expand_home);
+        self
+    }
+
+    /// Install extensions into `dir` rather than the default picked by
+    /// [`resolve_gemini_ext_dir`]. A leading `~` is expanded; `None` or a blank
+    /// path keeps the default.
+    pub fn with_extensions_dir(mut self, dir: Option<&str>) -> Self {
+        self.extensions_dir = dir.map(str::to_string);
+        self
+    }
+
+    /// Where a launch from `working_dir` installs extensions
+    fn extensions_dir(&self, working_dir: &Path) -> PathBuf {
+        resolve_gemini_ext_dir(self.extensions_dir.as_deref(), working_dir)
+    }
+
+    /// The program a launch runs
+    fn gemini_program(&self) -> PathBuf {
+        self.gemini_cli
+            .clone()
+            .unwrap_or_else(|| PathBuf::from("gemini"))
     }
 
     /// Launch Gemini CLI with the specified profile
     pub fn launch_with_profile(&self, profile: &Profile) -> Result<()> {
         // 1. Determine working directory
-        let working_dir = if let Some(dir) = &profile.working_directory {
-            // Expand ~ to home directory
-            let expanded = if dir.starts_with("~") {
-                dirs::home_dir()
-                    .map(|home| home.join(&dir[2..]))
-                    .unwrap_or_else(|| PathBuf::from(dir))
-            } else {
-                PathBuf::from(dir)
-            };
+        let working_dir = self.resolve_working_dir(profile)?;
+        info!("Launching profile '{}' in {working_dir:?}", profile.id);
 
-            // Create directory if it doesn't exist
-            if !expanded.exists() {
-                fs::create_dir_all(&expanded)?;
-            }
-
-            expanded
-        } else {
-            env::current_dir()?
-        };
+        // Create directory if it doesn't exist
+        if !working_dir.exists() {
+            fs::create_dir_all(&working_dir)?;
+        }
 
         // 2. Clean existing configuration if requested
         if profile.launch_config.clean_launch {
-            println!("🧹 Cleaning existing configuration...");
+            println!("{} Cleaning existing configuration...", Icon::Cleanup);
             self.clean_gemini_directory(&working_dir)?;
         }
 
@@ -62,24 +117,45 @@ impl Launcher {
 
         // 4. Install extensions to the working directory
         self.install_extensions_for_profile(profile, &working_dir)?;
+        if let Err(e) = self.record_launch_config(profile) {
+            warn!("Could not save the launch summary: {e}");
+        }
 
         // 5. Set up environment
         let env_vars = self.prepare_environment(profile);
 
         // 6. Launch Gemini CLI
         println!(
-            "🚀 Launching Gemini CLI with profile: {}",
+            "{} Launching Gemini CLI with profile: {}",
+            Icon::Launch,
             profile.display_name()
         );
-        println!("📂 Working directory: {}", working_dir.display());
-        println!("🔧 Extensions: {}", profile.extension_ids.join(", "));
+        println!(
+            "{} Working directory: {}",
+            Icon::Folder,
+            working_dir.display()
+        );
+        println!(
+            "{} Extensions: {}",
+            Icon::Extensions,
+            profile.extension_ids.join(", ")
+        );
         if profile.launch_config.cleanup_on_exit {
-            println!("🧹 Will clean up extensions after exit");
+            println!("{} Will clean up extensions after exit", Icon::Cleanup);
         }
         println!();
 
         // Check if gemini is available (cross-platform)
-        let gemini_check = if cfg!(target_os = "windows") {
+        let program = self.gemini_program();
+        let gemini_check = if let Some(path) = &self.gemini_cli {
+            if !path.is_file() {
+                return Err(eyre!(
+                    "Gemini CLI not found at {}. Check the path in Settings.",
+                    path.display()
+                ));
+            }
+            true
+        } else if cfg!(target_os = "windows") {
             Command::new("where")
                 .arg("gemini")
                 .output()
@@ -99,9 +175,26 @@ impl Launcher {
             ));
         }
 
+        // Refuse to launch extensions that need a newer Gemini CLI
+        match gemini_version(&program) {
+            Some(version) => {
+                let problems = check_compatibility(&self.enabled_extensions(profile), &version);
+                if !problems.is_empty() {
+                    return Err(eyre!(
+                        "Incompatible extensions:\n  {}",
+                        problems.join("\n  ")
+                    ));
+                }
+            }
+            None => {
+                warn!("Could not determine the Gemini CLI version; skipping compatibility check")
+            }
+        }
+
         // Run gemini
-        let mut cmd = Command::new("gemini");
+        let mut cmd = Command::new(&program);
         cmd.current_dir(&working_dir)
+            .env_clear()
             .envs(&env_vars)
             .stdin(Stdio::inherit())
             .stdout(Stdio::inherit())
@@ -111,7 +204,7 @@ impl Launcher {
 
         // 7. Clean up if requested
         if profile.launch_config.cleanup_on_exit {
-            println!("\n🧹 Cleaning up extensions...");
+            println!("\n{} Cleaning up extensions...", Icon::Cleanup);
             self.cleanup_extensions(&working_dir)?;
         }
 
@@ -122,33 +215,35 @@ impl Launcher {
         Ok(())
     }
 
-    /// Set up the .gemini directory structure in the working directory
+    /// Set up the .gemini directory structure in the working directory,
+    /// and the extensions directory wherever it is
     pub fn setup_workspace(&self, working_dir: &Path) -> Result<()> {
         // Create .gemini directory structure
         let gemini_dir = working_dir.join(".gemini");
         fs::create_dir_all(&gemini_dir)?;
 
-        let extensions_dir = gemini_dir.join("extensions");
-        fs::create_dir_all(&extensions_dir)?;
+        fs::create_dir_all(self.extensions_dir(working_dir))?;
 
         Ok(())
     }
 
-    /// Install extensions to the working directory
+    /// Install extensions for a launch from the working directory
     pub fn install_extensions_for_profile(
         &self,
         profile: &Profile,
         working_dir: &Path,
     ) -> Result<()> {
-        let extensions_dir = working_dir.join(".gemini").join("extensions");
+        let extensions_dir = self.extensions_dir(working_dir);
 
         // Load extensions from storage
         for ext_id in &profile.extension_ids {
             match self.storage.load_extension(ext_id) {
                 Ok(extension) => {
+                    debug!("Installing extension '{ext_id}' into {extensions_dir:?}");
                     self.install_extension(&extension, &extensions_dir)?;
                 }
                 Err(e) => {
+                    warn!("Failed to load extension '{ext_id}': {e}");
                     eprintln!("Warning: Failed to load extension '{ext_id}': {e}");
                 }
             }
@@ -157,50 +252,147 @@ impl Launcher {
         Ok(())
     }
 
+    /// Extensions of a profile that actually exist in storage
+    pub fn enabled_extensions(&self, profile: &Profile) -> Vec<Extension> {
+        profile
+            .extension_ids
+            .iter()
+            .filter_map(|id| self.storage.load_extension(id).ok())
+            .collect()
+    }
+
+    /// A summary of what a launch of `profile` would set up, see [`build_config`]
+    pub fn preview_config(&self, profile: &Profile) -> Result<String> {
+        build_config(profile, &self.enabled_extensions(profile))
+    }
+
+    /// Summarize what a launch of `profile` sets up and keep it as the last
+    /// launch summary. Returns the summary.
+    pub fn record_launch_config(&self, profile: &Profile) -> Result<String> {
+        let config = self.preview_config(profile)?;
+        self.storage.save_last_launch_config(&config)?;
+        Ok(config)
+    }
+
     /// Install a single extension
     fn install_extension(&self, extension: &Extension, extensions_dir: &Path) -> Result<()> {
+        extension
+            .validate()
+            .map_err(|e| eyre!("Refusing to install '{}': {e}", extension.id))?;
         let ext_dir = extensions_dir.join(&extension.id);
+        ensure_within(extensions_dir, &ext_dir)?;
+        // A linked extension is someone's working tree; writing through the
+        // link would overwrite their manifest and context file
+        if ext_dir.is_symlink() {
+            return Err(eyre!(
+                "Refusing to install '{}' over the linked extension at {}; remove the link first",
+                extension.id,
+                ext_dir.display()
+            ));
+        }
+        // Likewise a directory someone else put there. One an earlier version
+        // installed before the marker existed is recognized and taken over.
+        if ext_dir.exists()
+            && !ext_dir.join(INSTALL_MARKER).is_file()
+            && !is_unmarked_install(&ext_dir, extension)
+        {
+            return Err(eyre!(
+                "Refusing to install '{}' over {}, which Gemini CLI Manager didn't install; remove it first",
+                extension.id,
+                ext_dir.display()
+            ));
+        }
         fs::create_dir_all(&ext_dir)?;
+        fs::write(ext_dir.join(INSTALL_MARKER), &extension.id)?;
 
         // Write gemini-extension.json
-        let config = json!({
-            "name": extension.name,
-            "version": extension.version,
-            "mcpServers": extension.mcp_servers,
-        });
-
         let config_path = ext_dir.join("gemini-extension.json");
+        ensure_within(extensions_dir, &config_path)?;
         let mut file = fs::File::create(&config_path)?;
-        file.write_all(serde_json::to_string_pretty(&config)?.as_bytes())?;
+        file.write_all(serde_json::to_string_pretty(&extension.manifest())?.as_bytes())?;
 
         // Write context file if present
         if let Some(content) = &extension.context_content {
             // Always write as GEMINI.md for Gemini CLI compatibility
             let context_path = ext_dir.join("GEMINI.md");
+            ensure_within(extensions_dir, &context_path)?;
             let mut file = fs::File::create(&context_path)?;
             file.write_all(content.as_bytes())?;
         }
 
-        println!("  ✓ Installed extension: {}", extension.name);
+        println!(
+            "  {} Installed extension: {}",
+            Icon::Success,
+            extension.name
+        );
 
         Ok(())
     }
 
+    /// Resolve the working directory for a profile, expanding a leading `~`
+    pub fn resolve_working_dir(&self, profile: &Profile) -> Result<PathBuf> {
+        match &profile.working_directory {
+            Some(dir) => Ok(expand_home(dir)),
+            None => Ok(env::current_dir()?),
+        }
+    }
+
     /// Prepare environment variables
     pub fn prepare_environment(&self, profile: &Profile) -> HashMap<String, String> {
-        let mut env_vars = env::vars().collect::<HashMap<_, _>>();
+        self.assemble_environment(profile, env::vars())
+    }
 
-        // Add profile-specific environment variables
-        for (key, value) in &profile.environment_variables {
-            // Expand environment variable references
-            let expanded_value = if let Some(var_name) = value.strip_prefix('$') {
-                env::var(var_name).unwrap_or_else(|_| value.clone())
-            } else {
-                value.clone()
-            };
+    /// The complete environment for Gemini, built from `os_vars` and the
+    /// profile. A clean-environment profile only inherits allowlisted variables.
+    pub fn assemble_environment(
+        &self,
+        profile: &Profile,
+        os_vars: impl IntoIterator<Item = (String, String)>,
+    ) -> HashMap<String, String> {
+        let mut env_vars = Self::inherited_environment(profile, os_vars);
+        env_vars.extend(self.profile_environment(profile));
+        env_vars
+    }
 
-            env_vars.insert(key.clone(), expanded_value);
-        }
+    /// Inherited variables from `os_vars` that the profile replaces with a
+    /// different value, sorted by name. The profile's value always wins; this
+    /// only says where it hides one the user may have expected.
+    pub fn shadowed_variables(
+        &self,
+        profile: &Profile,
+        os_vars: impl IntoIterator<Item = (String, String)>,
+    ) -> Vec<String> {
+        let inherited = Self::inherited_environment(profile, os_vars);
+        let mut shadowed: Vec<String> = self
+            .profile_environment(profile)
+            .into_iter()
+            .filter(|(key, value)| inherited.get(key).is_some_and(|old| old != value))
+            .map(|(key, _)| key)
+            .collect();
+        shadowed.sort();
+        shadowed
+    }
+
+    /// The part of `os_vars` a launch passes through to Gemini
+    fn inherited_environment(
+        profile: &Profile,
+        os_vars: impl IntoIterator<Item = (String, String)>,
+    ) -> HashMap<String, String> {
+        let launch_config = &profile.launch_config;
+        os_vars
+            .into_iter()
+            .filter(|(key, _)| {
+                !launch_config.clean_environment || launch_config.env_allowlist.contains(key)
+            })
+            .collect()
+    }
+
+    /// Environment variables contributed by the profile itself, on top of the
+    /// inherited process environment
+    pub fn profile_environment(&self, profile: &Profile) -> HashMap<String, String> {
+        // Add profile-specific environment variables, with references expanded
+        let mut env_vars =
+            expand_environment(&profile.environment_variables, |name| env::var(name).ok());
 
         // Add Gemini-specific environment variables
         env_vars.insert("GEMINI_PROFILE".to_string(), profile.id.clone());
@@ -208,27 +400,91 @@ impl Launcher {
         env_vars
     }
 
-    /// Clean the .gemini directory
-    fn clean_gemini_directory(&self, working_dir: &Path) -> Result<()> {
-        let gemini_dir = working_dir.join(".gemini");
+    /// Build a shell command line that reproduces the launch for a profile,
+    /// suitable for pasting into a terminal when debugging.
+    ///
+    /// A clean-environment profile runs under `env -i` with the allowlisted
+    /// variables spelled out, so the shell's own environment isn't inherited.
+    pub fn command_line(&self, profile: &Profile) -> Result<String> {
+        let working_dir = self.resolve_working_dir(profile)?;
 
-        if !gemini_dir.exists() {
-            return Ok(());
+        let clean = profile.launch_config.clean_environment;
+        let mut env_vars: Vec<(String, String)> = if clean {
+            self.prepare_environment(profile).into_iter().collect()
+        } else {
+            self.profile_environment(profile).into_iter().collect()
+        };
+        env_vars.sort();
+
+        let mut parts = vec![
+            "cd".to_string(),
+            shell_quote(&working_dir.to_string_lossy()),
+            "&&".to_string(),
+        ];
+        if clean {
+            parts.extend(["env".to_string(), "-i".to_string()]);
+        }
+        for (key, value) in env_vars {
+            parts.push(format!("{key}={}", shell_quote(&value)));
         }
+        parts.push(shell_quote(&self.gemini_program().to_string_lossy()));
 
-        let extensions_dir = gemini_dir.join("extensions");
-        if extensions_dir.exists() {
-            // Remove all extensions
-            for entry in fs::read_dir(&extensions_dir)? {
-                let entry = entry?;
-                let path = entry.path();
+        Ok(parts.join(" "))
+    }
 
-                if path.is_dir() {
-                    let dir_name = path.file_name().and_then(|n| n.to_str()).unwrap_or("");
+    /// Work out what launching `profile` would do, for checking before the
+    /// real launch. Nothing is installed, created or run.
+    pub fn plan(&self, profile: &Profile) -> Result<LaunchPlan> {
+        let working_dir = self.resolve_working_dir(profile)?;
+        let extensions_dir = self.extensions_dir(&working_dir);
+        let environment = self.prepare_environment(profile);
 
-                    fs::remove_dir_all(&path)?;
-                    println!("  ✓ Removed extension: {dir_name}");
-                }
+        let mut installs = Vec::new();
+        let mut missing = Vec::new();
+        for ext_id in &profile.extension_ids {
+            if self.storage.load_extension(ext_id).is_ok() {
+                installs.push((ext_id.clone(), extensions_dir.join(ext_id)));
+            } else {
+                missing.push(ext_id.clone());
+            }
+        }
+
+        Ok(LaunchPlan {
+            program: self.gemini_cli.clone().unwrap_or_else(|| {
+                find_program("gemini", environment.get("PATH").map(String::as_str))
+            }),
+            args: Vec::new(),
+            working_dir,
+            environment,
+            secret_references: secret_references(&profile.environment_variables),
+            installs,
+            missing,
+        })
+    }
+
+    /// Mark the copies of stored extensions that earlier versions installed
+    /// into `extensions_dir` before [`INSTALL_MARKER`] existed, so cleanup
+    /// removes them like any other install
+    fn mark_unmarked_installs(&self, extensions_dir: &Path) -> Result<()> {
+        for extension in self.storage.list_extensions()? {
+            let ext_dir = extensions_dir.join(&extension.id);
+            if ensure_within(extensions_dir, &ext_dir).is_ok()
+                && !ext_dir.join(INSTALL_MARKER).exists()
+                && is_unmarked_install(&ext_dir, &extension)
+            {
+                fs::write(ext_dir.join(INSTALL_MARKER), &extension.id)?;
+            }
+        }
+        Ok(())
+    }
+
+    /// Clean the extensions directory
+    fn clean_gemini_directory(&self, working_dir: &Path) -> Result<()> {
+        let extensions_dir = self.extensions_dir(working_dir);
+        if extensions_dir.exists() {
+            self.mark_unmarked_installs(&extensions_dir)?;
+            for warning in remove_installed_extensions(&extensions_dir)? {
+                println!("  {} {warning}", Icon::Warning);
             }
         }
 
@@ -240,30 +496,564 @@ impl Launcher {
 
     /// Clean up extensions after Gemini exits
     fn cleanup_extensions(&self, working_dir: &Path) -> Result<()> {
-        let extensions_dir = working_dir.join(".gemini").join("extensions");
+        let extensions_dir = self.extensions_dir(working_dir);
 
         if extensions_dir.exists() {
-            // Only remove extensions we created (those with our naming pattern)
-            for entry in fs::read_dir(&extensions_dir)? {
-                let entry = entry?;
-                let path = entry.path();
-
-                if path.is_dir() {
-                    // Check if this is one of our managed extensions
-                    // (You could check for a marker file or naming pattern)
-                    let manifest_path = path.join("gemini-extension.json");
-                    if manifest_path.exists() {
-                        // This is likely one of our extensions
-                        fs::remove_dir_all(&path)?;
-                        if let Some(name) = path.file_name().and_then(|n| n.to_str()) {
-                            println!("  ✓ Removed extension: {name}");
+            self.mark_unmarked_installs(&extensions_dir)?;
+            for warning in remove_installed_extensions(&extensions_dir)? {
+                println!("  {} {warning}", Icon::Warning);
+            }
+        }
+
+        Ok(())
+    }
+}
+
+/// Environment variable that relocates the Gemini CLI's home directory
+pub const GEMINI_HOME_ENV: &str = "GEMINI_HOME";
+
+/// Resolve the directory a launch from `working_dir` installs extensions into.
+///
+/// A directory configured in settings wins, then `$GEMINI_HOME/extensions`,
+/// then the workspace `.gemini/extensions` under `working_dir`, which the
+/// Gemini CLI loads from the directory it starts in on every OS.
+pub fn resolve_gemini_ext_dir(configured: Option<&str>, working_dir: &Path) -> PathBuf {
+    resolve_gemini_ext_dir_from(configured, env::var(GEMINI_HOME_ENV).ok(), working_dir)
+}
+
+/// Resolution logic behind [`resolve_gemini_ext_dir`], with the environment passed in
+pub fn resolve_gemini_ext_dir_from(
+    configured: Option<&str>,
+    gemini_home: Option<String>,
+    working_dir: &Path,
+) -> PathBuf {
+    if let Some(dir) = configured.map(str::trim).filter(|d| !d.is_empty()) {
+        return expand_home(dir);
+    }
+
+    if let Some(gemini_home) = gemini_home
+        .as_deref()
+        .map(str::trim)
+        .filter(|d| !d.is_empty())
+    {
+        return expand_home(gemini_home).join("extensions");
+    }
+
+    working_dir.join(".gemini").join("extensions")
+}
+
+/// Expand a leading `~` to the user's home directory
+pub fn expand_home(dir: &str) -> PathBuf {
+    let rest = if dir == "~" {
+        Some("")
+    } else {
+        dir.strip_prefix("~/")
+    };
+
+    match (rest, dirs::home_dir()) {
+        (Some(rest), Some(home)) => home.join(rest),
+        _ => PathBuf::from(dir),
+    }
+}
+
+/// File dropped into every extension directory we install, so cleanup can
+/// tell our copies from extensions the user installed with Gemini directly
+pub const INSTALL_MARKER: &str = ".gemini-cli-manager";
+
+/// Whether `ext_dir` is a copy of `extension` installed before
+/// [`INSTALL_MARKER`] existed: a real directory whose manifest is exactly the
+/// one installing the extension writes
+fn is_unmarked_install(ext_dir: &Path, extension: &Extension) -> bool {
+    if ext_dir.is_symlink() || !ext_dir.is_dir() {
+        return false;
+    }
+    fs::read_to_string(ext_dir.join("gemini-extension.json"))
+        .ok()
+        .and_then(|manifest| serde_json::from_str::<serde_json::Value>(&manifest).ok())
+        .is_some_and(|manifest| manifest == extension.manifest())
+}
+
+/// Remove the extensions we installed into `extensions_dir`.
+///
+/// Only directories carrying [`INSTALL_MARKER`] are removed. Anything else is
+/// someone else's data: a linked working tree, an extension Gemini installed
+/// itself, a stray file. Those are kept, and one warning is returned for each.
+pub fn remove_installed_extensions(extensions_dir: &Path) -> Result<Vec<String>> {
+    let mut warnings = Vec::new();
+    let mut entries: Vec<PathBuf> = fs::read_dir(extensions_dir)?
+        .map(|entry| entry.map(|e| e.path()))
+        .collect::<std::io::Result<_>>()?;
+    entries.sort();
+
+    for path in entries {
+        let name = path.file_name().and_then(|n| n.to_str()).unwrap_or("");
+        // Checked first: is_dir follows links and would see the target
+        if path.is_symlink() {
+            warnings.push(format!("Kept linked extension: {name}"));
+        } else if path.is_dir() && path.join(INSTALL_MARKER).is_file() {
+            fs::remove_dir_all(&path)?;
+            println!("  {} Removed extension: {name}", Icon::Success);
+        } else {
+            warnings.push(format!("Kept {name}: not installed by Gemini CLI Manager"));
+        }
+    }
+    Ok(warnings)
+}
+
+/// Refuse to write to `target` unless it lies strictly inside `base`.
+///
+/// Extension and profile ids come from JSON files on disk, so an id like
+/// `../../x` or an absolute path must not be able to redirect a write
+/// elsewhere. The check is lexical because the destination usually doesn't
+/// exist yet.
+pub(crate) fn ensure_within(base: &Path, target: &Path) -> Result<()> {
+    let base = normalize_path(base);
+    let target = normalize_path(target);
+
+    match target.strip_prefix(&base) {
+        Ok(rest) if rest.components().next().is_some() => Ok(()),
+        _ => Err(eyre!(
+            "Refusing to write outside {}: {}",
+            base.display(),
+            target.display()
+        )),
+    }
+}
+
+/// Resolve `.` and `..` components without touching the filesystem
+fn normalize_path(path: &Path) -> PathBuf {
+    let mut normalized = PathBuf::new();
+    for component in path.components() {
+        match component {
+            Component::CurDir => {}
+            Component::ParentDir => match normalized.components().next_back() {
+                Some(Component::Normal(_)) => {
+                    normalized.pop();
+                }
+                // `..` at the root stays at the root
+                Some(Component::RootDir | Component::Prefix(_)) => {}
+                _ => normalized.push(component),
+            },
+            other => normalized.push(other),
+        }
+    }
+    normalized
+}
+
+/// Summarize, as JSON, what launching a profile with these extensions sets up.
+///
+/// This is a summary for people to read, not a file Gemini loads: each
+/// extension is installed as its own `gemini-extension.json`, and the
+/// variables are passed in Gemini's environment.
+///
+/// MCP servers are merged in profile order. When two extensions define a
+/// server with the same name the first definition wins; the ones it hides are
+/// listed under `shadowedServers` so overlapping definitions are easy to spot.
+/// Environment values are shown unexpanded so secrets referenced with `$VAR`
+/// don't end up on screen, and secrets written inline are masked, since the
+/// summary is also kept on disk as the last launch summary.
+pub fn build_config(profile: &Profile, extensions: &[Extension]) -> Result<String> {
+    let mut servers = serde_json::Map::new();
+    let mut owners: HashMap<&str, &str> = HashMap::new();
+    let mut shadowed = Vec::new();
+
+    for extension in extensions {
+        let mut names: Vec<&String> = extension.mcp_servers.keys().collect();
+        names.sort();
+        for name in names {
+            if let Some(owner) = owners.get(name.as_str()) {
+                shadowed.push(json!({
+                    "server": name,
+                    "extension": extension.name,
+                    "shadowedBy": owner,
+                }));
+                continue;
+            }
+            owners.insert(name, &extension.name);
+            let mut server = serde_json::to_value(&extension.mcp_servers[name])?;
+            if let Some(fields) = server.as_object_mut() {
+                // Unset options are left out, as they would be in settings.json
+                fields.retain(|_, value| !value.is_null());
+                if let Some(env) = fields.get_mut("env").and_then(|env| env.as_object_mut()) {
+                    for (key, value) in env.iter_mut() {
+                        if let Some(text) = value.as_str() {
+                            *value = json!(mask_config_value(key, text));
                         }
                     }
                 }
             }
+            servers.insert(name.clone(), server);
         }
+    }
 
-        Ok(())
+    let mut environment: serde_json::Map<String, serde_json::Value> = profile
+        .environment_variables
+        .iter()
+        .map(|(key, value)| (key.clone(), json!(mask_config_value(key, value))))
+        .collect();
+    environment.insert("GEMINI_PROFILE".to_string(), json!(profile.id));
+
+    let mut config = json!({
+        "profile": profile.name,
+        "extensions": extensions
+            .iter()
+            .map(|ext| json!({ "name": ext.name, "version": ext.version }))
+            .collect::<Vec<_>>(),
+        "mcpServers": servers,
+        "environment": environment,
+    });
+    if !shadowed.is_empty() {
+        config["shadowedServers"] = json!(shadowed);
+    }
+
+    Ok(serde_json::to_string_pretty(&config)?)
+}
+
+/// Whether an environment variable's name suggests it holds a secret
+pub fn is_secret_env_key(key: &str) -> bool {
+    let key = key.to_uppercase();
+    ["TOKEN", "SECRET", "KEY"]
+        .iter()
+        .any(|marker| key.contains(marker))
+}
+
+/// Hide the value of a secret variable. Long values keep four characters at
+/// each end so they can still be told apart.
+pub fn mask_env_value(key: &str, value: &str) -> String {
+    if is_secret_env_key(key) {
+        mask_secret(value)
+    } else {
+        value.to_string()
+    }
+}
+
+/// Variables in `vars` whose values pull in a secret by referring to a
+/// variable named like one, directly or through other entries of `vars`,
+/// sorted by name. Expanding them copies the secret under a harmless name.
+pub fn secret_references(vars: &HashMap<String, String>) -> Vec<String> {
+    fn pulls_secret(key: &str, vars: &HashMap<String, String>, seen: &mut Vec<String>) -> bool {
+        seen.push(key.to_string());
+        let parts = parse_env_value(&vars[key]).unwrap_or_default();
+        parts.into_iter().any(|part| match part {
+            EnvPart::Variable(name) if is_secret_env_key(name) => true,
+            // A reference back to `key` reads the inherited value instead
+            EnvPart::Variable(name) if name != key && vars.contains_key(name) => {
+                !seen.iter().any(|s| s == name) && pulls_secret(name, vars, seen)
+            }
+            _ => false,
+        })
+    }
+
+    let mut names: Vec<String> = vars
+        .keys()
+        .filter(|key| pulls_secret(key, vars, &mut Vec::new()))
+        .cloned()
+        .collect();
+    names.sort();
+    names
+}
+
+/// Mask a secret value, keeping four characters at each end of long ones
+fn mask_secret(value: &str) -> String {
+    let chars: Vec<char> = value.chars().collect();
+    if chars.len() > 8 {
+        let head: String = chars[..4].iter().collect();
+        let tail: String = chars[chars.len() - 4..].iter().collect();
+        format!("{head}...{tail}")
+    } else {
+        "***".to_string()
+    }
+}
+
+/// Mask a secret value written into a config. A bare `$VAR` or `${VAR}`
+/// reference holds no secret itself, so it is left readable.
+fn mask_config_value(key: &str, value: &str) -> String {
+    let reference = value.strip_prefix('$').map(|name| {
+        name.strip_prefix('{')
+            .and_then(|name| name.strip_suffix('}'))
+            .unwrap_or(name)
+    });
+    if reference.is_some_and(is_variable_name) {
+        value.to_string()
+    } else {
+        mask_env_value(key, value)
+    }
+}
+
+/// A resolved environment as `(name, value)` pairs sorted by name, with
+/// secret values masked for display. Variables named in `secret_refs` (see
+/// [`secret_references`]) are masked whatever their name.
+pub fn environment_preview(
+    env: &HashMap<String, String>,
+    secret_refs: &[String],
+) -> Vec<(String, String)> {
+    sorted_entries(env)
+        .into_iter()
+        .map(|(key, value)| {
+            let value = if secret_refs.contains(key) {
+                mask_secret(value)
+            } else {
+                mask_env_value(key, value)
+            };
+            (key.clone(), value)
+        })
+        .collect()
+}
+
+/// Expand `$NAME` and `${NAME}` references in the values of `vars` the way
+/// a shell would: unset names expand to nothing and `$$` is a literal `$`.
+///
+/// A value may refer to another entry of `vars`, which is expanded first. A
+/// reference back to the variable being expanded, as in
+/// `PATH=$HOME/bin:$PATH`, reads `lookup` instead.
+pub fn expand_environment(
+    vars: &HashMap<String, String>,
+    lookup: impl Fn(&str) -> Option<String>,
+) -> HashMap<String, String> {
+    vars.keys()
+        .map(|key| {
+            let value = expand_variable(key, vars, &lookup, &mut Vec::new());
+            (key.clone(), value)
+        })
+        .collect()
+}
+
+/// Expand one entry of `vars`. `expanding` holds the entries already being
+/// expanded further up, so a cycle falls back to `lookup` instead of looping.
+fn expand_variable(
+    key: &str,
+    vars: &HashMap<String, String>,
+    lookup: &dyn Fn(&str) -> Option<String>,
+    expanding: &mut Vec<String>,
+) -> String {
+    expanding.push(key.to_string());
+    let value = expand_references(&vars[key], &mut |name| {
+        if vars.contains_key(name) && !expanding.iter().any(|k| k == name) {
+            Some(expand_variable(name, vars, lookup, expanding))
+        } else {
+            lookup(name)
+        }
+    });
+    expanding.pop();
+    value
+}
+
+/// Replace each `$NAME`, `${NAME}` and `$$` in `value`, as parsed by
+/// [`parse_env_value`]. A malformed value is kept as it is; launches refuse
+/// it before anything is expanded.
+fn expand_references(value: &str, lookup: &mut dyn FnMut(&str) -> Option<String>) -> String {
+    let Ok(parts) = parse_env_value(value) else {
+        return value.to_string();
+    };
+    parts
+        .into_iter()
+        .map(|part| match part {
+            EnvPart::Text(text) => text.to_string(),
+            EnvPart::Variable(name) => lookup(name).unwrap_or_default(),
+        })
+        .collect()
+}
+
+/// Version of the Gemini CLI at `program`, from `gemini --version`
+pub fn gemini_version(program: &Path) -> Option<Version> {
+    let output = Command::new(program).arg("--version").output().ok()?;
+    if !output.status.success() {
+        return None;
+    }
+    parse_gemini_version(&String::from_utf8_lossy(&output.stdout))
+}
+
+/// Pull the first semantic version out of `gemini --version` output, which
+/// may be bare (`0.1.9`) or prefixed (`gemini v0.1.9`)
+pub fn parse_gemini_version(output: &str) -> Option<Version> {
+    output
+        .split_whitespace()
+        .map(|word| word.trim_start_matches('v'))
+        .find_map(|word| Version::parse(word).ok())
+}
+
+/// Describe every extension whose `min_gemini_version` is newer than `gemini`.
+///
+/// An empty result means all extensions are compatible. Unparseable minimums
+/// are reported too, since the requirement can't be checked.
+pub fn check_compatibility(extensions: &[Extension], gemini: &Version) -> Vec<String> {
+    extensions
+        .iter()
+        .filter_map(|ext| {
+            let min = ext.min_gemini_version.as_deref()?.trim();
+            match Version::parse(min) {
+                Ok(required) if required > *gemini => Some(format!(
+                    "'{}' requires Gemini CLI {required} or newer (found {gemini})",
+                    ext.name
+                )),
+                Ok(_) => None,
+                Err(_) => Some(format!(
+                    "'{}' has an invalid minGeminiVersion '{min}'",
+                    ext.name
+                )),
+            }
+        })
+        .collect()
+}
+
+/// Describe every extension that doesn't list `platform` among the operating
+/// systems it supports. An empty result means all of them run there.
+pub fn check_platforms(extensions: &[Extension], platform: &str) -> Vec<String> {
+    extensions
+        .iter()
+        .filter(|ext| !ext.supports_platform(platform))
+        .map(|ext| {
+            format!(
+                "'{}' supports {} only (this is {platform})",
+                ext.name,
+                ext.platforms.join(", ")
+            )
+        })
+        .collect()
+}
+
+/// How long a server must stay up for the smoke test to pass
+pub const SMOKE_TEST_WAIT: Duration = Duration::from_millis(500);
+
+/// How much of the end of a server's stderr the smoke test keeps
+const STDERR_TAIL_BYTES: usize = 4096;
+
+/// How long to wait for the rest of a server's stderr once it has exited.
+/// A process it started may hold the pipe open for much longer.
+const STDERR_GRACE: Duration = Duration::from_millis(100);
+
+/// Directory an extension's servers run from: where it was imported from when
+/// that still exists, otherwise the current directory
+pub fn extension_dir(extension: &Extension) -> PathBuf {
+    source_dir(extension)
+        .unwrap_or_else(|| env::current_dir().unwrap_or_else(|_| PathBuf::from(".")))
+}
+
+/// Directory an extension was imported from, if it still exists. A manifest
+/// or context file source gives the directory containing it.
+pub fn source_dir(extension: &Extension) -> Option<PathBuf> {
+    let path = expand_home(extension.metadata.source_path.as_deref()?);
+    if path.is_dir() {
+        Some(path)
+    } else if path.is_file() {
+        path.parent().map(Path::to_path_buf)
+    } else {
+        None
+    }
+}
+
+/// Start an MCP server and check it is still running after `wait`, then kill
+/// it. This only proves the command starts; no protocol handshake is made.
+/// A server that exits early is reported with the last line of its stderr.
+pub fn smoke_test_server(server: &McpServerConfig, dir: &Path, wait: Duration) -> Result<()> {
+    let Some(command) = &server.command else {
+        return Err(eyre!("not a command-based server"));
+    };
+
+    let cwd = match &server.cwd {
+        Some(cwd) => dir.join(expand_home(cwd)),
+        None => dir.to_path_buf(),
+    };
+
+    let mut cmd = Command::new(command);
+    cmd.args(server.args.iter().flatten())
+        .current_dir(&cwd)
+        // Keep stdin open: stdio servers exit as soon as it closes
+        .stdin(Stdio::piped())
+        .stdout(Stdio::null())
+        .stderr(Stdio::piped());
+    if let Some(vars) = &server.env {
+        cmd.envs(expand_environment(vars, |name| env::var(name).ok()));
+    }
+
+    debug!("Smoke testing MCP server: {command} in {cwd:?}");
+    let mut child = cmd
+        .spawn()
+        .map_err(|e| eyre!("failed to start '{command}': {e}"))?;
+
+    // Read stderr as it comes, so a chatty server can't fill the pipe and a
+    // process holding it open can't hold up the test
+    let tail = Arc::new(Mutex::new(Vec::new()));
+    let (done_tx, done_rx) = mpsc::channel();
+    if let Some(pipe) = child.stderr.take() {
+        let tail = Arc::clone(&tail);
+        thread::spawn(move || {
+            keep_tail(pipe, &tail);
+            let _ = done_tx.send(());
+        });
+    }
+
+    let started = Instant::now();
+    while started.elapsed() < wait {
+        if let Some(status) = child.try_wait()? {
+            // The last thing it printed usually says why
+            let _ = done_rx.recv_timeout(STDERR_GRACE);
+            let stderr = tail
+                .lock()
+                .map(|tail| String::from_utf8_lossy(&tail).into_owned())
+                .unwrap_or_default();
+            return Err(
+                match stderr.lines().rev().find(|line| !line.trim().is_empty()) {
+                    Some(line) => {
+                        eyre!("'{command}' exited immediately ({status}): {}", line.trim())
+                    }
+                    None => eyre!("'{command}' exited immediately ({status})"),
+                },
+            );
+        }
+        thread::sleep(Duration::from_millis(25));
+    }
+
+    let _ = child.kill();
+    let _ = child.wait();
+    Ok(())
+}
+
+/// Read `pipe` to the end, keeping the last [`STDERR_TAIL_BYTES`] in `tail`
+fn keep_tail(mut pipe: impl Read, tail: &Mutex<Vec<u8>>) {
+    let mut chunk = [0; 1024];
+    while let Ok(read) = pipe.read(&mut chunk) {
+        if read == 0 {
+            break;
+        }
+        if let Ok(mut tail) = tail.lock() {
+            tail.extend_from_slice(&chunk[..read]);
+            let excess = tail.len().saturating_sub(STDERR_TAIL_BYTES);
+            tail.drain(..excess);
+        }
+    }
+}
+
+/// The first executable called `name` in the directories of `path`, a
+/// PATH-style list, or just `name` when none is found
+fn find_program(name: &str, path: Option<&str>) -> PathBuf {
+    let suffixes: &[&str] = if cfg!(target_os = "windows") {
+        &["exe", "cmd", "bat"]
+    } else {
+        &[""]
+    };
+    path.into_iter()
+        .flat_map(env::split_paths)
+        .flat_map(|dir| {
+            suffixes
+                .iter()
+                .map(move |ext| dir.join(name).with_extension(ext))
+        })
+        .find(|candidate| candidate.is_file())
+        .unwrap_or_else(|| PathBuf::from(name))
+}
+
+/// Quote a value for POSIX shells, leaving simple words untouched
+fn shell_quote(value: &str) -> String {
+    let is_plain = !value.is_empty()
+        && value
+            .chars()
+            .all(|c| c.is_ascii_alphanumeric() || "-_./:@%+,".contains(c));
+
+    if is_plain {
+        value.to_string()
+    } else {
+        format!("'{}'", value.replace('\'', "'\\''"))
     }
 }
 
@@ -297,8 +1087,8 @@ export GEMINI_PROFILE="{}"
 {}
 
 # Launch Gemini CLI
-echo "🚀 Launching Gemini CLI with profile: {}"
-echo "📂 Working directory: $PWD"
+echo "{launch} Launching Gemini CLI with profile: {}"
+echo "{folder} Working directory: $PWD"
 echo ""
 
 gemini
@@ -317,6 +1107,8 @@ gemini
             .map(|dir| format!("cd \"{dir}\""))
             .unwrap_or_else(|| "# No working directory specified".to_string()),
         profile.display_name(),
+        launch = Icon::Launch,
+        folder = Icon::Folder,
     );
 
     let mut file = fs::File::create(output_path)?;
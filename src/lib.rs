@@ -4,6 +4,7 @@ pub mod cli;
 pub mod components;
 pub mod config;
 pub mod errors;
+pub mod icons;
 pub mod launcher;
 pub mod logging;
 pub mod models;
@@ -12,6 +13,7 @@ pub mod theme;
 pub mod tui;
 pub mod utils;
 pub mod view;
+pub mod watcher;
 
 // Re-export commonly used types
 pub use app::App;
@@ -1,3 +1,5 @@
+use std::path::PathBuf;
+
 use color_eyre::Result;
 use tracing_error::ErrorLayer;
 use tracing_subscriber::{EnvFilter, fmt, prelude::*};
@@ -9,18 +11,43 @@ lazy_static::lazy_static! {
     pub static ref LOG_FILE: String = format!("{}.log", env!("CARGO_PKG_NAME"));
 }
 
+/// Options controlling where and how verbosely we log
+#[derive(Debug, Clone, Default)]
+pub struct LogOptions {
+    /// Log everything at debug level, ignoring any level set in the environment
+    pub debug: bool,
+    /// Write to this file instead of the default one in the data directory
+    pub path: Option<PathBuf>,
+}
+
+/// Initialize logging with the default options
+#[allow(dead_code)]
 pub fn init() -> Result<()> {
-    let directory = config::get_data_dir();
-    std::fs::create_dir_all(directory.clone())?;
-    let log_path = directory.join(LOG_FILE.clone());
+    init_with(LogOptions::default())
+}
+
+pub fn init_with(options: LogOptions) -> Result<()> {
+    let log_path = match options.path {
+        Some(path) => {
+            if let Some(parent) = path.parent().filter(|p| !p.as_os_str().is_empty()) {
+                std::fs::create_dir_all(parent)?;
+            }
+            path
+        }
+        None => {
+            let directory = config::get_data_dir();
+            std::fs::create_dir_all(directory.clone())?;
+            directory.join(LOG_FILE.clone())
+        }
+    };
     let log_file = std::fs::File::create(log_path)?;
-    let env_filter = EnvFilter::builder().with_default_directive(tracing::Level::INFO.into());
     // If the `RUST_LOG` environment variable is set, use that as the default, otherwise use the
-    // value of the `LOG_ENV` environment variable. If the `LOG_ENV` environment variable contains
-    // errors, then this will return an error.
-    let env_filter = env_filter
-        .try_from_env()
-        .or_else(|_| env_filter.with_env_var(LOG_ENV.clone()).from_env())?;
+    // value of the `LOG_ENV` environment variable. If the directives contain errors, then this
+    // will return an error.
+    let directives = std::env::var(EnvFilter::DEFAULT_ENV)
+        .or_else(|_| std::env::var(LOG_ENV.as_str()))
+        .ok();
+    let env_filter = build_filter(options.debug, directives.as_deref())?;
     let file_subscriber = fmt::layer()
         .with_file(true)
         .with_line_number(true)
@@ -34,3 +61,16 @@ pub fn init() -> Result<()> {
         .try_init()?;
     Ok(())
 }
+
+/// Build the level filter from the `--debug` flag and any directives from the environment.
+///
+/// `--debug` takes precedence so a stray `RUST_LOG` can't hide debug output when it
+/// was explicitly asked for. Without either, only info and above is logged.
+pub fn build_filter(debug: bool, directives: Option<&str>) -> Result<EnvFilter> {
+    if debug {
+        return Ok(EnvFilter::new(tracing::Level::DEBUG.as_str()));
+    }
+
+    let builder = EnvFilter::builder().with_default_directive(tracing::Level::INFO.into());
+    Ok(builder.parse(directives.unwrap_or_default())?)
+}
@@ -1,5 +1,5 @@
 use clap::Parser;
-use cli::Cli;
+use cli::{Cli, Command};
 use color_eyre::Result;
 
 use crate::app::App;
@@ -10,6 +10,7 @@ mod cli;
 mod components;
 mod config;
 mod errors;
+mod icons;
 mod launcher;
 mod logging;
 mod models;
@@ -18,36 +19,149 @@ mod theme;
 mod tui;
 mod utils;
 mod view;
+mod watcher;
 
 #[tokio::main]
 async fn main() -> Result<()> {
     crate::errors::init()?;
-    crate::logging::init()?;
+
+    let args = Cli::parse();
+    crate::logging::init_with(crate::logging::LogOptions {
+        debug: args.debug,
+        path: args.log_file.clone(),
+    })?;
 
     // Initialize theme with Catppuccin Mocha for better visibility
     crate::theme::set_flavour(crate::theme::ThemeFlavour::Mocha);
 
-    let args = Cli::parse();
+    match &args.command {
+        Some(Command::Init { from, to }) => {
+            init_data_dir(from, to)?;
+            return Ok(());
+        }
+        Some(Command::Keys { markdown }) => {
+            print_keybindings(*markdown)?;
+            return Ok(());
+        }
+        _ => {}
+    }
 
     // Handle list-storage flag
+    // The layout in the settings belongs to the default data directory; any
+    // other one keeps the usual layout
+    let storage = match &args.data_dir {
+        Some(dir) => crate::storage::Storage::with_data_dir(dir.clone()),
+        None => with_configured_layout(crate::storage::Storage::new()?)?,
+    };
+
+    match &args.command {
+        Some(Command::ExportProfile { id, to }) => {
+            storage.export_profile(id, to)?;
+            println!(
+                "{} Exported profile '{id}' to {}",
+                crate::icons::Icon::Done,
+                to.display()
+            );
+            return Ok(());
+        }
+        Some(Command::ImportProfile { file, on_conflict }) => {
+            storage.init()?;
+            print_import_outcome(&storage.import_profile_file(file, |_, _| *on_conflict)?);
+            return Ok(());
+        }
+        _ => {}
+    }
+
     if args.list_storage {
-        list_storage_contents()?;
+        list_storage_contents(&storage)?;
         return Ok(());
     }
 
-    let mut app = App::new()?;
+    let mut app = App::with_storage(storage)?;
     app.run().await?;
     Ok(())
 }
 
-fn list_storage_contents() -> Result<()> {
-    use crate::storage::Storage;
+/// Point `storage`, which must be the default data directory, at the
+/// extension and profile directories chosen in the settings, if any
+fn with_configured_layout(storage: crate::storage::Storage) -> Result<crate::storage::Storage> {
+    use crate::components::settings_view::SettingsManager;
+    use crate::launcher::expand_home;
+
+    let manager = SettingsManager::new()?;
+    let settings = manager.get_settings();
+    let extensions = settings.extensions_dir.as_deref().map(expand_home);
+    let profiles = settings.profiles_dir.as_deref().map(expand_home);
+    Ok(storage.with_layout(extensions.as_deref(), profiles.as_deref()))
+}
+
+fn init_data_dir(from: &std::path::Path, to: &std::path::Path) -> Result<()> {
+    use crate::storage::{Storage, data_dir_badge};
+
+    // Copying from the default data directory reads it with its configured
+    // layout; the new directory gets the usual one, as `--data-dir` expects
+    let mut source = Storage::with_data_dir(from.to_path_buf());
+    if data_dir_badge(from, &Storage::default_data_dir()?).is_none() {
+        source = with_configured_layout(source)?;
+    }
+    let target = Storage::with_data_dir(to.to_path_buf());
+    let (extensions, profiles) = source.copy_into(&target)?;
 
+    println!(
+        "{} Copied {extensions} extensions and {profiles} profiles to {}",
+        crate::icons::Icon::Done,
+        to.display()
+    );
+    Ok(())
+}
+
+fn print_import_outcome(outcome: &crate::storage::ImportOutcome) {
+    use crate::{icons::Icon, storage::ImportOutcome};
+
+    match outcome {
+        ImportOutcome::Imported(id) => println!("{} Imported profile '{id}'", Icon::Done),
+        ImportOutcome::Renamed { from, to } => println!(
+            "{} Imported profile as '{to}' because '{from}' already exists",
+            Icon::Done
+        ),
+        ImportOutcome::Overwritten(id) => println!("{} Replaced profile '{id}'", Icon::Done),
+        ImportOutcome::Skipped(id) => println!("Skipped profile '{id}': it already exists"),
+    }
+}
+
+fn print_keybindings(markdown: bool) -> Result<()> {
+    use crate::components::settings_view::{
+        FIXED_KEY_ACTIONS, KEYBINDING_ACTIONS, SettingsManager,
+    };
+
+    let manager = SettingsManager::new()?;
+    let keybindings = &manager.get_settings().keybindings;
+    if markdown {
+        print!("{}", keybindings.to_markdown());
+        println!();
+        print!("{}", keybindings.fixed_keys_markdown());
+    } else {
+        for action in KEYBINDING_ACTIONS {
+            println!(
+                "{action:<8} {}",
+                keybindings.get_keys_for_action(action).join(", ")
+            );
+        }
+        println!("\nFixed keys:");
+        for (action, description) in FIXED_KEY_ACTIONS {
+            println!(
+                "{:<8} {description}",
+                keybindings.get_keys_for_action(action).join(", ")
+            );
+        }
+    }
+    Ok(())
+}
+
+fn list_storage_contents(storage: &crate::storage::Storage) -> Result<()> {
     println!("Gemini CLI Manager - Storage Contents");
     println!("=====================================\n");
 
-    let storage = Storage::new()?;
-
     println!("Extensions:");
     println!("-----------");
     let extensions = storage.list_extensions()?;
@@ -1,6 +1,9 @@
 use chrono::{DateTime, Utc};
-use serde::{Deserialize, Serialize};
-use std::collections::HashMap;
+use serde::{Deserialize, Serialize, Serializer};
+use std::collections::{BTreeMap, HashMap};
+
+use super::Profile;
+use super::profile::{check_env_value, is_variable_name};
 
 /// Represents a Gemini CLI extension based on gemini-extension.json
 #[derive(Debug, Clone, Serialize, Deserialize)]
@@ -18,6 +21,7 @@ pub struct Extension {
     pub description: Option<String>,
 
     /// MCP servers defined in the extension
+    #[serde(serialize_with = "serialize_sorted")]
     pub mcp_servers: HashMap<String, McpServerConfig>,
 
     /// Context file name (e.g., "GITHUB.md", "DATABASE.md")
@@ -26,6 +30,23 @@ pub struct Extension {
     /// Content of the context file
     pub context_content: Option<String>,
 
+    /// Whether new profiles should include this extension without being asked
+    #[serde(default)]
+    pub enabled_by_default: bool,
+
+    /// Oldest Gemini CLI version the extension works with
+    #[serde(default)]
+    pub min_gemini_version: Option<String>,
+
+    /// Operating systems the extension works on, e.g. "darwin" or "linux".
+    /// Empty means it works everywhere.
+    #[serde(default)]
+    pub platforms: Vec<String>,
+
+    /// Icon shown next to the name in place of the default
+    #[serde(default)]
+    pub icon: Option<String>,
+
     /// Our metadata
     pub metadata: ExtensionMetadata,
 }
@@ -46,6 +67,7 @@ pub struct McpServerConfig {
     pub cwd: Option<String>,
 
     /// Environment variables (with $VAR_NAME syntax)
+    #[serde(serialize_with = "serialize_sorted_opt")]
     pub env: Option<HashMap<String, String>>,
 
     /// Timeout in milliseconds
@@ -61,6 +83,10 @@ pub struct ExtensionMetadata {
     /// When the extension was imported
     pub imported_at: DateTime<Utc>,
 
+    /// When the extension was last modified, if ever
+    #[serde(default)]
+    pub updated_at: Option<DateTime<Utc>>,
+
     /// Original source path
     pub source_path: Option<String>,
 
@@ -68,6 +94,197 @@ pub struct ExtensionMetadata {
     pub tags: Vec<String>,
 }
 
+/// The operating system we are running on, named the way manifests list it
+/// ("darwin", "linux", "windows", ...)
+pub fn current_platform() -> &'static str {
+    normalize_platform(std::env::consts::OS)
+}
+
+/// Map the other common names for an OS onto the one manifests use
+fn normalize_platform(name: &str) -> &str {
+    match name {
+        "macos" | "osx" => "darwin",
+        "win32" => "windows",
+        other => other,
+    }
+}
+
+/// Whether `source` points somewhere other machines can reach
+fn is_remote_source(source: &str) -> bool {
+    source.contains("://") || source.starts_with("git@")
+}
+
 impl Extension {
     // Mock data methods removed - extensions should be imported from actual extension packages
+
+    /// When the extension was last installed or edited
+    pub fn last_updated(&self) -> DateTime<Utc> {
+        self.metadata
+            .updated_at
+            .unwrap_or(self.metadata.imported_at)
+    }
+
+    /// MCP servers ordered by name, so displays and generated files don't
+    /// reshuffle between runs
+    pub fn sorted_mcp_servers(&self) -> Vec<(&String, &McpServerConfig)> {
+        sorted_entries(&self.mcp_servers)
+    }
+
+    /// The manifest's icon, ignoring a blank one
+    pub fn display_icon(&self) -> Option<&str> {
+        self.icon
+            .as_deref()
+            .map(str::trim)
+            .filter(|i| !i.is_empty())
+    }
+
+    /// Something a teammate can run or search for to get this extension.
+    ///
+    /// A remote source becomes a `gemini extensions install` command. Local
+    /// installs have nothing shareable, so the ID is given with a note.
+    pub fn install_hint(&self) -> String {
+        match self.metadata.source_path.as_deref().map(str::trim) {
+            Some(source) if is_remote_source(source) => {
+                format!("gemini extensions install {source}")
+            }
+            _ => format!(
+                "{} (installed from a local path, no shareable source)",
+                self.id
+            ),
+        }
+    }
+
+    /// The `gemini-extension.json` written when the extension is installed.
+    ///
+    /// Keys come out sorted and unset server fields are left out, so the same
+    /// extension always produces the same file. The context is always
+    /// installed as `GEMINI.md`.
+    pub fn manifest(&self) -> serde_json::Value {
+        let mut manifest = serde_json::json!({
+            "name": self.name,
+            "version": self.version,
+        });
+        if let Some(description) = &self.description {
+            manifest["description"] = description.as_str().into();
+        }
+        if self.context_content.is_some() {
+            manifest["contextFileName"] = "GEMINI.md".into();
+        }
+
+        let mut servers = serde_json::to_value(&self.mcp_servers).unwrap_or_default();
+        for server in servers
+            .as_object_mut()
+            .into_iter()
+            .flat_map(|s| s.values_mut())
+        {
+            if let Some(fields) = server.as_object_mut() {
+                fields.retain(|_, value| !value.is_null());
+            }
+        }
+        manifest["mcpServers"] = servers;
+        manifest
+    }
+
+    /// Whether the extension lists `platform` among the operating systems it
+    /// supports. An extension that lists none supports them all.
+    pub fn supports_platform(&self, platform: &str) -> bool {
+        let platform = normalize_platform(platform);
+        self.platforms.is_empty()
+            || self
+                .platforms
+                .iter()
+                .any(|p| normalize_platform(&p.trim().to_lowercase()) == platform)
+    }
+
+    /// Whether none of `profiles` enables this extension
+    pub fn is_orphan(&self, profiles: &[Profile]) -> bool {
+        !profiles.iter().any(|p| p.extension_ids.contains(&self.id))
+    }
+
+    /// Check the extension is complete and every MCP server can be written
+    /// safely to the Gemini config. Errors name the offending field.
+    pub fn validate(&self) -> Result<(), String> {
+        if self.name.trim().is_empty() {
+            return Err("name is required".to_string());
+        }
+        if self.version.trim().is_empty() {
+            return Err("version is required".to_string());
+        }
+        if let Some(min) = &self.min_gemini_version
+            && semver::Version::parse(min.trim()).is_err()
+        {
+            return Err(format!("minGeminiVersion '{min}' is not a valid version"));
+        }
+        for (name, server) in self.sorted_mcp_servers() {
+            server
+                .validate()
+                .map_err(|e| format!("MCP server '{name}': {e}"))?;
+        }
+        Ok(())
+    }
+}
+
+impl McpServerConfig {
+    /// Reject control characters (null bytes, raw newlines, ...) in the command,
+    /// args and working directory, which would otherwise end up in the
+    /// generated config. Tabs are allowed since they are harmless inside a
+    /// quoted argument.
+    ///
+    /// Environment variables need names a shell would accept, and values
+    /// held to the same rules as a profile's environment.
+    pub fn validate(&self) -> Result<(), String> {
+        if self.command.as_deref().is_some_and(has_control_chars) {
+            return Err("command contains control characters".to_string());
+        }
+        for (i, arg) in self.args.iter().flatten().enumerate() {
+            if has_control_chars(arg) {
+                return Err(format!("argument {} contains control characters", i + 1));
+            }
+        }
+        if self.cwd.as_deref().is_some_and(has_control_chars) {
+            return Err("cwd contains control characters".to_string());
+        }
+        if let Some(env) = &self.env {
+            for (key, value) in sorted_entries(env) {
+                if !is_variable_name(key) {
+                    return Err(format!("env '{key}' is not a valid variable name"));
+                }
+                check_env_value(value).map_err(|e| format!("env {key} {e}"))?;
+            }
+        }
+        Ok(())
+    }
+}
+
+/// Entries of a map in key order. `HashMap` iteration order changes from run
+/// to run, so anything shown to the user or written out goes through this.
+pub fn sorted_entries<V>(map: &HashMap<String, V>) -> Vec<(&String, &V)> {
+    let mut entries: Vec<_> = map.iter().collect();
+    entries.sort_by(|a, b| a.0.cmp(b.0));
+    entries
+}
+
+fn serialize_sorted<S, V>(map: &HashMap<String, V>, serializer: S) -> Result<S::Ok, S::Error>
+where
+    S: Serializer,
+    V: Serialize,
+{
+    map.iter().collect::<BTreeMap<_, _>>().serialize(serializer)
+}
+
+fn serialize_sorted_opt<S, V>(
+    map: &Option<HashMap<String, V>>,
+    serializer: S,
+) -> Result<S::Ok, S::Error>
+where
+    S: Serializer,
+    V: Serialize,
+{
+    map.as_ref()
+        .map(|map| map.iter().collect::<BTreeMap<_, _>>())
+        .serialize(serializer)
+}
+
+fn has_control_chars(value: &str) -> bool {
+    value.chars().any(|c| c.is_control() && c != '\t')
 }
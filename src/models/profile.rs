@@ -14,6 +14,11 @@ pub struct Profile {
     /// Optional description
     pub description: Option<String>,
 
+    /// ID of a profile this one builds on. Its extensions, variables and
+    /// working directory apply wherever this profile doesn't set its own.
+    #[serde(default, skip_serializing_if = "Option::is_none")]
+    pub inherits: Option<String>,
+
     /// Extension IDs included in this profile
     pub extension_ids: Vec<String>,
 
@@ -38,6 +43,14 @@ pub struct LaunchConfig {
 
     /// Remove extensions directory after Gemini exits
     pub cleanup_on_exit: bool,
+
+    /// Start Gemini from an empty environment instead of inheriting ours
+    #[serde(default)]
+    pub clean_environment: bool,
+
+    /// OS variables still passed through when `clean_environment` is set
+    #[serde(default = "default_env_allowlist")]
+    pub env_allowlist: Vec<String>,
 }
 
 impl Default for LaunchConfig {
@@ -45,10 +58,20 @@ impl Default for LaunchConfig {
         Self {
             clean_launch: false,
             cleanup_on_exit: true, // Default to cleaning up after ourselves
+            clean_environment: false,
+            env_allowlist: default_env_allowlist(),
         }
     }
 }
 
+/// Variables a clean environment keeps so Gemini can still find its tools,
+/// home directory and terminal
+pub fn default_env_allowlist() -> Vec<String> {
+    ["PATH", "HOME", "USER", "SHELL", "TERM", "LANG", "TMPDIR"]
+        .map(String::from)
+        .to_vec()
+}
+
 #[derive(Debug, Clone, Serialize, Deserialize)]
 pub struct ProfileMetadata {
     /// When the profile was created
@@ -79,6 +102,83 @@ impl Profile {
         }
     }
 
+    /// A copy of the profile without the `skipped` extensions, for launching
+    /// without them once. The profile itself is left as it is.
+    pub fn without_extensions(&self, skipped: &[String]) -> Profile {
+        let mut profile = self.clone();
+        profile.extension_ids.retain(|id| !skipped.contains(id));
+        profile
+    }
+
+    /// Fill in what this profile leaves unset from `parent`: the parent's
+    /// extensions come first, and its variables and working directory are
+    /// used where this profile has none of its own. Returns the names of the
+    /// parent's variables this profile sets to something else, sorted.
+    pub fn inherit_from(&mut self, parent: &Profile) -> Vec<String> {
+        let mut extension_ids: Vec<String> = parent
+            .extension_ids
+            .iter()
+            .filter(|id| !self.extension_ids.contains(id))
+            .cloned()
+            .collect();
+        extension_ids.append(&mut self.extension_ids);
+        self.extension_ids = extension_ids;
+
+        let mut overridden = Vec::new();
+        for (key, value) in &parent.environment_variables {
+            match self.environment_variables.get(key) {
+                Some(own) if own != value => overridden.push(key.clone()),
+                Some(_) => {}
+                None => {
+                    self.environment_variables
+                        .insert(key.clone(), value.clone());
+                }
+            }
+        }
+        if self.working_directory.is_none() {
+            self.working_directory = parent.working_directory.clone();
+        }
+
+        overridden.sort();
+        overridden
+    }
+
+    /// Take up the settings an extension suggests: include the extension, so
+    /// its MCP servers come along, and set the suggested variables the profile
+    /// doesn't already define. Returns the names of the variables set, sorted.
+    pub fn apply_defaults(
+        &mut self,
+        extension_id: &str,
+        defaults: &ProfileDefaults,
+    ) -> Vec<String> {
+        if !self.extension_ids.iter().any(|id| id == extension_id) {
+            self.extension_ids.push(extension_id.to_string());
+        }
+
+        let mut added = Vec::new();
+        for (key, value) in &defaults.environment_variables {
+            if !self.environment_variables.contains_key(key) {
+                self.environment_variables
+                    .insert(key.clone(), value.clone());
+                added.push(key.clone());
+            }
+        }
+        added.sort();
+        added
+    }
+
+    /// Environment values that would fail or misbehave at launch, one message
+    /// per variable, sorted by name
+    pub fn environment_problems(&self) -> Vec<String> {
+        let mut problems: Vec<String> = self
+            .environment_variables
+            .iter()
+            .filter_map(|(key, value)| check_env_value(value).err().map(|e| format!("{key}: {e}")))
+            .collect();
+        problems.sort();
+        problems
+    }
+
     /// Get a summary of what's included
     pub fn summary(&self) -> String {
         let ext_count = self.extension_ids.len();
@@ -93,3 +193,116 @@ impl Profile {
         )
     }
 }
+
+/// Whether `name` can name an environment variable: letters, digits and
+/// underscores, not starting with a digit
+pub fn is_variable_name(name: &str) -> bool {
+    name.chars()
+        .next()
+        .is_some_and(|c| c.is_ascii_alphabetic() || c == '_')
+        && name.chars().all(|c| c.is_ascii_alphanumeric() || c == '_')
+}
+
+/// Check an environment value's syntax: no control characters, and every
+/// `${` reference closed and naming a variable
+pub fn check_env_value(value: &str) -> Result<(), String> {
+    if value.chars().any(|c| c.is_control() && c != '\t') {
+        return Err("contains control characters".to_string());
+    }
+    parse_env_value(value).map(|_| ())
+}
+
+/// A piece of an environment value
+#[derive(Debug, Clone, Copy, PartialEq, Eq)]
+pub enum EnvPart<'a> {
+    /// Text used as it is
+    Text(&'a str),
+    /// A `$NAME` or `${NAME}` reference to the named variable
+    Variable(&'a str),
+}
+
+/// Split an environment value into text and variable references, the way a
+/// launch expands it. `$$` is a literal `$`, as is a `$` that starts no
+/// reference; a `${` that isn't closed or doesn't name a variable is an error.
+pub fn parse_env_value(value: &str) -> Result<Vec<EnvPart<'_>>, String> {
+    let mut parts = Vec::new();
+    let mut rest = value;
+
+    while let Some(start) = rest.find('$') {
+        if start > 0 {
+            parts.push(EnvPart::Text(&rest[..start]));
+        }
+        let after = &rest[start + 1..];
+
+        if let Some(tail) = after.strip_prefix('$') {
+            parts.push(EnvPart::Text("$"));
+            rest = tail;
+        } else if let Some(braced) = after.strip_prefix('{') {
+            let Some(end) = braced.find('}') else {
+                return Err("has an unclosed '${'".to_string());
+            };
+            let name = &braced[..end];
+            if !is_variable_name(name) {
+                return Err(format!("'${{{name}}}' doesn't name a variable"));
+            }
+            parts.push(EnvPart::Variable(name));
+            rest = &braced[end + 1..];
+        } else if after.starts_with(|c: char| c.is_ascii_alphabetic() || c == '_') {
+            let end = after
+                .find(|c: char| !(c.is_ascii_alphanumeric() || c == '_'))
+                .unwrap_or(after.len());
+            parts.push(EnvPart::Variable(&after[..end]));
+            rest = &after[end..];
+        } else {
+            parts.push(EnvPart::Text("$"));
+            rest = after;
+        }
+    }
+    if !rest.is_empty() {
+        parts.push(EnvPart::Text(rest));
+    }
+    Ok(parts)
+}
+
+/// Profile settings an extension manifest suggests in its optional
+/// `profileDefaults` block. They are only ever applied once the user agrees.
+#[derive(Debug, Clone, Default, PartialEq, Deserialize)]
+pub struct ProfileDefaults {
+    #[serde(rename = "environmentVariables", alias = "env", default)]
+    pub environment_variables: HashMap<String, String>,
+}
+
+/// IDs a new profile can't take. "default" and "system" would read as special
+/// profiles; the rest are device names Windows refuses as file names.
+pub const RESERVED_IDS: &[&str] = &[
+    "default", "system", "con", "prn", "aux", "nul", "com1", "com2", "com3", "com4", "com5",
+    "com6", "com7", "com8", "com9", "lpt1", "lpt2", "lpt3", "lpt4", "lpt5", "lpt6", "lpt7", "lpt8",
+    "lpt9",
+];
+
+/// Derive a profile ID from a display name: lowercase letters and digits, with
+/// one hyphen wherever spaces, hyphens, underscores or dots separate words.
+/// Other characters are dropped.
+pub fn profile_id_from_name(name: &str) -> Result<String, String> {
+    let id = name
+        .to_lowercase()
+        .chars()
+        .filter_map(|c| match c {
+            c if c.is_alphanumeric() => Some(c),
+            ' ' | '-' | '_' | '.' => Some('-'),
+            _ => None,
+        })
+        .collect::<String>()
+        .split('-')
+        .filter(|s| !s.is_empty())
+        .collect::<Vec<_>>()
+        .join("-");
+
+    if id.is_empty() {
+        return Err("the name needs at least one letter or digit".to_string());
+    }
+    if RESERVED_IDS.contains(&id.as_str()) {
+        return Err(format!("'{id}' is reserved, choose another name"));
+    }
+    Ok(id)
+}
@@ -1,35 +1,130 @@
-use std::fs;
+use std::fs::{self, File, OpenOptions};
 use std::path::{Path, PathBuf};
+use std::thread;
 
+use chrono::Utc;
 use color_eyre::{Result, eyre::eyre};
-use serde::{Serialize, de::DeserializeOwned};
+use serde::{Deserialize, Serialize, de::DeserializeOwned};
+use tracing::warn;
+
+use crate::{
+    launcher::ensure_within,
+    models::{Extension, Profile, profile::profile_id_from_name},
+};
+
+/// Upper bound on threads used to parse stored items
+const MAX_SCAN_WORKERS: usize = 8;
+
+/// Directories with fewer items than this are parsed on the calling thread
+const PARALLEL_SCAN_THRESHOLD: usize = 32;
+
+/// On-disk format for profile files
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq)]
+pub enum ProfileFormat {
+    /// Plain JSON, written by the manager itself
+    #[default]
+    Json,
+    /// JSON5, convenient for hand-written or tool-generated profiles
+    Json5,
+}
+
+impl ProfileFormat {
+    /// All formats, in the order they are looked up
+    pub const ALL: [ProfileFormat; 2] = [ProfileFormat::Json, ProfileFormat::Json5];
+
+    /// File extension used for this format
+    pub fn extension(self) -> &'static str {
+        match self {
+            ProfileFormat::Json => "json",
+            ProfileFormat::Json5 => "json5",
+        }
+    }
+}
+
+/// What to do when an imported profile's ID is already taken
+#[derive(Debug, Clone, Copy, Default, PartialEq, Eq, clap::ValueEnum)]
+pub enum ConflictResolution {
+    /// Leave the existing profile alone and drop the imported one
+    Skip,
+    /// Store the imported profile under a fresh ID
+    #[default]
+    Rename,
+    /// Replace the existing profile with the imported one
+    Overwrite,
+}
+
+/// Result of importing a single profile
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub enum ImportOutcome {
+    /// Saved under its original ID
+    Imported(String),
+    /// Saved under a new ID because the original was taken
+    Renamed { from: String, to: String },
+    /// Replaced an existing profile with the same ID
+    Overwritten(String),
+    /// Not saved because the ID was taken
+    Skipped(String),
+}
+
+/// Format version written into exported profile files. Files from a newer
+/// version are refused rather than half understood.
+pub const PROFILE_EXPORT_VERSION: u32 = 1;
 
-use crate::models::{Extension, Profile};
+/// A profile as written to a file for sharing
+#[derive(Debug, Serialize, Deserialize)]
+#[serde(rename_all = "camelCase")]
+pub struct ProfileExport {
+    pub format_version: u32,
+    pub profile: Profile,
+}
 
 /// Storage manager for persisting application data
 #[derive(Clone)]
 pub struct Storage {
     data_dir: PathBuf,
+    extensions_dir: PathBuf,
+    profiles_dir: PathBuf,
 }
 
 impl Storage {
     /// Create a new storage instance with the default data directory
     pub fn new() -> Result<Self> {
         let data_dir = Self::get_data_dir()?;
-        Ok(Self { data_dir })
+        Ok(Self::with_data_dir(data_dir))
     }
 
     /// Create a storage instance with a custom data directory
-    #[allow(dead_code)]
     pub fn with_data_dir(data_dir: PathBuf) -> Self {
-        Self { data_dir }
+        Self {
+            extensions_dir: data_dir.join("extensions"),
+            profiles_dir: data_dir.join("profiles"),
+            data_dir,
+        }
+    }
+
+    /// Keep extensions and profiles in other directories, for example ones
+    /// left by another tool. Relative paths are taken from the data
+    /// directory; `None` keeps the usual location.
+    pub fn with_layout(mut self, extensions: Option<&Path>, profiles: Option<&Path>) -> Self {
+        if let Some(dir) = extensions {
+            self.extensions_dir = self.data_dir.join(dir);
+        }
+        if let Some(dir) = profiles {
+            self.profiles_dir = self.data_dir.join(dir);
+        }
+        self
+    }
+
+    /// The data directory used when none is given
+    pub fn default_data_dir() -> Result<PathBuf> {
+        Ok(dirs::data_dir()
+            .ok_or_else(|| eyre!("Could not determine data directory"))?
+            .join("gemini-cli-manager"))
     }
 
     /// Get the default data directory for the application
     fn get_data_dir() -> Result<PathBuf> {
-        let data_dir = dirs::data_dir()
-            .ok_or_else(|| eyre!("Could not determine data directory"))?
-            .join("gemini-cli-manager");
+        let data_dir = Self::default_data_dir()?;
 
         // Ensure the directory exists
         fs::create_dir_all(&data_dir)?;
@@ -37,11 +132,13 @@ impl Storage {
         Ok(data_dir)
     }
 
-    /// Initialize storage directories
+    /// Initialize storage directories, failing if either can't be written
     pub fn init(&self) -> Result<()> {
-        // Create subdirectories
-        fs::create_dir_all(self.data_dir.join("extensions"))?;
-        fs::create_dir_all(self.data_dir.join("profiles"))?;
+        for dir in [&self.extensions_dir, &self.profiles_dir] {
+            fs::create_dir_all(dir)
+                .map_err(|e| eyre!("Could not create {}: {e}", dir.display()))?;
+            Self::check_writable(dir)?;
+        }
 
         // Extensions should be imported from actual extension packages
         // Profiles should be created by users
@@ -49,53 +146,220 @@ impl Storage {
         Ok(())
     }
 
+    /// Write and remove a probe file, so a read-only directory is reported
+    /// at startup rather than on the first save
+    fn check_writable(dir: &Path) -> Result<()> {
+        let probe = dir.join(".write-test");
+        fs::write(&probe, b"")
+            .and_then(|_| fs::remove_file(&probe))
+            .map_err(|e| eyre!("{} is not writable: {e}", dir.display()))
+    }
+
+    /// Copy every extension and profile into `target`, which must be empty.
+    ///
+    /// Meant for bootstrapping a teammate's data directory, so no copied
+    /// profile is left marked as the default. Returns how many extensions and
+    /// profiles were copied.
+    pub fn copy_into(&self, target: &Storage) -> Result<(usize, usize)> {
+        if !self.data_dir.is_dir() {
+            return Err(eyre!("{} is not a directory", self.data_dir.display()));
+        }
+        target.init()?;
+        if !target.list_extensions()?.is_empty() || !target.list_profiles()?.is_empty() {
+            return Err(eyre!(
+                "{} already contains extensions or profiles",
+                target.data_dir.display()
+            ));
+        }
+
+        let extensions = self.list_extensions()?;
+        for extension in &extensions {
+            target.save_extension(extension)?;
+        }
+
+        let profiles = self.list_profiles()?;
+        for profile in &profiles {
+            let mut profile = profile.clone();
+            profile.metadata.is_default = false;
+            target.save_profile(&profile)?;
+        }
+
+        Ok((extensions.len(), profiles.len()))
+    }
+
     // Extension methods
 
     /// Save an extension to storage
     pub fn save_extension(&self, extension: &Extension) -> Result<()> {
-        let path = self
-            .data_dir
-            .join("extensions")
-            .join(format!("{}.json", extension.id));
+        extension.validate().map_err(|e| eyre!(e))?;
+        let path = self.extensions_dir.join(format!("{}.json", extension.id));
         self.save_json(&path, extension)
     }
 
     /// Load an extension by ID
     pub fn load_extension(&self, id: &str) -> Result<Extension> {
-        let path = self.data_dir.join("extensions").join(format!("{id}.json"));
+        let path = self.extensions_dir.join(format!("{id}.json"));
         self.load_json(&path)
     }
 
     /// List all extensions
     pub fn list_extensions(&self) -> Result<Vec<Extension>> {
-        self.list_items("extensions")
+        self.list_items(&self.extensions_dir, &["json"])
+    }
+
+    /// Find a stored extension that `candidate` would duplicate.
+    ///
+    /// Imports are given a fresh id, so the same extension installed from two
+    /// different places is matched on its id or its (case-insensitive) name.
+    pub fn find_duplicate_extension(&self, candidate: &Extension) -> Result<Option<Extension>> {
+        let name = candidate.name.trim().to_lowercase();
+        Ok(self.list_extensions()?.into_iter().find(|existing| {
+            existing.id == candidate.id || existing.name.trim().to_lowercase() == name
+        }))
+    }
+
+    /// Set each extension's `enabled_by_default` to whether `profile` uses it,
+    /// so new profiles start from the same set. Returns how many changed.
+    pub fn sync_defaults_from_profile(&self, profile: &Profile) -> Result<usize> {
+        let mut changed = 0;
+        for mut extension in self.list_extensions()? {
+            let enabled = profile.extension_ids.contains(&extension.id);
+            if extension.enabled_by_default != enabled {
+                extension.enabled_by_default = enabled;
+                self.save_extension(&extension)?;
+                changed += 1;
+            }
+        }
+        Ok(changed)
+    }
+
+    /// Set `profile`'s extensions to exactly those marked `enabled_by_default`,
+    /// the reverse of `sync_defaults_from_profile`. Returns the saved profile.
+    pub fn capture_defaults_into_profile(&self, profile: &Profile) -> Result<Profile> {
+        let mut profile = profile.clone();
+        profile.extension_ids = self
+            .list_extensions()?
+            .into_iter()
+            .filter(|e| e.enabled_by_default)
+            .map(|e| e.id)
+            .collect();
+        profile.metadata.updated_at = Utc::now();
+        self.save_profile_as(&profile, self.stored_format(&profile.id))?;
+        Ok(profile)
+    }
+
+    /// Extensions that no profile enables
+    pub fn orphaned_extensions(&self) -> Result<Vec<Extension>> {
+        let profiles = self.list_profiles()?;
+        Ok(self
+            .list_extensions()?
+            .into_iter()
+            .filter(|e| e.is_orphan(&profiles))
+            .collect())
     }
 
     /// Delete an extension
     #[allow(dead_code)]
     pub fn delete_extension(&self, id: &str) -> Result<()> {
-        let path = self.data_dir.join("extensions").join(format!("{id}.json"));
+        let path = self.extensions_dir.join(format!("{id}.json"));
         if path.exists() {
             fs::remove_file(path)?;
         }
+        let notes = self.extension_notes_file(id);
+        if notes.exists() {
+            fs::remove_file(notes)?;
+        }
+        Ok(())
+    }
+
+    /// Where the user's notes on an extension live. They are kept apart from
+    /// the extension itself so updates and re-imports leave them alone.
+    pub fn extension_notes_file(&self, id: &str) -> PathBuf {
+        self.data_dir.join("notes").join(format!("{id}.md"))
+    }
+
+    /// The user's notes on an extension, if there are any
+    pub fn load_extension_notes(&self, id: &str) -> Result<Option<String>> {
+        let path = self.extension_notes_file(id);
+        if !path.exists() {
+            return Ok(None);
+        }
+        let notes = fs::read_to_string(path)?;
+        Ok(Some(notes).filter(|n| !n.trim().is_empty()))
+    }
+
+    /// Replace the notes on an extension. Blank notes remove the file.
+    pub fn save_extension_notes(&self, id: &str, notes: &str) -> Result<()> {
+        let path = self.extension_notes_file(id);
+        if notes.trim().is_empty() {
+            if path.exists() {
+                fs::remove_file(path)?;
+            }
+            return Ok(());
+        }
+        if let Some(parent) = path.parent() {
+            fs::create_dir_all(parent)?;
+        }
+        fs::write(path, notes)?;
+        Ok(())
+    }
+
+    /// Where the summary of the most recent launch is kept, so what it set up
+    /// can still be checked after the session ends
+    pub fn last_launch_config_file(&self) -> PathBuf {
+        self.data_dir.join("last-launch-config.json")
+    }
+
+    /// Record the summary of a launch, replacing the previous one
+    pub fn save_last_launch_config(&self, config: &str) -> Result<()> {
+        fs::write(self.last_launch_config_file(), config)?;
         Ok(())
     }
 
+    /// The summary of the most recent launch, if anything has been launched
+    pub fn load_last_launch_config(&self) -> Result<Option<String>> {
+        let path = self.last_launch_config_file();
+        if !path.exists() {
+            return Ok(None);
+        }
+        Ok(Some(fs::read_to_string(path)?))
+    }
+
     // Profile methods
 
-    /// Save a profile to storage
+    /// Save a profile to storage, keeping the format it is already stored in
     pub fn save_profile(&self, profile: &Profile) -> Result<()> {
-        let path = self
-            .data_dir
-            .join("profiles")
-            .join(format!("{}.json", profile.id));
-        self.save_json(&path, profile)
+        self.save_profile_as(profile, self.stored_format(&profile.id))
+    }
+
+    /// Save a profile in a specific format, replacing any copy stored in another format
+    pub fn save_profile_as(&self, profile: &Profile, format: ProfileFormat) -> Result<()> {
+        let _lock = self.lock_profiles()?;
+        self.write_profile(profile, format)
+    }
+
+    /// Write a profile file; callers must hold the profiles lock
+    fn write_profile(&self, profile: &Profile, format: ProfileFormat) -> Result<()> {
+        let path = self.profile_path(&profile.id, format);
+        ensure_within(&self.profiles_dir, &path)?;
+        let contents = match format {
+            ProfileFormat::Json => serde_json::to_string_pretty(profile)?,
+            ProfileFormat::Json5 => json5::to_string(profile)?,
+        };
+        write_atomic(&path, &contents)?;
+
+        for other in ProfileFormat::ALL.into_iter().filter(|f| *f != format) {
+            let stale = self.profile_path(&profile.id, other);
+            if stale.exists() {
+                fs::remove_file(stale)?;
+            }
+        }
+        Ok(())
     }
 
     /// Load a profile by ID
     pub fn load_profile(&self, id: &str) -> Result<Profile> {
-        let path = self.data_dir.join("profiles").join(format!("{id}.json"));
-        let profile: Profile = self.load_json(&path)?;
+        let profile: Profile = self.load_json(&self.profile_file(id))?;
 
         // Ensure backward compatibility - if launch_config is missing, it will use default
         // This is handled by serde's #[serde(default)] attribute on the field
@@ -103,35 +367,229 @@ impl Storage {
         Ok(profile)
     }
 
+    /// Load a profile with the profiles it inherits from merged in, nearest
+    /// first, so it holds everything a launch needs
+    pub fn resolve_profile(&self, id: &str) -> Result<Profile> {
+        self.resolve(self.load_profile(id)?)
+    }
+
+    /// Merge into `profile` the profiles it inherits from, as
+    /// [`Storage::resolve_profile`] does for a stored one
+    pub fn resolve(&self, profile: Profile) -> Result<Profile> {
+        self.resolve_with_overrides(profile)
+            .map(|(profile, _)| profile)
+    }
+
+    /// Like [`Storage::resolve`], also returning the names of the inherited
+    /// variables replaced somewhere along the chain, sorted
+    pub fn resolve_with_overrides(&self, mut profile: Profile) -> Result<(Profile, Vec<String>)> {
+        let mut overridden = Vec::new();
+        let mut seen = vec![profile.id.clone()];
+        let mut next = profile.inherits.clone();
+
+        while let Some(parent_id) = next {
+            if seen.contains(&parent_id) {
+                seen.push(parent_id);
+                return Err(eyre!("profile inheritance loops: {}", seen.join(" -> ")));
+            }
+            let parent = self.load_profile(&parent_id).map_err(|e| {
+                eyre!(
+                    "'{}' inherits from '{parent_id}', which can't be loaded: {e}",
+                    seen[seen.len() - 1]
+                )
+            })?;
+            overridden.extend(profile.inherit_from(&parent));
+            next = parent.inherits;
+            seen.push(parent_id);
+        }
+
+        overridden.sort();
+        overridden.dedup();
+        Ok((profile, overridden))
+    }
+
     /// List all profiles
     pub fn list_profiles(&self) -> Result<Vec<Profile>> {
-        self.list_items("profiles")
+        let extensions = ProfileFormat::ALL.map(ProfileFormat::extension);
+        self.list_items(&self.profiles_dir, &extensions)
     }
 
     /// Delete a profile
     pub fn delete_profile(&self, id: &str) -> Result<()> {
-        let path = self.data_dir.join("profiles").join(format!("{id}.json"));
-        if path.exists() {
-            fs::remove_file(path)?;
+        for format in ProfileFormat::ALL {
+            let path = self.profile_path(id, format);
+            if path.exists() {
+                fs::remove_file(path)?;
+            }
         }
         Ok(())
     }
 
+    /// Whether a profile with this ID is stored in any format
+    fn profile_exists(&self, id: &str) -> bool {
+        ProfileFormat::ALL
+            .into_iter()
+            .any(|format| self.profile_path(id, format).exists())
+    }
+
+    /// File a profile is stored in, whichever format it was saved as
+    pub fn profile_file(&self, id: &str) -> PathBuf {
+        ProfileFormat::ALL
+            .into_iter()
+            .map(|format| self.profile_path(id, format))
+            .find(|path| path.exists())
+            .unwrap_or_else(|| self.profile_path(id, ProfileFormat::default()))
+    }
+
+    /// Load a hand-edited copy of profile `id` and store it if it is still valid.
+    ///
+    /// The stored profile is left untouched when the edited file doesn't parse,
+    /// changes the profile's ID, or leaves it without a name.
+    pub fn apply_edited_profile(&self, id: &str, edited: &Path) -> Result<Profile> {
+        let profile: Profile = self
+            .load_json(edited)
+            .map_err(|e| eyre!("invalid profile file: {e}"))?;
+        if profile.id != id {
+            return Err(eyre!(
+                "the profile ID can't be changed (expected '{id}', found '{}')",
+                profile.id
+            ));
+        }
+        if profile.name.trim().is_empty() {
+            return Err(eyre!("name is required"));
+        }
+        let problems = profile.environment_problems();
+        if !problems.is_empty() {
+            return Err(eyre!("invalid environment: {}", problems.join("; ")));
+        }
+
+        self.save_profile_as(&profile, self.stored_format(id))?;
+        Ok(profile)
+    }
+
+    /// Format profile `id` is currently stored in, so rewrites keep it
+    fn stored_format(&self, id: &str) -> ProfileFormat {
+        if self.profile_file(id).extension().and_then(|s| s.to_str())
+            == Some(ProfileFormat::Json5.extension())
+        {
+            ProfileFormat::Json5
+        } else {
+            ProfileFormat::Json
+        }
+    }
+
+    /// Path of a profile file in the given format
+    fn profile_path(&self, id: &str, format: ProfileFormat) -> PathBuf {
+        self.profiles_dir
+            .join(format!("{id}.{}", format.extension()))
+    }
+
+    /// Import a profile, asking `on_conflict` what to do if its ID already exists.
+    ///
+    /// The callback receives the existing profile and the one being imported, so
+    /// interactive callers can prompt and non-interactive ones can apply a fixed
+    /// policy (typically `ConflictResolution::default()`).
+    pub fn import_profile<F>(&self, mut profile: Profile, on_conflict: F) -> Result<ImportOutcome>
+    where
+        F: FnOnce(&Profile, &Profile) -> ConflictResolution,
+    {
+        if !self.profile_exists(&profile.id) {
+            self.save_profile(&profile)?;
+            return Ok(ImportOutcome::Imported(profile.id));
+        }
+        let existing = self.load_profile(&profile.id)?;
+
+        match on_conflict(&existing, &profile) {
+            ConflictResolution::Skip => Ok(ImportOutcome::Skipped(profile.id)),
+            ConflictResolution::Overwrite => {
+                // Keep the replaced profile's default flag, so overwriting
+                // never adds or removes a default
+                profile.metadata.is_default = existing.metadata.is_default;
+                self.save_profile(&profile)?;
+                Ok(ImportOutcome::Overwritten(profile.id))
+            }
+            ConflictResolution::Rename => {
+                let from = profile.id.clone();
+                let mut suffix = 2;
+                while self.profile_exists(&format!("{from}-{suffix}")) {
+                    suffix += 1;
+                }
+
+                let id = format!("{from}-{suffix}");
+                profile.id = id.clone();
+                profile.name = format!("{} ({suffix})", profile.name);
+                // An imported copy shouldn't steal the default flag
+                profile.metadata.is_default = false;
+                self.save_profile(&profile)?;
+                Ok(ImportOutcome::Renamed { from, to: id })
+            }
+        }
+    }
+
+    /// Write profile `id` to `path` so it can be shared and imported elsewhere
+    pub fn export_profile(&self, id: &str, path: &Path) -> Result<()> {
+        let export = ProfileExport {
+            format_version: PROFILE_EXPORT_VERSION,
+            profile: self.load_profile(id)?,
+        };
+        self.save_json(path, &export)
+    }
+
+    /// Import a profile written by [`Storage::export_profile`].
+    ///
+    /// The profile must have a name, an ID of the form the app itself
+    /// generates, and well-formed environment values. It arrives with fresh
+    /// timestamps and never as the default; `on_conflict` decides what
+    /// happens if its ID is already taken, as in [`Storage::import_profile`].
+    pub fn import_profile_file<F>(&self, path: &Path, on_conflict: F) -> Result<ImportOutcome>
+    where
+        F: FnOnce(&Profile, &Profile) -> ConflictResolution,
+    {
+        let export: ProfileExport = self
+            .load_json(path)
+            .map_err(|e| eyre!("not a profile export: {e}"))?;
+        if export.format_version > PROFILE_EXPORT_VERSION {
+            return Err(eyre!(
+                "profile export version {} is newer than this version of the app supports",
+                export.format_version
+            ));
+        }
+
+        let mut profile = export.profile;
+        if profile.name.trim().is_empty() {
+            return Err(eyre!("name is required"));
+        }
+        // The ID becomes a file name, so only accept IDs the app could have
+        // derived itself: no separators, dots or reserved device names
+        if profile_id_from_name(&profile.id).as_deref() != Ok(profile.id.as_str()) {
+            return Err(eyre!("invalid profile ID '{}'", profile.id));
+        }
+        let problems = profile.environment_problems();
+        if !problems.is_empty() {
+            return Err(eyre!("invalid environment: {}", problems.join("; ")));
+        }
+
+        let now = Utc::now();
+        profile.metadata.created_at = now;
+        profile.metadata.updated_at = now;
+        profile.metadata.is_default = false;
+        self.import_profile(profile, on_conflict)
+    }
+
     /// Get the default profile
-    #[allow(dead_code)]
     pub fn get_default_profile(&self) -> Result<Option<Profile>> {
         let profiles = self.list_profiles()?;
         Ok(profiles.into_iter().find(|p| p.metadata.is_default))
     }
 
     /// Set a profile as default
-    #[allow(dead_code)]
     pub fn set_default_profile(&self, id: &str) -> Result<()> {
+        let _lock = self.lock_profiles()?;
         let mut profiles = self.list_profiles()?;
 
         for profile in &mut profiles {
             profile.metadata.is_default = profile.id == id;
-            self.save_profile(profile)?;
+            self.write_profile(profile, self.stored_format(&profile.id))?;
         }
 
         Ok(())
@@ -139,6 +597,22 @@ impl Storage {
 
     // Helper methods
 
+    /// Take an exclusive advisory lock on the profiles directory, held until
+    /// the returned file is dropped.
+    ///
+    /// Two app instances sharing a data directory would otherwise interleave
+    /// their writes to the same profile. The lock isn't reentrant, so a
+    /// method holding it must not call another method that takes it.
+    fn lock_profiles(&self) -> Result<File> {
+        let file = OpenOptions::new()
+            .create(true)
+            .truncate(false)
+            .write(true)
+            .open(self.profiles_dir.join(".lock"))?;
+        file.lock()?;
+        Ok(file)
+    }
+
     /// Save data as JSON
     fn save_json<T: Serialize>(&self, path: &Path, data: &T) -> Result<()> {
         let json = serde_json::to_string_pretty(data)?;
@@ -146,16 +620,26 @@ impl Storage {
         Ok(())
     }
 
-    /// Load data from JSON
+    /// Load data from JSON, or JSON5 for `.json5` files
     fn load_json<T: DeserializeOwned>(&self, path: &Path) -> Result<T> {
         let json = fs::read_to_string(path)?;
-        let data = serde_json::from_str(&json)?;
+        let data = if path.extension().and_then(|s| s.to_str()) == Some("json5") {
+            json5::from_str(&json)?
+        } else {
+            serde_json::from_str(&json)?
+        };
         Ok(data)
     }
 
-    /// List all items in a subdirectory
-    fn list_items<T: DeserializeOwned>(&self, subdir: &str) -> Result<Vec<T>> {
-        let dir = self.data_dir.join(subdir);
+    /// List all items in `dir` stored with one of the given file extensions.
+    ///
+    /// Large directories are parsed on a small pool of threads, but items are
+    /// returned in path order. Files that fail to load are logged and skipped.
+    fn list_items<T: DeserializeOwned + Send>(
+        &self,
+        dir: &Path,
+        extensions: &[&str],
+    ) -> Result<Vec<T>> {
         let mut items = Vec::new();
 
         if dir.exists() {
@@ -163,43 +647,110 @@ impl Storage {
             let mut paths: Vec<PathBuf> = fs::read_dir(dir)?
                 .filter_map(|entry| entry.ok())
                 .map(|entry| entry.path())
-                .filter(|path| path.extension().and_then(|s| s.to_str()) == Some("json"))
+                .filter(|path| {
+                    path.extension()
+                        .and_then(|s| s.to_str())
+                        .is_some_and(|ext| extensions.contains(&ext))
+                })
                 .collect();
 
             // Sort paths to ensure consistent ordering
             paths.sort();
 
-            // Load items in sorted order
-            for path in paths {
-                match self.load_json::<T>(&path) {
-                    Ok(item) => items.push(item),
-                    Err(e) => eprintln!("Warning: Failed to load {path:?}: {e}"),
-                }
+            if paths.len() < PARALLEL_SCAN_THRESHOLD {
+                return Ok(self.load_all(&paths));
             }
+
+            // Each worker loads a contiguous run of paths, so joining the
+            // workers in spawn order keeps the sorted order
+            let workers = thread::available_parallelism()
+                .map_or(1, |n| n.get())
+                .min(MAX_SCAN_WORKERS);
+            let chunk_size = paths.len().div_ceil(workers).max(1);
+            thread::scope(|scope| {
+                let handles: Vec<_> = paths
+                    .chunks(chunk_size)
+                    .map(|chunk| scope.spawn(move || self.load_all::<T>(chunk)))
+                    .collect();
+                for handle in handles {
+                    match handle.join() {
+                        Ok(loaded) => items.extend(loaded),
+                        Err(_) => warn!("A worker panicked while listing {}", dir.display()),
+                    }
+                }
+            });
         }
 
         Ok(items)
     }
 
+    /// Load each path in order, skipping files that fail to load
+    fn load_all<T: DeserializeOwned>(&self, paths: &[PathBuf]) -> Vec<T> {
+        paths
+            .iter()
+            .filter_map(|path| match self.load_json::<T>(path) {
+                Ok(item) => Some(item),
+                Err(e) => {
+                    warn!("Failed to load {path:?}: {e}");
+                    None
+                }
+            })
+            .collect()
+    }
+
     /// Get the data directory path
     #[allow(dead_code)]
     pub fn data_dir(&self) -> &Path {
         &self.data_dir
     }
+
+    /// Directory extensions are stored in
+    pub fn extensions_dir(&self) -> &Path {
+        &self.extensions_dir
+    }
+
+    /// Directory profiles are stored in
+    pub fn profiles_dir(&self) -> &Path {
+        &self.profiles_dir
+    }
+}
+
+/// Write `contents` to `path` through a temporary file beside it, so a
+/// crash or a concurrent reader never sees a half-written file
+fn write_atomic(path: &Path, contents: &str) -> Result<()> {
+    let mut temp = path.as_os_str().to_owned();
+    temp.push(".tmp");
+    let temp = PathBuf::from(temp);
+    fs::write(&temp, contents)?;
+    fs::rename(&temp, path)?;
+    Ok(())
+}
+
+/// Name to show as a reminder when `data_dir` isn't `default_dir`, so edits
+/// to a scratch copy aren't mistaken for the real thing
+pub fn data_dir_badge(data_dir: &Path, default_dir: &Path) -> Option<String> {
+    let resolve = |path: &Path| fs::canonicalize(path).unwrap_or_else(|_| path.to_path_buf());
+    if resolve(data_dir) == resolve(default_dir) {
+        return None;
+    }
+    Some(
+        data_dir
+            .file_name()
+            .map(|name| name.to_string_lossy().into_owned())
+            .unwrap_or_else(|| data_dir.display().to_string()),
+    )
 }
 
 impl Default for Storage {
     fn default() -> Self {
-        Self::new().unwrap_or_else(|_| Self {
-            data_dir: PathBuf::from(".gemini-cli-manager-data"),
-        })
+        Self::new()
+            .unwrap_or_else(|_| Self::with_data_dir(PathBuf::from(".gemini-cli-manager-data")))
     }
 }
 
 #[cfg(test)]
 mod tests {
     use super::*;
-    use chrono::Utc;
     use std::collections::HashMap;
     use tempfile::TempDir;
 
@@ -223,8 +774,13 @@ mod tests {
             mcp_servers: HashMap::new(),
             context_file_name: None,
             context_content: None,
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: crate::models::extension::ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: None,
                 tags: vec!["test".to_string()],
             },
@@ -254,8 +810,13 @@ mod tests {
             mcp_servers: HashMap::new(),
             context_file_name: None,
             context_content: None,
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: crate::models::extension::ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: None,
                 tags: vec![],
             },
@@ -275,6 +836,7 @@ mod tests {
             id: "test-profile".to_string(),
             name: "Test Profile".to_string(),
             description: Some("Test description".to_string()),
+            inherits: None,
             extension_ids: vec![],
             environment_variables: HashMap::new(),
             working_directory: None,
@@ -308,6 +870,7 @@ mod tests {
             id: "profile1".to_string(),
             name: "Profile 1".to_string(),
             description: None,
+            inherits: None,
             extension_ids: vec![],
             environment_variables: HashMap::new(),
             working_directory: None,
@@ -325,6 +888,7 @@ mod tests {
             id: "profile2".to_string(),
             name: "Profile 2".to_string(),
             description: None,
+            inherits: None,
             extension_ids: vec![],
             environment_variables: HashMap::new(),
             working_directory: None,
@@ -1,9 +1,12 @@
 #![allow(dead_code)]
 
 use catppuccin::{Flavor, FlavorColors, PALETTE};
+use color_eyre::{Result, eyre::eyre};
 use lazy_static::lazy_static;
 use ratatui::style::Color;
 use serde::{Deserialize, Serialize};
+use std::fs;
+use std::path::Path;
 use std::sync::Mutex;
 
 /// Available theme flavours from Catppuccin
@@ -26,12 +29,71 @@ impl ThemeFlavour {
     }
 }
 
+/// The colour of each role a theme fills in. Built-in themes take theirs
+/// from a Catppuccin flavour; custom themes list them in a file.
+#[derive(Debug, Clone, Copy, PartialEq, Deserialize)]
+#[serde(deny_unknown_fields)]
+pub struct ThemeColors {
+    pub background: Color,
+    pub surface: Color,
+    pub overlay: Color,
+    pub text_primary: Color,
+    pub text_secondary: Color,
+    pub text_muted: Color,
+    pub text_disabled: Color,
+    pub primary: Color,
+    pub secondary: Color,
+    pub accent: Color,
+    pub highlight: Color,
+    pub success: Color,
+    pub warning: Color,
+    pub error: Color,
+    pub info: Color,
+    pub border: Color,
+    pub border_focused: Color,
+    pub selection: Color,
+    pub selection_bar: Color,
+    pub cursor: Color,
+}
+
+impl ThemeColors {
+    fn from_flavor(colors: &FlavorColors) -> Self {
+        Self {
+            background: colors.base.into(),
+            surface: colors.surface0.into(),
+            overlay: colors.surface1.into(),
+            text_primary: colors.text.into(),
+            text_secondary: colors.subtext1.into(),
+            // Use overlay1 instead of subtext0 for better contrast
+            // overlay1 has better visibility while still being muted
+            text_muted: colors.overlay1.into(),
+            text_disabled: colors.overlay0.into(),
+            primary: colors.blue.into(),
+            secondary: colors.mauve.into(),
+            accent: colors.pink.into(),
+            highlight: colors.yellow.into(),
+            success: colors.green.into(),
+            warning: colors.peach.into(),
+            error: colors.red.into(),
+            info: colors.sky.into(),
+            border: colors.surface2.into(),
+            border_focused: colors.blue.into(),
+            // Use a color with better contrast for selections
+            // Using sapphire with low alpha would be ideal, but ratatui doesn't support alpha
+            // So we'll use surface1 which provides better contrast than surface2
+            selection: colors.surface1.into(),
+            // For list selections, use an accent color for better visibility
+            selection_bar: colors.sapphire.into(),
+            cursor: colors.rosewater.into(),
+        }
+    }
+}
+
 /// A theme defines the colors used throughout the application
 #[derive(Debug, Clone)]
 pub struct Theme {
     pub name: String,
-    flavor: &'static Flavor,
-    colors: &'static FlavorColors,
+    colors: ThemeColors,
 }
 
 impl Theme {
@@ -40,100 +102,93 @@ impl Theme {
         let flavor = flavour.to_flavor();
         Self {
             name: format!("Catppuccin {}", flavor.name),
-            flavor,
-            colors: &flavor.colors,
+            colors: ThemeColors::from_flavor(&flavor.colors),
         }
     }
 
     // Base colors
     pub fn background(&self) -> Color {
-        self.colors.base.into()
+        self.colors.background
     }
 
     pub fn surface(&self) -> Color {
-        self.colors.surface0.into()
+        self.colors.surface
     }
 
     pub fn overlay(&self) -> Color {
-        self.colors.surface1.into()
+        self.colors.overlay
     }
 
     // Text colors
     pub fn text_primary(&self) -> Color {
-        self.colors.text.into()
+        self.colors.text_primary
     }
 
     pub fn text_secondary(&self) -> Color {
-        self.colors.subtext1.into()
+        self.colors.text_secondary
     }
 
     pub fn text_muted(&self) -> Color {
-        // Use overlay1 instead of subtext0 for better contrast
-        // overlay1 has better visibility while still being muted
-        self.colors.overlay1.into()
+        self.colors.text_muted
     }
 
     pub fn text_disabled(&self) -> Color {
-        self.colors.overlay0.into()
+        self.colors.text_disabled
     }
 
     // Accent colors
     pub fn primary(&self) -> Color {
-        self.colors.blue.into()
+        self.colors.primary
     }
 
     pub fn secondary(&self) -> Color {
-        self.colors.mauve.into()
+        self.colors.secondary
     }
 
     pub fn accent(&self) -> Color {
-        self.colors.pink.into()
+        self.colors.accent
     }
 
     pub fn highlight(&self) -> Color {
-        self.colors.yellow.into()
+        self.colors.highlight
     }
 
     // Semantic colors
     pub fn success(&self) -> Color {
-        self.colors.green.into()
+        self.colors.success
     }
 
     pub fn warning(&self) -> Color {
-        self.colors.peach.into()
+        self.colors.warning
     }
 
     pub fn error(&self) -> Color {
-        self.colors.red.into()
+        self.colors.error
     }
 
     pub fn info(&self) -> Color {
-        self.colors.sky.into()
+        self.colors.info
     }
 
     // UI element colors
     pub fn border(&self) -> Color {
-        self.colors.surface2.into()
+        self.colors.border
     }
 
     pub fn border_focused(&self) -> Color {
-        self.colors.blue.into()
+        self.colors.border_focused
     }
 
     pub fn selection(&self) -> Color {
-        // Use a color with better contrast for selections
-        // Using sapphire with low alpha would be ideal, but ratatui doesn't support alpha
-        // So we'll use surface1 which provides better contrast than surface2
-        self.colors.surface1.into()
+        self.colors.selection
     }
 
     pub fn selection_bar(&self) -> Color {
-        // For list selections, use an accent color for better visibility
-        self.colors.sapphire.into()
+        self.colors.selection_bar
     }
 
     pub fn cursor(&self) -> Color {
-        self.colors.rosewater.into()
+        self.colors.cursor
     }
 }
 
@@ -147,6 +202,125 @@ impl Default for Theme {
 lazy_static! {
     /// Global theme instance using Mutex for thread safety
     static ref CURRENT_THEME: Mutex<Theme> = Mutex::new(Theme::default());
+
+    /// Themes loaded from files, offered alongside the built-in ones
+    static ref CUSTOM_THEMES: Mutex<Vec<CustomTheme>> = Mutex::new(Vec::new());
+}
+
+/// Names the built-in themes are selected by
+const BUILT_IN_THEMES: [&str; 4] = ["mocha", "macchiato", "frappe", "latte"];
+
+/// A theme defined in a file rather than built in
+#[derive(Debug, Clone)]
+pub struct CustomTheme {
+    /// What the theme is selected and saved by: its file name without the
+    /// extension, in lowercase
+    pub name: String,
+    pub display_name: String,
+    /// "Dark" or "Light", shown beside the name in Settings
+    pub variant: String,
+    pub colors: ThemeColors,
+}
+
+/// The layout of a theme file. Every colour role must be given.
+#[derive(Deserialize)]
+#[serde(deny_unknown_fields)]
+struct ThemeFile {
+    name: Option<String>,
+    variant: Option<String>,
+    colors: ThemeColors,
+}
+
+/// Load every `.yaml`, `.yml` and `.toml` theme in `dir` and offer them
+/// alongside the built-in themes, replacing any loaded before under the
+/// same name. A missing directory has no themes.
+///
+/// A file that can't be read, leaves out a colour role or reuses a built-in
+/// name is skipped; the others are still loaded and the error lists what was
+/// skipped and why.
+pub fn load_custom_themes(dir: &Path) -> Result<()> {
+    let Ok(entries) = fs::read_dir(dir) else {
+        return Ok(());
+    };
+
+    let mut paths: Vec<_> = entries.flatten().map(|entry| entry.path()).collect();
+    paths.sort();
+
+    let mut problems = Vec::new();
+    for path in paths {
+        let format = match path.extension().and_then(|e| e.to_str()) {
+            Some("yaml" | "yml") => config::FileFormat::Yaml,
+            Some("toml") => config::FileFormat::Toml,
+            _ => continue,
+        };
+        let file_name = path.file_name().unwrap_or_default().to_string_lossy();
+        match load_theme_file(&path, format) {
+            Ok(theme) => register_custom_theme(theme),
+            Err(e) => problems.push(format!("{file_name}: {e}")),
+        }
+    }
+
+    if problems.is_empty() {
+        Ok(())
+    } else {
+        Err(eyre!("Skipped custom themes: {}", problems.join("; ")))
+    }
+}
+
+fn load_theme_file(path: &Path, format: config::FileFormat) -> Result<CustomTheme> {
+    let name = path
+        .file_stem()
+        .map(|stem| stem.to_string_lossy().to_lowercase())
+        .unwrap_or_default();
+    if BUILT_IN_THEMES.contains(&name.as_str()) {
+        return Err(eyre!("'{name}' is the name of a built-in theme"));
+    }
+
+    let file: ThemeFile = config::Config::builder()
+        .add_source(config::File::from(path).format(format))
+        .build()?
+        .try_deserialize()?;
+
+    Ok(CustomTheme {
+        display_name: file.name.unwrap_or_else(|| name.clone()),
+        variant: file.variant.unwrap_or_else(|| "Custom".to_string()),
+        name,
+        colors: file.colors,
+    })
+}
+
+fn register_custom_theme(theme: CustomTheme) {
+    let mut themes = CUSTOM_THEMES.lock().unwrap();
+    themes.retain(|existing| existing.name != theme.name);
+    themes.push(theme);
+}
+
+/// The custom themes loaded so far, in the order they were loaded
+pub fn custom_themes() -> Vec<CustomTheme> {
+    CUSTOM_THEMES.lock().unwrap().clone()
+}
+
+/// The theme selected by `name`, built in or custom
+pub fn theme_by_name(name: &str) -> Option<Theme> {
+    let name = name.to_lowercase();
+    let flavour = match name.as_str() {
+        "mocha" => ThemeFlavour::Mocha,
+        "macchiato" => ThemeFlavour::Macchiato,
+        "frappe" => ThemeFlavour::Frappe,
+        "latte" => ThemeFlavour::Latte,
+        _ => {
+            return CUSTOM_THEMES
+                .lock()
+                .unwrap()
+                .iter()
+                .find(|theme| theme.name == name)
+                .map(|theme| Theme {
+                    name: theme.name.clone(),
+                    colors: theme.colors,
+                });
+        }
+    };
+    Some(Theme::new(flavour))
 }
 
 /// Get the current theme and apply a function to it
@@ -171,14 +345,8 @@ pub fn set_flavour(flavour: ThemeFlavour) {
 
 /// Set the theme by name (string)
 pub fn set_theme_by_name(name: &str) -> Result<(), String> {
-    let flavour = match name.to_lowercase().as_str() {
-        "mocha" => ThemeFlavour::Mocha,
-        "macchiato" => ThemeFlavour::Macchiato,
-        "frappe" => ThemeFlavour::Frappe,
-        "latte" => ThemeFlavour::Latte,
-        _ => return Err(format!("Unknown theme: {name}")),
-    };
-    set_flavour(flavour);
+    let theme = theme_by_name(name).ok_or_else(|| format!("Unknown theme: {name}"))?;
+    set_theme(theme);
     Ok(())
 }
 
@@ -0,0 +1,108 @@
+use std::io::Write;
+use std::sync::{Arc, Mutex};
+
+use color_eyre::Result;
+
+/// Destination for text copied out of the TUI
+pub trait Clipboard {
+    /// Replace the clipboard contents with `text`
+    fn set_text(&mut self, text: &str) -> Result<()>;
+}
+
+/// Clipboard backed by the OSC 52 terminal escape sequence.
+///
+/// This works over SSH and inside tmux without needing a system clipboard
+/// daemon, as long as the terminal emulator supports OSC 52.
+#[derive(Debug, Default, Clone, Copy)]
+pub struct Osc52Clipboard;
+
+impl Clipboard for Osc52Clipboard {
+    fn set_text(&mut self, text: &str) -> Result<()> {
+        let mut stdout = std::io::stdout();
+        write!(stdout, "\x1b]52;c;{}\x07", base64_encode(text.as_bytes()))?;
+        stdout.flush()?;
+        Ok(())
+    }
+}
+
+/// In-memory clipboard. Clones share the same contents, so a test can keep a
+/// handle while the component owns another.
+#[derive(Debug, Default, Clone)]
+#[allow(dead_code)]
+pub struct MemoryClipboard {
+    contents: Arc<Mutex<Option<String>>>,
+}
+
+#[allow(dead_code)]
+impl MemoryClipboard {
+    pub fn new() -> Self {
+        Self::default()
+    }
+
+    /// The last text copied, if any
+    pub fn contents(&self) -> Option<String> {
+        self.contents.lock().ok().and_then(|c| c.clone())
+    }
+}
+
+impl Clipboard for MemoryClipboard {
+    fn set_text(&mut self, text: &str) -> Result<()> {
+        if let Ok(mut contents) = self.contents.lock() {
+            *contents = Some(text.to_string());
+        }
+        Ok(())
+    }
+}
+
+/// Standard base64 encoding with padding
+fn base64_encode(input: &[u8]) -> String {
+    const ALPHABET: &[u8; 64] = b"ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/";
+
+    let mut output = String::with_capacity(input.len().div_ceil(3) * 4);
+    for chunk in input.chunks(3) {
+        let b = [
+            chunk[0],
+            chunk.get(1).copied().unwrap_or(0),
+            chunk.get(2).copied().unwrap_or(0),
+        ];
+        let n = ((b[0] as u32) << 16) | ((b[1] as u32) << 8) | b[2] as u32;
+
+        output.push(ALPHABET[(n >> 18) as usize & 63] as char);
+        output.push(ALPHABET[(n >> 12) as usize & 63] as char);
+        output.push(if chunk.len() > 1 {
+            ALPHABET[(n >> 6) as usize & 63] as char
+        } else {
+            '='
+        });
+        output.push(if chunk.len() > 2 {
+            ALPHABET[n as usize & 63] as char
+        } else {
+            '='
+        });
+    }
+    output
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_base64_encode() {
+        assert_eq!(base64_encode(b""), "");
+        assert_eq!(base64_encode(b"f"), "Zg==");
+        assert_eq!(base64_encode(b"fo"), "Zm8=");
+        assert_eq!(base64_encode(b"foo"), "Zm9v");
+        assert_eq!(base64_encode(b"gemini --help"), "Z2VtaW5pIC0taGVscA==");
+    }
+
+    #[test]
+    fn test_memory_clipboard_shares_contents() {
+        let clipboard = MemoryClipboard::new();
+        let mut handle = clipboard.clone();
+
+        handle.set_text("copied").unwrap();
+
+        assert_eq!(clipboard.contents().as_deref(), Some("copied"));
+    }
+}
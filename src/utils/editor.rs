@@ -0,0 +1,60 @@
+use std::io::Write;
+use std::path::Path;
+use std::process::Command;
+
+use color_eyre::{Result, eyre::eyre};
+use tempfile::NamedTempFile;
+
+/// The user's editor command: `$VISUAL`, then `$EDITOR`, then `vi`.
+///
+/// The value is split on whitespace so settings like `code --wait` work.
+pub fn editor_command() -> Vec<String> {
+    editor_command_from(std::env::var("VISUAL").ok(), std::env::var("EDITOR").ok())
+}
+
+/// [`editor_command`] with the environment passed in, for testing
+pub fn editor_command_from(visual: Option<String>, editor: Option<String>) -> Vec<String> {
+    [visual, editor]
+        .into_iter()
+        .flatten()
+        .map(|value| {
+            value
+                .split_whitespace()
+                .map(str::to_string)
+                .collect::<Vec<_>>()
+        })
+        .find(|parts| !parts.is_empty())
+        .unwrap_or_else(|| vec!["vi".to_string()])
+}
+
+/// Open `path` in the user's editor and wait for it to exit.
+///
+/// The caller is responsible for leaving the TUI first.
+pub fn edit_file(path: &Path) -> Result<()> {
+    let command = editor_command();
+    let status = Command::new(&command[0])
+        .args(&command[1..])
+        .arg(path)
+        .status()
+        .map_err(|e| eyre!("failed to start '{}': {e}", command[0]))?;
+
+    if status.success() {
+        Ok(())
+    } else {
+        Err(eyre!("'{}' exited with {status}", command[0]))
+    }
+}
+
+/// Write `contents` to a new scratch file for the user to edit.
+///
+/// The file gets an unguessable name and is readable only by the user,
+/// since profiles may hold secrets. It is deleted on drop unless kept.
+pub fn scratch_file(prefix: &str, suffix: &str, contents: &[u8]) -> Result<NamedTempFile> {
+    let mut file = tempfile::Builder::new()
+        .prefix(prefix)
+        .suffix(suffix)
+        .tempfile()?;
+    file.write_all(contents)?;
+    file.flush()?;
+    Ok(file)
+}
@@ -0,0 +1,120 @@
+use std::fs;
+use std::path::Path;
+
+use color_eyre::Result;
+
+/// One line of a directory listing
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub struct TreeEntry {
+    /// Nesting below the root; top-level entries are 0
+    pub depth: usize,
+    pub name: String,
+    /// Size in bytes, or `None` for a directory
+    pub size: Option<u64>,
+}
+
+/// List everything under `root`, depth first, with directories before files
+/// and each group sorted by name.
+///
+/// Symlinks are listed but not followed. At most `limit` entries are
+/// returned so a huge tree (a checked-in `node_modules`, say) stays cheap.
+pub fn build_tree(root: &Path, limit: usize) -> Result<Vec<TreeEntry>> {
+    let mut entries = Vec::new();
+    walk(root, 0, limit, &mut entries)?;
+    Ok(entries)
+}
+
+fn walk(dir: &Path, depth: usize, limit: usize, entries: &mut Vec<TreeEntry>) -> Result<()> {
+    let mut children: Vec<(bool, String, u64)> = fs::read_dir(dir)?
+        .flatten()
+        .filter_map(|entry| {
+            let metadata = entry.path().symlink_metadata().ok()?;
+            let name = entry.file_name().to_string_lossy().into_owned();
+            Some((metadata.is_dir(), name, metadata.len()))
+        })
+        .collect();
+    children.sort_by(|a, b| b.0.cmp(&a.0).then_with(|| a.1.cmp(&b.1)));
+
+    for (is_dir, name, len) in children {
+        if entries.len() >= limit {
+            break;
+        }
+        entries.push(TreeEntry {
+            depth,
+            name: name.clone(),
+            size: (!is_dir).then_some(len),
+        });
+        if is_dir {
+            // An unreadable subdirectory is shown but left empty
+            let _ = walk(&dir.join(&name), depth + 1, limit, entries);
+        }
+    }
+    Ok(())
+}
+
+/// Format a byte count for display, e.g. "512 B" or "1.5 KB"
+pub fn format_size(bytes: u64) -> String {
+    const UNITS: [&str; 4] = ["KB", "MB", "GB", "TB"];
+    if bytes < 1024 {
+        return format!("{bytes} B");
+    }
+    let mut size = bytes as f64 / 1024.0;
+    let mut unit = 0;
+    while size >= 1024.0 && unit < UNITS.len() - 1 {
+        size /= 1024.0;
+        unit += 1;
+    }
+    format!("{size:.1} {}", UNITS[unit])
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    fn entry(depth: usize, name: &str, size: Option<u64>) -> TreeEntry {
+        TreeEntry {
+            depth,
+            name: name.to_string(),
+            size,
+        }
+    }
+
+    #[test]
+    fn test_build_tree_over_fixture() {
+        let root = tempfile::TempDir::new().unwrap();
+        fs::write(root.path().join("gemini-extension.json"), "{}").unwrap();
+        fs::write(root.path().join("GEMINI.md"), "# Context\n").unwrap();
+        fs::create_dir_all(root.path().join("server/lib")).unwrap();
+        fs::write(root.path().join("server/index.js"), "run();").unwrap();
+        fs::write(root.path().join("server/lib/util.js"), "").unwrap();
+        fs::create_dir(root.path().join("assets")).unwrap();
+
+        assert_eq!(
+            build_tree(root.path(), 100).unwrap(),
+            vec![
+                entry(0, "assets", None),
+                entry(0, "server", None),
+                entry(1, "lib", None),
+                entry(2, "util.js", Some(0)),
+                entry(1, "index.js", Some(6)),
+                entry(0, "GEMINI.md", Some(10)),
+                entry(0, "gemini-extension.json", Some(2)),
+            ]
+        );
+
+        // The limit cuts the listing short
+        let first = build_tree(root.path(), 3).unwrap();
+        assert_eq!(first.len(), 3);
+        assert_eq!(first[2], entry(1, "lib", None));
+
+        assert!(build_tree(&root.path().join("missing"), 100).is_err());
+    }
+
+    #[test]
+    fn test_format_size() {
+        assert_eq!(format_size(0), "0 B");
+        assert_eq!(format_size(1023), "1023 B");
+        assert_eq!(format_size(1536), "1.5 KB");
+        assert_eq!(format_size(5 * 1024 * 1024), "5.0 MB");
+    }
+}
@@ -0,0 +1,99 @@
+use std::cmp::Reverse;
+
+/// Bonus for a match at the very start of the text
+const START_BONUS: i32 = 8;
+/// Bonus for a match at the start of a word, or at a camelCase hump
+const WORD_BONUS: i32 = 6;
+/// Bonus for a match right after the previous one
+const CONSECUTIVE_BONUS: i32 = 5;
+/// Most a single gap between matches can cost
+const MAX_GAP_PENALTY: i32 = 10;
+
+/// Where and how well a query matched some text
+#[derive(Debug, Clone, PartialEq, Eq)]
+pub struct FuzzyMatch {
+    /// Higher is better. Only scores for the same query compare meaningfully.
+    pub score: i32,
+    /// Char indices of the matched characters in the text
+    pub positions: Vec<usize>,
+}
+
+/// Match `query` against `text` as a subsequence: every query character
+/// must appear in `text`, in order, though not necessarily side by side.
+/// Case and whitespace in the query are ignored, so "mkasst" and
+/// "md assist" both find "Markdown Assistant".
+///
+/// Of the ways the query can line up with the text, the best scoring is
+/// returned. Matches at the start of words and runs of adjacent characters
+/// score higher; gaps between matches cost a little. An empty query matches
+/// anything with a score of 0.
+pub fn fuzzy_match(query: &str, text: &str) -> Option<FuzzyMatch> {
+    let query: Vec<char> = query
+        .chars()
+        .filter(|c| !c.is_whitespace())
+        .flat_map(char::to_lowercase)
+        .collect();
+    if query.is_empty() {
+        return Some(FuzzyMatch {
+            score: 0,
+            positions: Vec::new(),
+        });
+    }
+
+    let chars: Vec<char> = text.chars().collect();
+    let lower: Vec<char> = chars
+        .iter()
+        .map(|c| c.to_lowercase().next().unwrap_or(*c))
+        .collect();
+
+    // best[i][j]: best score with query[..=i] matched and query[i] at text
+    // position j, and the position query[i - 1] was matched at to get it
+    let mut best: Vec<Vec<Option<(i32, usize)>>> = vec![vec![None; chars.len()]; query.len()];
+    for (i, &wanted) in query.iter().enumerate() {
+        for j in (i..chars.len()).filter(|&j| lower[j] == wanted) {
+            let bonus = 1 + boundary_bonus(&chars, j);
+            if i == 0 {
+                best[0][j] = Some((bonus, 0));
+                continue;
+            }
+            best[i][j] = (i - 1..j)
+                .filter_map(|k| {
+                    let (score, _) = best[i - 1][k]?;
+                    let gap = (j - k - 1) as i32;
+                    let link = if gap == 0 {
+                        CONSECUTIVE_BONUS
+                    } else {
+                        -gap.min(MAX_GAP_PENALTY)
+                    };
+                    Some((score + link + bonus, k))
+                })
+                .max_by_key(|&(score, k)| (score, Reverse(k)));
+        }
+    }
+
+    let last = query.len() - 1;
+    let (mut position, score) = (0..chars.len())
+        .filter_map(|j| best[last][j].map(|(score, _)| (j, score)))
+        .max_by_key(|&(j, score)| (score, Reverse(j)))?;
+
+    let mut positions = vec![position];
+    for i in (1..query.len()).rev() {
+        position = best[i][position]?.1;
+        positions.push(position);
+    }
+    positions.reverse();
+    Some(FuzzyMatch { score, positions })
+}
+
+/// Extra score for matching at position `j`: the start of the text, the
+/// start of a word, or an uppercase letter following a lowercase one
+fn boundary_bonus(chars: &[char], j: usize) -> i32 {
+    let Some(&previous) = j.checked_sub(1).and_then(|p| chars.get(p)) else {
+        return START_BONUS;
+    };
+    if !previous.is_alphanumeric() || (previous.is_lowercase() && chars[j].is_uppercase()) {
+        WORD_BONUS
+    } else {
+        0
+    }
+}
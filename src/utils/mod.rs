@@ -1,7 +1,17 @@
+pub mod clipboard;
+pub mod editor;
+pub mod file_tree;
+pub mod fuzzy;
 pub mod help_text;
 pub mod keybinding_manager;
+pub mod text;
+pub mod time;
 
 #[allow(unused_imports)]
 pub use help_text::{HelpTextBuilder, build_help_text, get_current_keybindings};
 #[allow(unused_imports)]
 pub use keybinding_manager::KeybindingManager;
+#[allow(unused_imports)]
+pub use text::{display_width, truncate_to_width};
+#[allow(unused_imports)]
+pub use time::humanize_since;
@@ -0,0 +1,61 @@
+use unicode_segmentation::UnicodeSegmentation;
+use unicode_width::UnicodeWidthStr;
+
+/// Number of terminal columns `text` takes up.
+///
+/// Measured per grapheme cluster, so combining marks and zero-width joiners
+/// don't add width and emoji ZWJ sequences count as a single glyph.
+pub fn display_width(text: &str) -> usize {
+    text.graphemes(true).map(UnicodeWidthStr::width).sum()
+}
+
+/// Cut `text` down to at most `max_width` columns, ending in "…" if shortened.
+///
+/// Never splits a grapheme cluster, so accents stay attached to their base
+/// character and emoji sequences are kept whole or dropped entirely.
+pub fn truncate_to_width(text: &str, max_width: usize) -> String {
+    if display_width(text) <= max_width {
+        return text.to_string();
+    }
+    if max_width == 0 {
+        return String::new();
+    }
+
+    // Leave one column for the ellipsis
+    let budget = max_width - 1;
+    let mut width = 0;
+    let mut truncated = String::new();
+    for grapheme in text.graphemes(true) {
+        let grapheme_width = grapheme.width();
+        if width + grapheme_width > budget {
+            break;
+        }
+        width += grapheme_width;
+        truncated.push_str(grapheme);
+    }
+    truncated.push('…');
+    truncated
+}
+
+/// Pad `text` with spaces on the right so it fills `width` columns
+#[allow(dead_code)]
+pub fn pad_to_width(text: &str, width: usize) -> String {
+    let padding = width.saturating_sub(display_width(text));
+    format!("{text}{}", " ".repeat(padding))
+}
+
+/// Decode file contents as text, replacing invalid UTF-8 with U+FFFD.
+///
+/// Returns `None` for binary data (anything with a NUL byte), which would
+/// only draw as garbage and throw off width calculations.
+pub fn decode_text(bytes: &[u8]) -> Option<String> {
+    if bytes.contains(&0) {
+        return None;
+    }
+    Some(String::from_utf8_lossy(bytes).into_owned())
+}
+
+/// Read a file with [`decode_text`]
+pub fn read_text(path: &std::path::Path) -> std::io::Result<Option<String>> {
+    Ok(decode_text(&std::fs::read(path)?))
+}
@@ -0,0 +1,63 @@
+use chrono::{DateTime, Utc};
+
+/// Describe how long ago `then` was, e.g. "3 days ago"
+pub fn humanize_since(then: DateTime<Utc>) -> String {
+    humanize_between(then, Utc::now())
+}
+
+/// Describe the gap between `then` and `now` in the largest whole unit
+pub fn humanize_between(then: DateTime<Utc>, now: DateTime<Utc>) -> String {
+    let seconds = (now - then).num_seconds();
+    if seconds < 60 {
+        // Also covers timestamps slightly in the future due to clock skew
+        return "just now".to_string();
+    }
+
+    let (count, unit) = match seconds {
+        s if s < 3_600 => (s / 60, "minute"),
+        s if s < 86_400 => (s / 3_600, "hour"),
+        s if s < 2_592_000 => (s / 86_400, "day"),
+        s if s < 31_536_000 => (s / 2_592_000, "month"),
+        s => (s / 31_536_000, "year"),
+    };
+
+    if count == 1 {
+        format!("1 {unit} ago")
+    } else {
+        format!("{count} {unit}s ago")
+    }
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+    use chrono::Duration;
+
+    #[test]
+    fn test_humanize_between() {
+        let now = Utc::now();
+
+        assert_eq!(humanize_between(now, now), "just now");
+        assert_eq!(
+            humanize_between(now + Duration::seconds(5), now),
+            "just now"
+        );
+        assert_eq!(
+            humanize_between(now - Duration::minutes(1), now),
+            "1 minute ago"
+        );
+        assert_eq!(
+            humanize_between(now - Duration::hours(5), now),
+            "5 hours ago"
+        );
+        assert_eq!(humanize_between(now - Duration::days(3), now), "3 days ago");
+        assert_eq!(
+            humanize_between(now - Duration::days(65), now),
+            "2 months ago"
+        );
+        assert_eq!(
+            humanize_between(now - Duration::days(800), now),
+            "2 years ago"
+        );
+    }
+}
@@ -10,6 +10,7 @@ use crate::{
     action::Action,
     components::{
         Component,
+        config_preview::ConfigPreview,
         confirm_dialog::ConfirmDialog,
         extension_detail::ExtensionDetail,
         extension_form::ExtensionForm,
@@ -22,6 +23,9 @@ use crate::{
         tab_bar::TabBar,
     },
     config::Config,
+    icons::Icon,
+    launcher::{Launcher, check_platforms},
+    models::extension::current_platform,
     storage::Storage,
     theme,
 };
@@ -37,6 +41,7 @@ pub enum ViewType {
     ProfileDetail,
     ProfileCreate,
     ProfileEdit,
+    ProfileConfig,
     ConfirmDelete,
     Settings,
 }
@@ -52,6 +57,7 @@ pub struct ViewManager {
     deleting_profile_id: Option<String>,
     editing_extension_id: Option<String>,
     deleting_extension_id: Option<String>,
+    deleting_unused_ids: Vec<String>,
     came_from_detail_view: bool, // Track if we came from detail view when editing
     error_message: Option<(String, Instant)>,
     success_message: Option<(String, Instant)>,
@@ -132,6 +138,7 @@ impl ViewManager {
             deleting_profile_id: None,
             editing_extension_id: None,
             deleting_extension_id: None,
+            deleting_unused_ids: Vec::new(),
             came_from_detail_view: false,
             error_message: None,
             success_message: None,
@@ -140,6 +147,14 @@ impl ViewManager {
         }
     }
 
+    /// Add a custom status segment to the tab bar
+    pub fn add_status_segment<F>(&mut self, segment: F)
+    where
+        F: Fn() -> Result<String> + 'static,
+    {
+        self.tab_bar.add_segment(segment);
+    }
+
     pub fn register_action_handler(&mut self, tx: UnboundedSender<Action>) -> Result<()> {
         self.action_tx = Some(tx.clone());
 
@@ -221,6 +236,10 @@ impl ViewManager {
                     // Create a new edit form with the extension data
                     let mut edit_form =
                         ExtensionForm::with_extension(self.storage.clone(), &extension);
+                    if self.came_from_detail_view {
+                        // Pick up where the detail view left the user
+                        edit_form.set_initial_field(ExtensionForm::detail_field(&extension));
+                    }
 
                     // Register action handler for the new form
                     if let Some(tx) = &self.action_tx {
@@ -271,9 +290,57 @@ impl ViewManager {
                     self.navigate_to(ViewType::ConfirmDelete);
                 }
             }
+            Action::DeleteUnusedExtensions => match self.storage.orphaned_extensions() {
+                Ok(unused) if unused.is_empty() => {
+                    self.success_message =
+                        Some(("No unused extensions to delete".to_string(), Instant::now()));
+                }
+                Ok(unused) => {
+                    let names: Vec<&str> = unused.iter().map(|e| e.name.as_str()).collect();
+                    let message = format!(
+                        "Delete {} extension(s) not used by any profile?\n{}\nThis action cannot be undone.",
+                        unused.len(),
+                        names.join(", ")
+                    );
+                    self.deleting_unused_ids = unused.into_iter().map(|e| e.id).collect();
+
+                    let dialog = ConfirmDialog::new("Delete Unused Extensions", &message)
+                        .with_actions(Action::ConfirmDelete, Action::CancelDelete);
+
+                    self.views.insert(ViewType::ConfirmDelete, Box::new(dialog));
+                    self.navigate_to(ViewType::ConfirmDelete);
+                }
+                Err(e) => {
+                    self.error_message = Some((
+                        format!("Failed to find unused extensions: {e}"),
+                        Instant::now(),
+                    ));
+                }
+            },
             Action::ViewProfileDetails(_id) => {
                 self.navigate_to(ViewType::ProfileDetail);
             }
+            Action::PreviewConfig(id) => {
+                let preview = self.storage.resolve_profile(id).and_then(|profile| {
+                    let config =
+                        Launcher::with_storage(self.storage.clone()).preview_config(&profile)?;
+                    let last_launch = self.storage.load_last_launch_config().ok().flatten();
+                    Ok(ConfigPreview::new(&profile.name, config).with_last_launch(last_launch))
+                });
+                match preview {
+                    Ok(preview) => {
+                        self.views
+                            .insert(ViewType::ProfileConfig, Box::new(preview));
+                        self.navigate_to(ViewType::ProfileConfig);
+                    }
+                    Err(e) => {
+                        if let Some(tx) = &self.action_tx {
+                            let _ =
+                                tx.send(Action::Error(format!("Failed to summarize launch: {e}")));
+                        }
+                    }
+                }
+            }
             Action::EditProfile(id) => {
                 // Track where we came from
                 self.came_from_detail_view = self.current_view == ViewType::ProfileDetail;
@@ -332,6 +399,10 @@ impl ViewManager {
                         self.editing_profile_id = None;
                         self.came_from_detail_view = false;
                     }
+                    ViewType::ProfileConfig => {
+                        // From the config preview, return to the profile it belongs to
+                        self.navigate_to(ViewType::ProfileDetail);
+                    }
                     ViewType::ProfileCreate => {
                         // From create, always go back to list
                         self.navigate_to(ViewType::ProfileList);
@@ -456,12 +527,121 @@ impl ViewManager {
                     } else if let Some(prev) = self.previous_view {
                         self.navigate_to(prev);
                     }
+                } else if !self.deleting_unused_ids.is_empty() {
+                    let ids = std::mem::take(&mut self.deleting_unused_ids);
+                    let failed = ids
+                        .iter()
+                        .filter(|id| self.storage.delete_extension(id).is_err())
+                        .count();
+                    if let Some(tx) = &self.action_tx {
+                        let _ = if failed == 0 {
+                            tx.send(Action::Success(format!(
+                                "Deleted {} unused extension(s)",
+                                ids.len()
+                            )))
+                        } else {
+                            tx.send(Action::Error(format!(
+                                "Failed to delete {failed} of {} unused extension(s)",
+                                ids.len()
+                            )))
+                        };
+                        let _ = tx.send(Action::RefreshExtensions);
+                        let _ = tx.send(Action::Render);
+                    }
+
+                    if let Some(prev) = self.previous_view {
+                        self.navigate_to(prev);
+                    }
+                }
+            }
+            Action::ConfirmEmptyLaunch(id, skipped) => {
+                let name = self
+                    .storage
+                    .load_profile(id)
+                    .map(|p| p.name)
+                    .unwrap_or_else(|_| id.clone());
+                let message =
+                    format!("The profile '{name}' has no enabled extensions.\nLaunch anyway?");
+
+                let dialog = ConfirmDialog::new("Launch Profile", &message).with_actions(
+                    Action::LaunchConfirmed(id.clone(), skipped.clone()),
+                    Action::CancelLaunch,
+                );
+
+                self.views.insert(ViewType::ConfirmDelete, Box::new(dialog));
+                self.navigate_to(ViewType::ConfirmDelete);
+            }
+            Action::ConfirmPlatformLaunch(id, skipped) => {
+                let problems = self
+                    .storage
+                    .resolve_profile(id)
+                    .map(|profile| {
+                        let profile = profile.without_extensions(skipped);
+                        let launcher = Launcher::with_storage(self.storage.clone());
+                        check_platforms(&launcher.enabled_extensions(&profile), current_platform())
+                    })
+                    .unwrap_or_default();
+                let message = format!(
+                    "Some extensions aren't made for this system:\n{}\nLaunch anyway?",
+                    problems.join("\n")
+                );
+
+                let dialog = ConfirmDialog::new("Launch Profile", &message).with_actions(
+                    Action::LaunchConfirmed(id.clone(), skipped.clone()),
+                    Action::CancelLaunch,
+                );
+
+                self.views.insert(ViewType::ConfirmDelete, Box::new(dialog));
+                self.navigate_to(ViewType::ConfirmDelete);
+            }
+            Action::CaptureDefaults(id) => {
+                let name = self
+                    .storage
+                    .load_profile(id)
+                    .map(|p| p.name)
+                    .unwrap_or_else(|_| id.clone());
+                let defaults = self
+                    .storage
+                    .list_extensions()
+                    .map(|extensions| extensions.iter().filter(|e| e.enabled_by_default).count())
+                    .unwrap_or_default();
+                let message = format!(
+                    "Replace the extensions of '{name}' with the {defaults} enabled by default?\nExtensions it has now that aren't enabled by default are removed from it."
+                );
+
+                let dialog = ConfirmDialog::new("Capture Defaults", &message).with_actions(
+                    Action::CaptureDefaultsConfirmed(id.clone()),
+                    Action::CancelCaptureDefaults,
+                );
+
+                self.views.insert(ViewType::ConfirmDelete, Box::new(dialog));
+                self.navigate_to(ViewType::ConfirmDelete);
+            }
+            Action::ConfirmQuit => {
+                let dialog = ConfirmDialog::new("Quit", "Quit Gemini CLI Manager?")
+                    .with_actions(Action::QuitConfirmed, Action::CancelQuit);
+
+                self.views.insert(ViewType::ConfirmDelete, Box::new(dialog));
+                self.navigate_to(ViewType::ConfirmDelete);
+            }
+            Action::LaunchConfirmed(..)
+            | Action::CancelLaunch
+            | Action::CancelQuit
+            | Action::CaptureDefaultsConfirmed(_)
+            | Action::CancelCaptureDefaults => {
+                // Close the confirmation; the app handles the launch itself, and
+                // the profile list the capture
+                if self.current_view == ViewType::ConfirmDelete
+                    && let Some(prev) = self.previous_view
+                {
+                    self.navigate_to(prev);
                 }
             }
             Action::CancelDelete => {
                 // Clear deletion state and go back
                 self.deleting_profile_id = None;
                 self.deleting_extension_id = None;
+                self.deleting_unused_ids.clear();
                 if let Some(prev) = self.previous_view {
                     self.navigate_to(prev);
                 }
@@ -550,7 +730,7 @@ impl ViewManager {
                 .border_style(Style::default().fg(theme::error()))
                 .style(Style::default().bg(theme::overlay()));
 
-            let error_content = format!("✗ {message}\n\n(Press Esc to dismiss)");
+            let error_content = format!("{} {message}\n\n(Press Esc to dismiss)", Icon::Error);
             let error_text = Paragraph::new(error_content)
                 .block(error_block)
                 .style(
@@ -568,7 +748,7 @@ impl ViewManager {
         // Draw success message if present
         if let Some((message, _)) = &self.success_message {
             // Position in top-right corner
-            let notification_width = 40.min(area.width - 4);
+            let notification_width = 40.min(area.width.saturating_sub(4));
             let notification_height = 3;
             let notification_area = Rect {
                 x: area.width.saturating_sub(notification_width + 2),
@@ -593,7 +773,7 @@ impl ViewManager {
                 .border_style(Style::default().fg(theme::success()))
                 .style(Style::default().bg(theme::surface()));
 
-            let success_text = Paragraph::new(format!("✓ {message}"))
+            let success_text = Paragraph::new(format!("{} {message}", Icon::Success))
                 .style(
                     Style::default()
                         .fg(theme::success())
@@ -0,0 +1,97 @@
+use std::fs;
+use std::hash::{DefaultHasher, Hash, Hasher};
+use std::path::PathBuf;
+use std::time::{Duration, Instant, SystemTime};
+
+use crate::action::Action;
+
+/// How long files must stay unchanged before a reload is sent, so a burst of
+/// writes (an editor saving, a `git pull`) reloads once
+pub const RELOAD_DEBOUNCE: Duration = Duration::from_millis(500);
+
+/// Something whose state can be compared over time. Two calls return the same
+/// fingerprint exactly when nothing changed in between.
+pub trait ChangeSource {
+    fn fingerprint(&self) -> u64;
+}
+
+/// The files directly inside a directory, compared by name, size and
+/// modification time.
+///
+/// This is polled rather than event driven: native file watchers behave
+/// differently on each platform and miss changes on some network filesystems,
+/// while listing a directory of a few hundred files each tick costs little.
+pub struct DirectorySource {
+    dir: PathBuf,
+}
+
+impl DirectorySource {
+    pub fn new(dir: impl Into<PathBuf>) -> Self {
+        Self { dir: dir.into() }
+    }
+}
+
+impl ChangeSource for DirectorySource {
+    fn fingerprint(&self) -> u64 {
+        let mut entries: Vec<(String, u64, Option<SystemTime>)> = fs::read_dir(&self.dir)
+            .into_iter()
+            .flatten()
+            .flatten()
+            .filter_map(|entry| {
+                let metadata = entry.metadata().ok()?;
+                Some((
+                    entry.file_name().to_string_lossy().into_owned(),
+                    metadata.len(),
+                    metadata.modified().ok(),
+                ))
+            })
+            .collect();
+        entries.sort();
+
+        let mut hasher = DefaultHasher::new();
+        entries.hash(&mut hasher);
+        hasher.finish()
+    }
+}
+
+/// Turns changes in a [`ChangeSource`] into a debounced reload action
+pub struct ReloadWatcher<S> {
+    source: S,
+    reload: Action,
+    debounce: Duration,
+    last_seen: u64,
+    changed_at: Option<Instant>, // When the latest unreported change was seen
+}
+
+impl<S: ChangeSource> ReloadWatcher<S> {
+    /// Watch `source`, sending `reload` after it changes. The state at
+    /// creation counts as already loaded.
+    pub fn new(source: S, reload: Action, debounce: Duration) -> Self {
+        let last_seen = source.fingerprint();
+        Self {
+            source,
+            reload,
+            debounce,
+            last_seen,
+            changed_at: None,
+        }
+    }
+
+    /// Check the source at `now`. Returns the reload action once the source
+    /// has stayed unchanged for the debounce period after a change.
+    pub fn poll(&mut self, now: Instant) -> Option<Action> {
+        let fingerprint = self.source.fingerprint();
+        if fingerprint != self.last_seen {
+            self.last_seen = fingerprint;
+            self.changed_at = Some(now);
+            return None;
+        }
+
+        let changed_at = self.changed_at?;
+        if now.duration_since(changed_at) < self.debounce {
+            return None;
+        }
+        self.changed_at = None;
+        Some(self.reload.clone())
+    }
+}
@@ -29,8 +29,13 @@ mod tests {
             mcp_servers: HashMap::new(),
             context_file_name: Some("GEMINI.md".to_string()),
             context_content: Some("# Test Content".to_string()),
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: gemini_cli_manager::models::extension::ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: None,
                 tags: vec!["test".to_string()],
             },
@@ -64,6 +69,7 @@ mod tests {
             id: "test-profile".to_string(),
             name: "Test Profile".to_string(),
             description: Some("Test description".to_string()),
+            inherits: None,
             extension_ids: vec!["ext1".to_string(), "ext2".to_string()],
             environment_variables: HashMap::new(),
             working_directory: None,
@@ -139,8 +145,13 @@ mod tests {
             },
             context_file_name: Some("GEMINI.md".to_string()),
             context_content: Some("# Echo Test\n\nThis is a test.".to_string()),
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: gemini_cli_manager::models::extension::ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: None,
                 tags: vec!["test".to_string()],
             },
@@ -152,6 +163,7 @@ mod tests {
             id: "test-profile".to_string(),
             name: "Test Profile".to_string(),
             description: None,
+            inherits: None,
             extension_ids: vec![ext.id.clone()],
             environment_variables: HashMap::new(),
             working_directory: None,
@@ -234,8 +246,13 @@ mod tests {
             mcp_servers: HashMap::new(),
             context_file_name: None,
             context_content: None,
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: gemini_cli_manager::models::extension::ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: None,
                 tags: vec![],
             },
@@ -259,6 +276,7 @@ mod tests {
             id: "env-test".to_string(),
             name: "Environment Test".to_string(),
             description: None,
+            inherits: None,
             extension_ids: vec![],
             environment_variables: HashMap::new(),
             working_directory: None,
@@ -42,4 +42,170 @@ mod tests {
         let app2 = App::new();
         assert!(app2.is_ok());
     }
+
+    #[tokio::test]
+    async fn test_quit_key_sends_one_quit_per_press() {
+        use crate::test_utils::create_temp_storage;
+        use crossterm::event::{KeyCode, KeyEvent};
+        use gemini_cli_manager::{action::Action, tui::Event};
+
+        let (storage, _temp) = create_temp_storage();
+        let mut app = App::with_storage(storage).unwrap();
+        app.take_pending_actions();
+
+        // The extension list and the global keymap both map 'q' to Quit
+        app.handle_event(Event::Key(KeyEvent::from(KeyCode::Char('q'))))
+            .unwrap();
+        let quits = app
+            .take_pending_actions()
+            .into_iter()
+            .filter(|action| *action == Action::Quit)
+            .count();
+        assert_eq!(quits, 1);
+    }
+
+    #[test]
+    fn test_launch_confirmation_for_empty_profile() {
+        use crate::test_utils::{ExtensionBuilder, ProfileBuilder, create_test_storage};
+        use gemini_cli_manager::action::Action;
+        use gemini_cli_manager::app::launch_confirmation;
+
+        let storage = create_test_storage();
+
+        let empty = ProfileBuilder::new("Empty").build();
+        storage.save_profile(&empty).unwrap();
+
+        // Referencing a missing extension still counts as empty
+        let dangling = ProfileBuilder::new("Dangling")
+            .with_extensions(vec!["missing-extension"])
+            .build();
+        storage.save_profile(&dangling).unwrap();
+
+        let ext = ExtensionBuilder::new("Real").build();
+        storage.save_extension(&ext).unwrap();
+        let populated = ProfileBuilder::new("Populated")
+            .with_extensions(vec![&ext.id])
+            .build();
+        storage.save_profile(&populated).unwrap();
+
+        assert_eq!(
+            launch_confirmation(&storage, &empty.id, &[], true),
+            Some(Action::ConfirmEmptyLaunch(empty.id.clone(), vec![]))
+        );
+        assert_eq!(
+            launch_confirmation(&storage, &dangling.id, &[], true),
+            Some(Action::ConfirmEmptyLaunch(dangling.id.clone(), vec![]))
+        );
+        assert_eq!(
+            launch_confirmation(&storage, &populated.id, &[], true),
+            None
+        );
+
+        // Unchecking every extension for one launch is confirmed the same way
+        let skipped = vec![ext.id.clone()];
+        assert_eq!(
+            launch_confirmation(&storage, &populated.id, &skipped, true),
+            Some(Action::ConfirmEmptyLaunch(populated.id.clone(), skipped))
+        );
+
+        // The setting turns the guard off
+        assert_eq!(launch_confirmation(&storage, &empty.id, &[], false), None);
+    }
+
+    #[test]
+    fn test_launch_confirmation_blocks_malformed_environment() {
+        use crate::test_utils::{ExtensionBuilder, ProfileBuilder, create_test_storage};
+        use gemini_cli_manager::action::Action;
+        use gemini_cli_manager::app::launch_confirmation;
+
+        let storage = create_test_storage();
+        let ext = ExtensionBuilder::new("Real").build();
+        storage.save_extension(&ext).unwrap();
+
+        let mut profile = ProfileBuilder::new("Broken")
+            .with_extensions(vec![&ext.id])
+            .build();
+        profile
+            .environment_variables
+            .insert("API_URL".to_string(), "https://${HOST/api".to_string());
+        profile
+            .environment_variables
+            .insert("TOKEN".to_string(), "$API_TOKEN".to_string());
+        storage.save_profile(&profile).unwrap();
+
+        assert_eq!(
+            launch_confirmation(&storage, &profile.id, &[], true),
+            Some(Action::Error(
+                "Can't launch 'Broken': API_URL: has an unclosed '${'".to_string()
+            ))
+        );
+
+        // Fixing the value lets the launch go ahead
+        profile
+            .environment_variables
+            .insert("API_URL".to_string(), "https://${HOST}/api".to_string());
+        storage.save_profile(&profile).unwrap();
+        assert_eq!(launch_confirmation(&storage, &profile.id, &[], true), None);
+
+        profile
+            .environment_variables
+            .insert("LABEL".to_string(), "a\u{7}b ${}".to_string());
+        assert_eq!(
+            profile.environment_problems(),
+            vec!["LABEL: contains control characters".to_string()]
+        );
+        profile
+            .environment_variables
+            .insert("LABEL".to_string(), "${1BAD}".to_string());
+        assert_eq!(
+            profile.environment_problems(),
+            vec!["LABEL: '${1BAD}' doesn't name a variable".to_string()]
+        );
+
+        // `$$` escapes a dollar, so what follows it is plain text
+        profile
+            .environment_variables
+            .insert("LABEL".to_string(), "$${not a variable} $${".to_string());
+        assert!(profile.environment_problems().is_empty());
+    }
+
+    #[test]
+    fn test_quit_guard_follows_the_quit_setting() {
+        use gemini_cli_manager::app::{QuitDecision, QuitGuard};
+        use gemini_cli_manager::components::settings_view::{QuitBehavior, UserSettings};
+        use std::time::Instant;
+
+        // Older settings files keep quitting on the first press
+        let settings: UserSettings = serde_json::from_value(serde_json::json!({
+            "theme": "mocha",
+            "keybindings": serde_json::to_value(UserSettings::default().keybindings).unwrap(),
+        }))
+        .unwrap();
+        assert_eq!(settings.quit_behavior, QuitBehavior::Immediate);
+
+        let window = Duration::from_millis(500);
+        let start = Instant::now();
+
+        let mut immediate = QuitGuard::new(QuitBehavior::Immediate, window);
+        assert_eq!(immediate.request(start), QuitDecision::Quit);
+
+        let mut confirm = QuitGuard::new(QuitBehavior::Confirm, window);
+        assert_eq!(confirm.request(start), QuitDecision::Confirm);
+        assert_eq!(confirm.request(start), QuitDecision::Confirm);
+
+        let mut double = QuitGuard::new(QuitBehavior::DoublePress, window);
+        assert_eq!(double.request(start), QuitDecision::PressAgain);
+        // Too slow: the late press starts a new wait instead of quitting
+        let late = start + Duration::from_millis(800);
+        assert_eq!(double.request(late), QuitDecision::PressAgain);
+        assert_eq!(
+            double.request(late + Duration::from_millis(300)),
+            QuitDecision::Quit
+        );
+        // Quitting uses up the first press
+        assert_eq!(
+            double.request(late + Duration::from_millis(400)),
+            QuitDecision::PressAgain
+        );
+    }
 }
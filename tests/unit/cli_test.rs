@@ -8,6 +8,35 @@ mod tests {
         let cli = Cli::parse_from(["gemini-cli-manager"]);
 
         assert!(!cli.list_storage);
+        assert!(!cli.debug);
+        assert!(cli.log_file.is_none());
+    }
+
+    #[test]
+    fn test_cli_debug_and_log_file() {
+        let cli = Cli::parse_from([
+            "gemini-cli-manager",
+            "--debug",
+            "--log-file",
+            "/tmp/gcm.log",
+        ]);
+
+        assert!(cli.debug);
+        assert_eq!(
+            cli.log_file.as_deref(),
+            Some(std::path::Path::new("/tmp/gcm.log"))
+        );
+    }
+
+    #[test]
+    fn test_cli_data_dir_flag() {
+        let cli = Cli::parse_from(["gemini-cli-manager", "--data-dir", "/tmp/gemini-test"]);
+
+        assert_eq!(
+            cli.data_dir.as_deref(),
+            Some(std::path::Path::new("/tmp/gemini-test"))
+        );
+        assert!(Cli::parse_from(["gemini-cli-manager"]).data_dir.is_none());
     }
 
     #[test]
@@ -17,6 +46,108 @@ mod tests {
         assert!(cli.list_storage);
     }
 
+    #[test]
+    fn test_cli_init_subcommand() {
+        use gemini_cli_manager::cli::Command;
+        use std::path::Path;
+
+        let cli = Cli::parse_from([
+            "gemini-cli-manager",
+            "init",
+            "--from",
+            "/srv/team-state",
+            "--to",
+            "/home/new/state",
+        ]);
+
+        match cli.command {
+            Some(Command::Init { from, to }) => {
+                assert_eq!(from, Path::new("/srv/team-state"));
+                assert_eq!(to, Path::new("/home/new/state"));
+            }
+            other => panic!("expected init, got {other:?}"),
+        }
+
+        // Both directories are required
+        assert!(Cli::try_parse_from(["gemini-cli-manager", "init", "--from", "/a"]).is_err());
+    }
+
+    #[test]
+    fn test_cli_keys_subcommand() {
+        use gemini_cli_manager::cli::Command;
+
+        let cli = Cli::parse_from(["gemini-cli-manager", "keys", "--markdown"]);
+        assert!(matches!(
+            cli.command,
+            Some(Command::Keys { markdown: true })
+        ));
+
+        let cli = Cli::parse_from(["gemini-cli-manager", "keys"]);
+        assert!(matches!(
+            cli.command,
+            Some(Command::Keys { markdown: false })
+        ));
+    }
+
+    #[test]
+    fn test_cli_profile_export_and_import_subcommands() {
+        use gemini_cli_manager::cli::Command;
+        use gemini_cli_manager::storage::ConflictResolution;
+        use std::path::Path;
+
+        let cli = Cli::parse_from([
+            "gemini-cli-manager",
+            "export-profile",
+            "work",
+            "--to",
+            "work.json",
+        ]);
+        match cli.command {
+            Some(Command::ExportProfile { id, to }) => {
+                assert_eq!(id, "work");
+                assert_eq!(to, Path::new("work.json"));
+            }
+            other => panic!("expected export-profile, got {other:?}"),
+        }
+
+        let cli = Cli::parse_from(["gemini-cli-manager", "import-profile", "work.json"]);
+        assert!(matches!(
+            cli.command,
+            Some(Command::ImportProfile { file, on_conflict: ConflictResolution::Rename })
+                if file == Path::new("work.json")
+        ));
+
+        for (flag, expected) in [
+            ("skip", ConflictResolution::Skip),
+            ("rename", ConflictResolution::Rename),
+            ("overwrite", ConflictResolution::Overwrite),
+        ] {
+            let cli = Cli::parse_from([
+                "gemini-cli-manager",
+                "import-profile",
+                "work.json",
+                "--on-conflict",
+                flag,
+            ]);
+            match cli.command {
+                Some(Command::ImportProfile { on_conflict, .. }) => {
+                    assert_eq!(on_conflict, expected)
+                }
+                other => panic!("expected import-profile, got {other:?}"),
+            }
+        }
+        assert!(
+            Cli::try_parse_from([
+                "gemini-cli-manager",
+                "import-profile",
+                "work.json",
+                "--on-conflict",
+                "merge",
+            ])
+            .is_err()
+        );
+    }
+
     #[test]
     fn test_version_function() {
         let version_str = version();
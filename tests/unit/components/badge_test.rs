@@ -0,0 +1,39 @@
+#[cfg(test)]
+mod tests {
+    use gemini_cli_manager::components::badge::{Badge, BadgeKind, ChipRow};
+    use gemini_cli_manager::theme;
+    use ratatui::style::Style;
+
+    #[test]
+    fn test_badge_variants_use_theme_colors() {
+        let cases = [
+            (Badge::success("active"), theme::success()),
+            (Badge::warning("modified"), theme::warning()),
+            (Badge::error("missing"), theme::error()),
+            (Badge::info("default"), theme::info()),
+            (Badge::neutral("3 tags"), theme::text_secondary()),
+        ];
+
+        for (badge, color) in cases {
+            let span = badge.span();
+            assert_eq!(span.style, Style::default().fg(color), "{:?}", badge.kind());
+            assert_eq!(span.style, badge.kind().style());
+        }
+        assert_eq!(Badge::success("active").span().content, "active");
+        assert_eq!(BadgeKind::default(), BadgeKind::Neutral);
+    }
+
+    #[test]
+    fn test_chip_row_styles_each_tag() {
+        let tags = vec!["rust".to_string(), "cli".to_string()];
+        let spans = ChipRow::new(&tags, BadgeKind::Info).spans();
+
+        let text: String = spans.iter().map(|s| s.content.as_ref()).collect();
+        assert_eq!(text, "rust, cli");
+        assert_eq!(spans[0].style, BadgeKind::Info.style());
+        assert_eq!(spans[2].style, BadgeKind::Info.style());
+        assert_ne!(spans[1].style, BadgeKind::Info.style());
+
+        assert!(ChipRow::new(&[], BadgeKind::Info).spans().is_empty());
+    }
+}
@@ -248,8 +248,8 @@ mod tests {
     fn test_responsive_sizing() {
         let mut dialog = create_test_dialog();
 
-        // Test various terminal sizes
-        let sizes = vec![(40, 15), (60, 20), (80, 24), (120, 40)];
+        // Test various terminal sizes, down to a window shrunk to almost nothing
+        let sizes = vec![(3, 3), (10, 6), (40, 15), (60, 20), (80, 24), (120, 40)];
 
         for (width, height) in sizes {
             let mut terminal = setup_test_terminal(width, height).unwrap();
@@ -185,6 +185,10 @@ mod tests {
         // Should render without MCP servers section
         assert_buffer_contains(&terminal, "No MCP Extension");
         assert_buffer_contains(&terminal, "Extension without any MCP servers");
+        assert_buffer_not_contains(&terminal, "MCP Servers");
+
+        // The basic info keeps the full width rather than half a column
+        assert_buffer_contains(&terminal, "Description: Extension without any MCP servers");
     }
 
     #[test]
@@ -226,7 +230,7 @@ mod tests {
         // Verify all MCP server details are shown
         assert_buffer_contains(&terminal, "api-server");
         assert_buffer_contains(&terminal, "python");
-        // Working directory is not displayed in the current implementation
+        assert_buffer_contains(&terminal, "Cwd: /opt/api");
         assert_buffer_contains(&terminal, "Env: DEBUG = true");
         assert_buffer_contains(&terminal, "Env: API_KEY = $API_KEY");
         // Timeout is not displayed in the current implementation
@@ -378,4 +382,250 @@ mod tests {
             .unwrap();
         assert!(result.is_none());
     }
+
+    #[test]
+    fn test_install_and_update_times_are_humanized() {
+        let storage = create_test_storage();
+        let mut ext = ExtensionBuilder::new("Timestamps").build();
+        ext.metadata.imported_at = chrono::Utc::now() - chrono::Duration::days(3);
+        ext.metadata.updated_at = Some(chrono::Utc::now() - chrono::Duration::hours(2));
+        storage.save_extension(&ext).unwrap();
+
+        let mut detail = ExtensionDetail::new(storage, ext.id);
+        let mut terminal = setup_test_terminal(120, 30).unwrap();
+
+        terminal
+            .draw(|f| {
+                detail.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+
+        assert_buffer_contains(&terminal, "Installed 3 days ago");
+        assert_buffer_contains(&terminal, "Updated: 2 hours ago");
+    }
+
+    #[test]
+    fn test_format_arg_list_respects_width() {
+        use gemini_cli_manager::components::extension_detail::format_arg_list;
+
+        let args: Vec<String> = vec![
+            "--port".to_string(),
+            "8080".to_string(),
+            "--config=/a/very/long/path/to/some/config/file.json".to_string(),
+        ];
+
+        let lines = format_arg_list(&args, 20, 5);
+        assert_eq!(lines.len(), 3);
+        assert_eq!(lines[0], "--port");
+        assert_eq!(lines[1], "8080");
+        assert_eq!(lines[2].chars().count(), 20);
+        assert!(lines[2].ends_with('…'));
+        assert!(lines.iter().all(|line| line.chars().count() <= 20));
+    }
+
+    #[test]
+    fn test_format_arg_list_collapses_extra_args() {
+        use gemini_cli_manager::components::extension_detail::format_arg_list;
+
+        let args: Vec<String> = (1..=8).map(|i| format!("arg{i}")).collect();
+
+        let lines = format_arg_list(&args, 40, 3);
+        assert_eq!(lines, vec!["arg1", "arg2", "arg3", "+5 more"]);
+    }
+
+    #[test]
+    fn test_args_listed_in_detail_view() {
+        let storage = create_test_storage();
+        let mut ext = ExtensionBuilder::new("Many Args").build();
+        let mut servers = HashMap::new();
+        servers.insert(
+            "server".to_string(),
+            McpServerConfig {
+                command: Some("node".to_string()),
+                args: Some((1..=7).map(|i| format!("--flag{i}")).collect()),
+                cwd: None,
+                env: None,
+                trust: None,
+                timeout: None,
+                url: None,
+            },
+        );
+        ext.mcp_servers = servers;
+        storage.save_extension(&ext).unwrap();
+
+        let mut detail = ExtensionDetail::new(storage, ext.id);
+        let mut terminal = setup_test_terminal(80, 40).unwrap();
+
+        terminal
+            .draw(|f| {
+                detail.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+
+        assert_buffer_contains(&terminal, "Args: --flag1");
+        assert_buffer_contains(&terminal, "--flag5");
+        assert_buffer_contains(&terminal, "+2 more");
+        assert_buffer_not_contains(&terminal, "--flag6");
+    }
+
+    #[test]
+    fn test_install_hint_formatting() {
+        let mut ext = ExtensionBuilder::new("Db Tools").build();
+
+        ext.metadata.source_path = Some("https://github.com/acme/db-tools".to_string());
+        assert_eq!(
+            ext.install_hint(),
+            "gemini extensions install https://github.com/acme/db-tools"
+        );
+
+        ext.metadata.source_path = Some("git@github.com:acme/db-tools.git".to_string());
+        assert_eq!(
+            ext.install_hint(),
+            "gemini extensions install git@github.com:acme/db-tools.git"
+        );
+
+        // A local path means nothing on a teammate's machine
+        let local = "db-tools (installed from a local path, no shareable source)";
+        ext.metadata.source_path = Some("/home/me/extensions/db-tools".to_string());
+        assert_eq!(ext.install_hint(), local);
+
+        ext.metadata.source_path = None;
+        assert_eq!(ext.install_hint(), local);
+    }
+
+    #[test]
+    fn test_copy_install_hint() {
+        use gemini_cli_manager::utils::clipboard::MemoryClipboard;
+
+        let storage = create_test_storage();
+        let mut ext = ExtensionBuilder::new("Db Tools").build();
+        ext.metadata.source_path = Some("https://github.com/acme/db-tools".to_string());
+        storage.save_extension(&ext).unwrap();
+
+        let clipboard = MemoryClipboard::new();
+        let mut detail = ExtensionDetail::new(storage, ext.id.clone());
+        detail.set_clipboard(Box::new(clipboard.clone()));
+
+        let action = detail
+            .handle_events(Some(create_key_event(KeyCode::Char('y'))))
+            .unwrap();
+
+        assert!(matches!(action, Some(Action::Success(_))));
+        assert_eq!(
+            clipboard.contents().as_deref(),
+            Some("gemini extensions install https://github.com/acme/db-tools")
+        );
+
+        // Nothing to copy without an extension
+        let mut empty = ExtensionDetail::default();
+        let action = empty
+            .handle_events(Some(create_key_event(KeyCode::Char('y'))))
+            .unwrap();
+        assert_eq!(action, None);
+    }
+
+    #[test]
+    fn test_file_tree_toggles_with_f() {
+        let storage = create_test_storage();
+        let source = tempfile::TempDir::new().unwrap();
+        std::fs::write(source.path().join("gemini-extension.json"), "{}").unwrap();
+        std::fs::create_dir(source.path().join("server")).unwrap();
+        std::fs::write(source.path().join("server").join("index.js"), "run();").unwrap();
+
+        let mut ext = ExtensionBuilder::new("Db Tools").build();
+        ext.metadata.source_path = Some(
+            source
+                .path()
+                .join("gemini-extension.json")
+                .to_string_lossy()
+                .to_string(),
+        );
+        storage.save_extension(&ext).unwrap();
+        let mut detail = ExtensionDetail::new(storage, ext.id.clone());
+
+        let draw = |detail: &mut ExtensionDetail| {
+            let mut terminal = setup_test_terminal(80, 24).unwrap();
+            terminal
+                .draw(|f| detail.draw(f, f.area()).unwrap())
+                .unwrap();
+            terminal
+        };
+
+        let action = detail
+            .handle_events(Some(create_key_event(KeyCode::Char('f'))))
+            .unwrap();
+        assert_eq!(action, Some(Action::Render));
+        let terminal = draw(&mut detail);
+        assert_buffer_contains(&terminal, "server/");
+        assert_buffer_contains(&terminal, "  index.js  6 B");
+        assert_buffer_contains(&terminal, "gemini-extension.json  2 B");
+
+        // Esc closes the tree rather than leaving the view
+        let action = detail
+            .handle_events(Some(create_key_event(KeyCode::Esc)))
+            .unwrap();
+        assert_eq!(action, Some(Action::Render));
+        let terminal = draw(&mut detail);
+        assert_buffer_not_contains(&terminal, "index.js");
+
+        // Nothing to list once the source is gone
+        drop(source);
+        assert!(matches!(
+            detail.handle_events(Some(create_key_event(KeyCode::Char('f')))),
+            Ok(Some(Action::Error(_)))
+        ));
+    }
+
+    #[test]
+    fn test_notes_shown_and_edited_from_detail() {
+        let storage = create_test_storage();
+        let ext = ExtensionBuilder::new("Db Tools").build();
+        storage.save_extension(&ext).unwrap();
+        let mut detail = ExtensionDetail::new(storage.clone(), ext.id.clone());
+
+        let action = detail
+            .handle_events(Some(create_key_event(KeyCode::Char('n'))))
+            .unwrap();
+        assert_eq!(action, Some(Action::EditExtensionNotes(ext.id.clone())));
+
+        // Notes saved elsewhere appear once the list is refreshed
+        storage
+            .save_extension_notes(&ext.id, "Only works on the VPN")
+            .unwrap();
+        detail.update(Action::RefreshExtensions).unwrap();
+
+        let mut terminal = setup_test_terminal(80, 30).unwrap();
+        terminal
+            .draw(|f| detail.draw(f, f.area()).unwrap())
+            .unwrap();
+        assert_buffer_contains(&terminal, "Notes");
+        assert_buffer_contains(&terminal, "  Only works on the VPN");
+    }
+
+    #[test]
+    fn test_platforms_shown_with_warning_for_other_os() {
+        use gemini_cli_manager::models::extension::current_platform;
+
+        let storage = create_test_storage();
+        let mut ext = ExtensionBuilder::new("Plan Nine Tools").build();
+        ext.platforms = vec!["plan9".to_string()];
+        storage.save_extension(&ext).unwrap();
+        let mut detail = ExtensionDetail::new(storage.clone(), ext.id.clone());
+
+        let mut terminal = setup_test_terminal(100, 30).unwrap();
+        terminal
+            .draw(|f| detail.draw(f, f.area()).unwrap())
+            .unwrap();
+        assert_buffer_contains(&terminal, "Platforms: plan9");
+        assert_buffer_contains(&terminal, &format!("Not listed for {}", current_platform()));
+
+        // No warning once this system is listed
+        ext.platforms.push(current_platform().to_string());
+        storage.save_extension(&ext).unwrap();
+        detail.update(Action::RefreshExtensions).unwrap();
+        terminal
+            .draw(|f| detail.draw(f, f.area()).unwrap())
+            .unwrap();
+        assert_buffer_not_contains(&terminal, "Not listed for");
+    }
 }
@@ -163,6 +163,116 @@ mod tests {
         assert_buffer_contains(&terminal, "Args: echo-server.js");
     }
 
+    #[test]
+    fn test_mcp_servers_render_in_stable_order() {
+        let storage = create_test_storage();
+
+        let mut ext = ExtensionBuilder::new("Many Servers").build();
+        for name in ["zeta", "alpha", "mike", "delta", "kilo"] {
+            ext.mcp_servers.insert(
+                name.to_string(),
+                McpServerConfig {
+                    command: Some(format!("{name}-cmd")),
+                    args: None,
+                    cwd: None,
+                    env: None,
+                    trust: None,
+                    timeout: None,
+                    url: None,
+                },
+            );
+        }
+        storage.save_extension(&ext).unwrap();
+
+        let mut detail = ExtensionDetail::new(storage, ext.id.clone());
+        let mut renders = Vec::new();
+        for _ in 0..3 {
+            let mut terminal = setup_test_terminal(80, 50).unwrap();
+            terminal
+                .draw(|f| {
+                    detail.draw(f, f.area()).unwrap();
+                })
+                .unwrap();
+            renders.push(buffer_to_string(terminal.backend().buffer()));
+        }
+
+        assert!(renders.iter().all(|render| render == &renders[0]));
+
+        let positions: Vec<usize> = ["alpha", "delta", "kilo", "mike", "zeta"]
+            .iter()
+            .map(|name| renders[0].find(&format!("• {name}")).unwrap())
+            .collect();
+        assert!(positions.windows(2).all(|pair| pair[0] < pair[1]));
+    }
+
+    #[cfg(unix)]
+    #[test]
+    fn test_server_test_marks_each_server() {
+        use gemini_cli_manager::action::Action;
+
+        let storage = create_test_storage();
+        let server = |command: &str, args: &[&str]| McpServerConfig {
+            command: Some(command.to_string()),
+            args: Some(args.iter().map(|arg| arg.to_string()).collect()),
+            cwd: None,
+            env: None,
+            trust: None,
+            timeout: None,
+            url: None,
+        };
+
+        let mut ext = ExtensionBuilder::new("Mixed Servers").build();
+        ext.mcp_servers
+            .insert("steady".to_string(), server("sleep", &["30"]));
+        ext.mcp_servers.insert(
+            "broken".to_string(),
+            server("sh", &["-c", "echo 'missing API key' >&2; exit 1"]),
+        );
+        storage.save_extension(&ext).unwrap();
+
+        let mut detail = ExtensionDetail::new(storage, ext.id.clone());
+        let (tx, mut rx) = tokio::sync::mpsc::unbounded_channel();
+        detail.register_action_handler(tx).unwrap();
+
+        // The servers are tried in the background; a second press waits for them
+        let action = detail
+            .handle_events(Some(create_key_event(KeyCode::Char('t'))))
+            .unwrap();
+        assert_eq!(
+            action,
+            Some(Action::Success("Testing 2 MCP server(s)...".to_string()))
+        );
+        assert_eq!(
+            detail
+                .handle_events(Some(create_key_event(KeyCode::Char('t'))))
+                .unwrap(),
+            None
+        );
+
+        let tested = rx.blocking_recv().unwrap();
+        assert!(matches!(tested, Action::ServersTested(ref id, _) if *id == ext.id));
+        let action = detail.update(tested).unwrap();
+        assert!(matches!(action, Some(Action::Error(message)) if message.contains("broken")));
+
+        // Every server is tried, not just the ones before the first failure
+        let checks = detail.server_checks();
+        assert_eq!(checks["steady"], Ok(()));
+        assert!(
+            checks["broken"]
+                .as_ref()
+                .unwrap_err()
+                .contains("missing API key")
+        );
+
+        let mut terminal = setup_test_terminal(100, 50).unwrap();
+        terminal
+            .draw(|f| {
+                detail.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "Test: 'sh' exited immediately");
+    }
+
     #[test]
     fn test_context_content_display() {
         let mut detail = create_test_detail();
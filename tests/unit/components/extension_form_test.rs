@@ -3,7 +3,7 @@ mod tests {
     use crate::test_utils::*;
     use crossterm::event::{KeyCode, KeyEvent, KeyEventKind};
     use gemini_cli_manager::components::Component;
-    use gemini_cli_manager::components::extension_form::{ExtensionForm, FormField};
+    use gemini_cli_manager::components::extension_form::{ExtensionForm, FormField, FormStep};
     use insta::assert_snapshot;
 
     fn create_key_event(code: KeyCode) -> gemini_cli_manager::tui::Event {
@@ -101,6 +101,153 @@ mod tests {
         assert_eq!(form.current_field(), &FormField::Version);
     }
 
+    #[test]
+    fn test_focus_keys_in_context_and_servers() {
+        let mut form = create_test_form();
+
+        // The context editor doesn't swallow Tab
+        form.set_initial_field(FormField::ContextContent);
+        form.handle_events(Some(create_key_event(KeyCode::Tab)))
+            .unwrap();
+        assert_eq!(form.current_field(), &FormField::McpServers);
+        form.handle_events(Some(create_key_event(KeyCode::BackTab)))
+            .unwrap();
+        form.handle_events(Some(create_key_event(KeyCode::BackTab)))
+            .unwrap();
+        assert_eq!(form.current_field(), &FormField::ContextFileName);
+
+        // Focus wraps between the server list and the first field
+        form.set_initial_field(FormField::McpServers);
+        form.handle_events(Some(create_key_event(KeyCode::Tab)))
+            .unwrap();
+        assert_eq!(form.current_field(), &FormField::Name);
+        form.handle_events(Some(create_key_event(KeyCode::BackTab)))
+            .unwrap();
+        assert_eq!(form.current_field(), &FormField::McpServers);
+    }
+
+    #[test]
+    fn test_detail_field_follows_detail_sections() {
+        assert_eq!(
+            ExtensionForm::detail_field(&McpFixtures::echo_extension()),
+            FormField::McpServers
+        );
+        assert_eq!(
+            ExtensionForm::detail_field(&McpFixtures::context_only_extension()),
+            FormField::ContextContent
+        );
+        assert_eq!(
+            ExtensionForm::detail_field(&ExtensionBuilder::new("Bare").build()),
+            FormField::Name
+        );
+    }
+
+    #[test]
+    fn test_footers_fit_80_columns() {
+        let mut form = create_test_form();
+        let mut terminal = setup_test_terminal(80, 30).unwrap();
+
+        terminal.draw(|f| form.draw(f, f.area()).unwrap()).unwrap();
+        assert_buffer_contains(&terminal, "Type: Edit");
+        assert_buffer_contains(&terminal, "Ctrl+P: Preview | Esc, b: Cancel");
+
+        form.set_initial_field(FormField::ContextContent);
+        terminal.draw(|f| form.draw(f, f.area()).unwrap()).unwrap();
+        assert_buffer_contains(&terminal, "Tab, Shift+Tab: Move focus");
+        assert_buffer_contains(&terminal, "Type: Edit");
+        assert_buffer_contains(&terminal, "Esc, b: Cancel");
+
+        form.set_initial_field(FormField::McpServers);
+        terminal.draw(|f| form.draw(f, f.area()).unwrap()).unwrap();
+        assert_buffer_contains(&terminal, "Down, j: Navigate");
+        assert_buffer_contains(&terminal, "Ctrl+P: Preview | Esc, b: Cancel");
+
+        form.handle_events(Some(create_key_event(KeyCode::Char('n'))))
+            .unwrap();
+        terminal.draw(|f| form.draw(f, f.area()).unwrap()).unwrap();
+        assert_buffer_contains(&terminal, "Esc, b: Cancel");
+        assert_buffer_contains(&terminal, "Tab, Shift+Tab: Switch server field");
+    }
+
+    #[test]
+    fn test_focus_keys_in_server_editor() {
+        let mut form = create_test_form();
+        form.set_initial_field(FormField::McpServers);
+        assert_eq!(form.server_field_cursor(), None);
+
+        form.handle_events(Some(create_key_event(KeyCode::Char('n'))))
+            .unwrap();
+        assert_eq!(form.server_field_cursor(), Some(0));
+
+        // Tab and Shift+Tab cycle the server's own inputs
+        form.handle_events(Some(create_key_event(KeyCode::Tab)))
+            .unwrap();
+        assert_eq!(form.server_field_cursor(), Some(1));
+        form.handle_events(Some(create_key_event(KeyCode::BackTab)))
+            .unwrap();
+        form.handle_events(Some(create_key_event(KeyCode::BackTab)))
+            .unwrap();
+        assert_eq!(form.server_field_cursor(), Some(6));
+        assert_eq!(form.current_field(), &FormField::McpServers);
+
+        form.handle_events(Some(create_key_event(KeyCode::Esc)))
+            .unwrap();
+        assert_eq!(form.server_field_cursor(), None);
+    }
+
+    #[test]
+    fn test_esc_in_preview_returns_to_edit() {
+        use gemini_cli_manager::action::Action;
+
+        let mut form = create_test_form();
+        for ch in "Previewed".chars() {
+            form.handle_events(Some(create_key_event(KeyCode::Char(ch))))
+                .unwrap();
+        }
+
+        let ctrl_p = KeyEvent {
+            code: KeyCode::Char('p'),
+            modifiers: crossterm::event::KeyModifiers::CONTROL,
+            kind: KeyEventKind::Press,
+            state: crossterm::event::KeyEventState::NONE,
+        };
+        form.handle_events(Some(gemini_cli_manager::tui::Event::Key(ctrl_p)))
+            .unwrap();
+        assert_eq!(form.current_step(), FormStep::Preview);
+
+        let output = render_to_string(80, 30, |f| {
+            form.draw(f, f.area()).unwrap();
+        })
+        .unwrap();
+        assert!(output.contains("gemini-extension.json"));
+        assert!(output.contains("\"name\": \"Previewed\""));
+        assert!(output.contains("Esc, b: Back to edit"));
+
+        // Esc leaves the preview but keeps the form open with its contents
+        let result = form
+            .handle_events(Some(create_key_event(KeyCode::Esc)))
+            .unwrap();
+        assert_eq!(result, Some(Action::Render));
+        assert_eq!(form.current_step(), FormStep::Fields);
+        assert_eq!(form.name_input().value(), "Previewed");
+
+        // So does `b`, as the preview footer says
+        form.handle_events(Some(gemini_cli_manager::tui::Event::Key(ctrl_p)))
+            .unwrap();
+        let result = form
+            .handle_events(Some(create_key_event(KeyCode::Char('b'))))
+            .unwrap();
+        assert_eq!(result, Some(Action::Render));
+        assert_eq!(form.current_step(), FormStep::Fields);
+        assert_eq!(form.name_input().value(), "Previewed");
+
+        // Only Esc on the fields closes the form
+        let result = form
+            .handle_events(Some(create_key_event(KeyCode::Esc)))
+            .unwrap();
+        assert_eq!(result, Some(Action::NavigateBack));
+    }
+
     #[test]
     fn test_text_input() {
         let mut form = create_test_form();
@@ -332,4 +479,53 @@ mod tests {
 
         assert_snapshot!(output.unwrap());
     }
+
+    #[test]
+    fn test_form_opens_on_requested_field() {
+        let mut form = create_edit_form("test");
+        form.set_initial_field(FormField::ContextContent);
+
+        assert_eq!(form.current_field(), &FormField::ContextContent);
+
+        // Navigation continues from the requested field
+        form.handle_events(Some(create_key_event(KeyCode::Tab)))
+            .unwrap();
+        assert_eq!(form.current_field(), &FormField::McpServers);
+    }
+
+    #[test]
+    fn test_save_without_version_is_rejected() {
+        use gemini_cli_manager::action::Action;
+
+        let storage = create_test_storage();
+        let ext = ExtensionBuilder::new("Versioned")
+            .with_version("1.2.3")
+            .build();
+        storage.save_extension(&ext).unwrap();
+
+        let mut form = ExtensionForm::with_extension(storage.clone(), &ext);
+        form.set_initial_field(FormField::Version);
+        for _ in 0.."1.2.3".len() {
+            form.handle_events(Some(create_key_event(KeyCode::Backspace)))
+                .unwrap();
+        }
+        assert!(form.version_input().value().is_empty());
+
+        let key_event = KeyEvent {
+            code: KeyCode::Char('s'),
+            modifiers: crossterm::event::KeyModifiers::CONTROL,
+            kind: KeyEventKind::Press,
+            state: crossterm::event::KeyEventState::NONE,
+        };
+        let result = form
+            .handle_events(Some(gemini_cli_manager::tui::Event::Key(key_event)))
+            .unwrap();
+
+        // Blocked with an error naming the missing field, and nothing written
+        match result {
+            Some(Action::Error(message)) => assert!(message.contains("version is required")),
+            other => panic!("expected a validation error, got {other:?}"),
+        }
+        assert_eq!(storage.load_extension(&ext.id).unwrap().version, "1.2.3");
+    }
 }
@@ -191,6 +191,83 @@ mod tests {
         assert_eq!(list.filtered_count(), 1);
     }
 
+    #[test]
+    fn test_search_by_path() {
+        let storage = create_test_storage();
+
+        let mut ext1 = ExtensionBuilder::new("Database Tools").build();
+        ext1.metadata.source_path = Some("/opt/extensions/db-tools".to_string());
+        storage.save_extension(&ext1).unwrap();
+
+        let mut ext2 = ExtensionBuilder::new("Path Helper").build();
+        ext2.metadata.source_path = Some("/home/user/ext/helper".to_string());
+        storage.save_extension(&ext2).unwrap();
+
+        // No source path at all
+        let ext3 = ExtensionBuilder::new("Opt Out").build();
+        storage.save_extension(&ext3).unwrap();
+
+        let mut list = ExtensionList::with_storage(storage);
+        list.handle_events(Some(create_key_event(KeyCode::Char('/'))))
+            .unwrap();
+        for ch in "path:/opt/".chars() {
+            list.handle_events(Some(create_key_event(KeyCode::Char(ch))))
+                .unwrap();
+        }
+
+        // Only the extension imported from /opt matches, not names containing "opt"
+        assert_eq!(list.filtered_count(), 1);
+
+        let mut terminal = setup_test_terminal(80, 20).unwrap();
+        terminal
+            .draw(|f| {
+                list.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "Database Tools");
+        assert_buffer_not_contains(&terminal, "Path Helper");
+    }
+
+    #[test]
+    fn test_search_ranks_fuzzy_name_matches() {
+        use gemini_cli_manager::action::Action;
+
+        let storage = create_test_storage();
+        for (name, description) in [
+            ("Aim Arrow Rack", "Target practice"),
+            ("Data Tools", "Export tables to markdown"),
+            ("Markdown Assistant", "Formatting help"),
+            ("Unrelated", "Nothing to see"),
+        ] {
+            let ext = ExtensionBuilder::new(name)
+                .with_description(description)
+                .build();
+            storage.save_extension(&ext).unwrap();
+        }
+
+        let mut list = ExtensionList::with_storage(storage);
+        list.handle_events(Some(create_key_event(KeyCode::Char('/'))))
+            .unwrap();
+        for ch in "mark".chars() {
+            list.handle_events(Some(create_key_event(KeyCode::Char(ch))))
+                .unwrap();
+        }
+
+        // Both names match with gaps allowed, and the description match is kept
+        assert_eq!(list.filtered_count(), 3);
+
+        // The tightest name match comes first, ahead of storage order
+        let action = list
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert_eq!(
+            action,
+            Some(Action::ViewExtensionDetails(
+                "markdown-assistant".to_string()
+            ))
+        );
+    }
+
     #[test]
     fn test_deletion_protection() {
         let mut list = create_test_list();
@@ -248,4 +325,413 @@ mod tests {
             .unwrap();
         assert_eq!(list.selected_index(), 0);
     }
+
+    #[test]
+    fn test_group_recent_orders_newest_first() {
+        use chrono::{Duration, Utc};
+        use gemini_cli_manager::components::extension_list::group_recent;
+
+        let now = Utc::now();
+        let ages = [48, 2, 30, 1, 23];
+        let extensions: Vec<_> = ages
+            .iter()
+            .enumerate()
+            .map(|(i, hours)| {
+                let mut ext = ExtensionBuilder::new(&format!("Ext {i}")).build();
+                ext.metadata.imported_at = now - Duration::hours(*hours);
+                ext
+            })
+            .collect();
+
+        let (ordered, recent_count) = group_recent(&extensions, &[0, 1, 2, 3, 4], now);
+
+        // Installed within the last day, newest first, then the rest in original order
+        assert_eq!(recent_count, 3);
+        assert_eq!(ordered, vec![3, 1, 4, 0, 2]);
+
+        // Only the indices passed in are considered
+        let (ordered, recent_count) = group_recent(&extensions, &[0, 2], now);
+        assert_eq!(recent_count, 0);
+        assert_eq!(ordered, vec![0, 2]);
+    }
+
+    #[test]
+    fn test_group_recent_counts_updates() {
+        use chrono::{Duration, Utc};
+        use gemini_cli_manager::components::extension_list::group_recent;
+
+        let now = Utc::now();
+        let mut updated = ExtensionBuilder::new("Updated").build();
+        updated.metadata.imported_at = now - Duration::days(30);
+        updated.metadata.updated_at = Some(now - Duration::hours(1));
+        let mut installed = ExtensionBuilder::new("Installed").build();
+        installed.metadata.imported_at = now - Duration::hours(2);
+        let mut stale = ExtensionBuilder::new("Stale").build();
+        stale.metadata.imported_at = now - Duration::days(30);
+        stale.metadata.updated_at = Some(now - Duration::days(3));
+
+        // A fresh update counts as recent, ordered by when it happened
+        let extensions = vec![stale, installed, updated];
+        let (ordered, recent_count) = group_recent(&extensions, &[0, 1, 2], now);
+        assert_eq!(recent_count, 2);
+        assert_eq!(ordered, vec![2, 1, 0]);
+    }
+
+    #[test]
+    fn test_sort_mode_toggle_groups_recent() {
+        use chrono::{Duration, Utc};
+        use gemini_cli_manager::components::extension_list::SortMode;
+
+        let storage = create_test_storage();
+
+        let mut old = ExtensionBuilder::new("Aardvark Tools").build();
+        old.metadata.imported_at = Utc::now() - Duration::days(10);
+        storage.save_extension(&old).unwrap();
+
+        let fresh = ExtensionBuilder::new("Zebra Tools").build();
+        storage.save_extension(&fresh).unwrap();
+
+        let mut list = ExtensionList::with_storage(storage);
+        assert_eq!(list.sort_mode(), SortMode::Default);
+        assert_eq!(list.recent_count(), 0);
+
+        list.handle_events(Some(create_key_event(KeyCode::Char('s'))))
+            .unwrap();
+        assert_eq!(list.sort_mode(), SortMode::RecentFirst);
+        assert_eq!(list.recent_count(), 1);
+
+        let mut terminal = setup_test_terminal(80, 20).unwrap();
+        terminal
+            .draw(|f| {
+                list.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "Recent first");
+
+        // The freshly installed extension is listed before the older one
+        let content = buffer_to_string(terminal.backend().buffer());
+        assert!(content.find("Zebra Tools").unwrap() < content.find("Aardvark Tools").unwrap());
+
+        list.handle_events(Some(create_key_event(KeyCode::Char('s'))))
+            .unwrap();
+        assert_eq!(list.sort_mode(), SortMode::Default);
+        assert_eq!(list.recent_count(), 0);
+    }
+
+    #[test]
+    fn test_compact_toggle_hides_card_metadata() {
+        let mut list = create_test_list();
+        assert!(!list.is_compact());
+
+        let mut terminal = setup_test_terminal(80, 20).unwrap();
+        terminal
+            .draw(|f| {
+                list.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "MCP servers");
+
+        list.handle_events(Some(create_key_event(KeyCode::Char('m'))))
+            .unwrap();
+        assert!(list.is_compact());
+
+        terminal
+            .draw(|f| {
+                list.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        // Title and description remain, metadata rows are gone
+        assert_buffer_contains(&terminal, "Extension One");
+        assert_buffer_contains(&terminal, "First test extension");
+        assert_buffer_not_contains(&terminal, "MCP servers");
+
+        // The preference survives a reload of the list
+        list.update(gemini_cli_manager::action::Action::RefreshExtensions)
+            .unwrap();
+        assert!(list.is_compact());
+
+        list.handle_events(Some(create_key_event(KeyCode::Char('m'))))
+            .unwrap();
+        assert!(!list.is_compact());
+    }
+
+    #[test]
+    fn test_compact_cards_setting_seeds_density() {
+        use gemini_cli_manager::action::Action;
+        use gemini_cli_manager::components::settings_view::UserSettings;
+        use std::sync::{Arc, RwLock};
+
+        let settings = UserSettings {
+            compact_cards: true,
+            ..UserSettings::default()
+        };
+        let mut list = create_test_list();
+        list.register_settings_handler(Arc::new(RwLock::new(settings)))
+            .unwrap();
+        assert!(list.is_compact());
+
+        let mut terminal = setup_test_terminal(80, 20).unwrap();
+        terminal
+            .draw(|f| {
+                list.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "Extension One");
+        assert_buffer_not_contains(&terminal, "MCP servers");
+
+        // Cards are two rows each with no spacer between them
+        let screen = buffer_to_string(terminal.backend().buffer());
+        let mut title_rows: Vec<usize> = ["Extension One", "Extension Two", "Another Extension"]
+            .iter()
+            .filter_map(|name| screen.lines().position(|line| line.contains(name)))
+            .collect();
+        title_rows.sort();
+        assert_eq!(title_rows.len(), 3);
+        assert_eq!(title_rows[1] - title_rows[0], 2);
+        assert_eq!(title_rows[2] - title_rows[1], 2);
+
+        // Changing the setting applies without a restart
+        list.update(Action::SetCompactCards(false)).unwrap();
+        assert!(!list.is_compact());
+    }
+
+    #[test]
+    fn test_in_active_profile_predicate() {
+        use gemini_cli_manager::components::extension_list::in_active_profile;
+
+        let ext = ExtensionBuilder::new("Extension One").build();
+        let other = ExtensionBuilder::new("Extension Two").build();
+        let profile = ProfileBuilder::new("Active")
+            .with_extensions(vec!["extension-one"])
+            .build();
+
+        assert!(in_active_profile(Some(&profile), &ext));
+        assert!(!in_active_profile(Some(&profile), &other));
+        // Without an active profile nothing is badged
+        assert!(!in_active_profile(None, &ext));
+    }
+
+    #[test]
+    fn test_active_profile_badge_follows_default_profile() {
+        let storage = create_test_storage();
+        storage
+            .save_extension(&ExtensionBuilder::new("Extension One").build())
+            .unwrap();
+        storage
+            .save_extension(&ExtensionBuilder::new("Extension Two").build())
+            .unwrap();
+
+        let mut list = ExtensionList::with_storage(storage.clone());
+        assert!(list.active_profile().is_none());
+
+        let mut terminal = setup_test_terminal(60, 20).unwrap();
+        terminal
+            .draw(|f| {
+                list.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_not_contains(&terminal, "v1.0.0  ✓");
+        assert_buffer_not_contains(&terminal, "v1.0.0  x");
+
+        // Making a profile the default badges its extensions
+        let profile = ProfileBuilder::new("Active")
+            .with_extensions(vec!["extension-two"])
+            .as_default()
+            .build();
+        storage.save_profile(&profile).unwrap();
+        list.update(gemini_cli_manager::action::Action::RefreshProfiles)
+            .unwrap();
+        assert_eq!(
+            list.active_profile().map(|p| p.name.as_str()),
+            Some("Active")
+        );
+
+        terminal
+            .draw(|f| {
+                list.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        // The badge is an ASCII "x" when emoji are turned off
+        let content = buffer_to_string(terminal.backend().buffer());
+        let badged: Vec<&str> = content
+            .lines()
+            .filter(|l| l.contains("v1.0.0  ✓") || l.contains("v1.0.0  x"))
+            .collect();
+        assert_eq!(badged.len(), 1);
+        assert!(badged[0].contains("Extension Two"));
+    }
+
+    #[test]
+    fn test_sync_defaults_key() {
+        use gemini_cli_manager::action::Action;
+
+        let storage = create_test_storage();
+        storage
+            .save_extension(&ExtensionBuilder::new("Extension One").build())
+            .unwrap();
+        storage
+            .save_extension(&ExtensionBuilder::new("Extension Two").build())
+            .unwrap();
+        let mut list = ExtensionList::with_storage(storage.clone());
+
+        // Nothing to sync from without a default profile
+        let action = list
+            .handle_events(Some(create_key_event(KeyCode::Char('p'))))
+            .unwrap();
+        assert!(matches!(action, Some(Action::Error(_))));
+
+        let profile = ProfileBuilder::new("Active")
+            .with_extensions(vec!["extension-two"])
+            .as_default()
+            .build();
+        storage.save_profile(&profile).unwrap();
+
+        let action = list
+            .handle_events(Some(create_key_event(KeyCode::Char('p'))))
+            .unwrap();
+        assert!(matches!(action, Some(Action::Success(_))));
+        assert!(
+            storage
+                .load_extension("extension-two")
+                .unwrap()
+                .enabled_by_default
+        );
+        assert!(
+            !storage
+                .load_extension("extension-one")
+                .unwrap()
+                .enabled_by_default
+        );
+    }
+
+    #[test]
+    fn test_manifest_icon_appears_on_card() {
+        let storage = create_test_storage();
+        let mut branded = ExtensionBuilder::new("Branded").build();
+        branded.icon = Some("λ".to_string());
+        storage.save_extension(&branded).unwrap();
+        let mut blank = ExtensionBuilder::new("Plain").build();
+        blank.icon = Some("  ".to_string());
+        storage.save_extension(&blank).unwrap();
+
+        let mut list = ExtensionList::with_storage(storage);
+        let mut terminal = setup_test_terminal(60, 20).unwrap();
+        terminal
+            .draw(|f| {
+                list.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+
+        assert_buffer_contains(&terminal, "λ Branded v1.0.0");
+        // A blank icon is treated as no icon
+        assert_eq!(blank.display_icon(), None);
+        assert_buffer_contains(&terminal, "Plain v1.0.0");
+    }
+
+    #[test]
+    fn test_unused_filter_and_bulk_delete_key() {
+        use gemini_cli_manager::action::Action;
+
+        let storage = create_test_storage();
+        storage
+            .save_extension(&ExtensionBuilder::new("Extension One").build())
+            .unwrap();
+        storage
+            .save_extension(&ExtensionBuilder::new("Extension Two").build())
+            .unwrap();
+        let profile = ProfileBuilder::new("Uses Two")
+            .with_extensions(vec!["extension-two"])
+            .build();
+        storage.save_profile(&profile).unwrap();
+
+        let mut list = ExtensionList::with_storage(storage.clone());
+
+        // Bulk delete is only offered while unused extensions are shown
+        let action = list
+            .handle_events(Some(create_key_event(KeyCode::Char('D'))))
+            .unwrap();
+        assert_eq!(action, None);
+
+        list.handle_events(Some(create_key_event(KeyCode::Char('u'))))
+            .unwrap();
+        assert!(list.is_orphans_only());
+        assert_eq!(list.filtered_count(), 1);
+        assert_eq!(list.total_count(), 2);
+
+        let action = list
+            .handle_events(Some(create_key_event(KeyCode::Char('D'))))
+            .unwrap();
+        assert_eq!(action, Some(Action::DeleteUnusedExtensions));
+
+        // Once a profile uses it too, nothing is left over
+        let profile = ProfileBuilder::new("Uses One")
+            .with_extensions(vec!["extension-one"])
+            .build();
+        storage.save_profile(&profile).unwrap();
+        list.update(Action::RefreshProfiles).unwrap();
+        assert_eq!(list.filtered_count(), 0);
+
+        let mut terminal = setup_test_terminal(60, 20).unwrap();
+        terminal
+            .draw(|f| {
+                list.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "Every extension is used by a profile");
+
+        list.handle_events(Some(create_key_event(KeyCode::Char('u'))))
+            .unwrap();
+        assert_eq!(list.filtered_count(), 2);
+    }
+
+    #[test]
+    fn test_space_toggles_extension_in_default_profile() {
+        use gemini_cli_manager::action::Action;
+
+        let storage = create_test_storage();
+        storage
+            .save_extension(&ExtensionBuilder::new("Extension One").build())
+            .unwrap();
+        storage
+            .save_extension(&ExtensionBuilder::new("Extension Two").build())
+            .unwrap();
+        let mut list = ExtensionList::with_storage(storage.clone());
+
+        // Without a default profile there is nowhere to enable it
+        let action = list
+            .handle_events(Some(create_key_event(KeyCode::Char(' '))))
+            .unwrap();
+        assert!(matches!(action, Some(Action::Error(_))));
+
+        let profile = ProfileBuilder::new("Active")
+            .with_extensions(vec!["extension-one"])
+            .as_default()
+            .build();
+        storage.save_profile(&profile).unwrap();
+
+        list.handle_events(Some(create_key_event(KeyCode::Down)))
+            .unwrap();
+        let action = list
+            .handle_events(Some(create_key_event(KeyCode::Char(' '))))
+            .unwrap();
+        assert_eq!(
+            action,
+            Some(Action::Success(
+                "Enabled 'Extension Two' in 'Active'".to_string()
+            ))
+        );
+        assert_eq!(
+            storage.load_profile("active").unwrap().extension_ids,
+            vec!["extension-one", "extension-two"]
+        );
+
+        let action = list
+            .handle_events(Some(create_key_event(KeyCode::Char(' '))))
+            .unwrap();
+        assert!(matches!(action, Some(Action::Success(msg)) if msg.starts_with("Disabled")));
+        assert_eq!(
+            storage.load_profile("active").unwrap().extension_ids,
+            vec!["extension-one"]
+        );
+    }
 }
@@ -0,0 +1,132 @@
+#[cfg(test)]
+mod tests {
+    use crate::test_utils::*;
+    use crossterm::event::{KeyCode, KeyEvent, KeyEventKind};
+    use gemini_cli_manager::action::Action;
+    use gemini_cli_manager::components::Component;
+    use gemini_cli_manager::components::icon_picker::IconPicker;
+
+    fn create_key_event(code: KeyCode) -> gemini_cli_manager::tui::Event {
+        use crossterm::event::KeyModifiers;
+        gemini_cli_manager::tui::Event::Key(KeyEvent {
+            code,
+            modifiers: KeyModifiers::NONE,
+            kind: KeyEventKind::Press,
+            state: crossterm::event::KeyEventState::NONE,
+        })
+    }
+
+    fn type_text(picker: &mut IconPicker, text: &str) {
+        for ch in text.chars() {
+            picker
+                .handle_events(Some(create_key_event(KeyCode::Char(ch))))
+                .unwrap();
+        }
+    }
+
+    #[test]
+    fn test_filter_by_keyword() {
+        let mut picker = IconPicker::new().with_ascii(false);
+        let all = picker.matching_icons().len();
+
+        type_text(&mut picker, "ai");
+        assert_eq!(picker.matching_icons(), vec!["🧠", "🤖"]);
+        assert_eq!(picker.selected_icon(), Some("🧠"));
+
+        // Every word has to match
+        type_text(&mut picker, " bot");
+        assert_eq!(picker.matching_icons(), vec!["🤖"]);
+
+        type_text(&mut picker, "x");
+        assert!(picker.matching_icons().is_empty());
+        assert_eq!(picker.selected_icon(), None);
+        let action = picker
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert_eq!(action, None);
+
+        // Clearing the filter brings everything back
+        for _ in 0.."ai botx".len() {
+            picker
+                .handle_events(Some(create_key_event(KeyCode::Backspace)))
+                .unwrap();
+        }
+        assert_eq!(picker.matching_icons().len(), all);
+    }
+
+    #[test]
+    fn test_grid_navigation_and_selection() {
+        let mut picker = IconPicker::new().with_ascii(false);
+        assert_eq!(picker.selected_icon(), Some("🚀"));
+
+        // Left at the first cell stays put
+        picker
+            .handle_events(Some(create_key_event(KeyCode::Left)))
+            .unwrap();
+        assert_eq!(picker.selected_icon(), Some("🚀"));
+
+        picker
+            .handle_events(Some(create_key_event(KeyCode::Right)))
+            .unwrap();
+        assert_eq!(picker.selected_icon(), Some("💻"));
+
+        // Down moves a whole row of eight
+        picker
+            .handle_events(Some(create_key_event(KeyCode::Down)))
+            .unwrap();
+        assert_eq!(picker.selected_icon(), Some("📊"));
+
+        let action = picker
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert_eq!(action, Some(Action::IconPicked(Some("📊".to_string()))));
+
+        let action = picker
+            .handle_events(Some(create_key_event(KeyCode::Delete)))
+            .unwrap();
+        assert_eq!(action, Some(Action::IconPicked(None)));
+
+        let action = picker
+            .handle_events(Some(create_key_event(KeyCode::Esc)))
+            .unwrap();
+        assert_eq!(action, Some(Action::NavigateBack));
+    }
+
+    #[test]
+    fn test_starts_on_current_icon_and_renders() {
+        let mut picker = IconPicker::with_selected(Some("🦀")).with_ascii(false);
+        assert_eq!(picker.selected_icon(), Some("🦀"));
+
+        let mut terminal = setup_test_terminal(80, 24).unwrap();
+        terminal
+            .draw(|f| {
+                picker.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "Choose an Icon");
+        assert_buffer_contains(&terminal, "crab rust");
+    }
+
+    #[test]
+    fn test_ascii_mode_offers_ascii_icons() {
+        // A stored emoji still selects its stand-in
+        let mut picker = IconPicker::with_selected(Some("🦀")).with_ascii(true);
+        assert_eq!(picker.selected_icon(), Some("[rs]"));
+        assert!(picker.matching_icons().iter().all(|icon| icon.is_ascii()));
+
+        type_text(&mut picker, "docker");
+        assert_eq!(picker.matching_icons(), vec!["[dkr]"]);
+        let action = picker
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert_eq!(action, Some(Action::IconPicked(Some("[dkr]".to_string()))));
+
+        let mut terminal = setup_test_terminal(80, 24).unwrap();
+        terminal
+            .draw(|f| {
+                picker.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "[dkr]");
+    }
+}
@@ -0,0 +1,460 @@
+#[cfg(test)]
+mod tests {
+    use crate::test_utils::*;
+    use crossterm::event::{KeyCode, KeyEvent, KeyEventKind};
+    use gemini_cli_manager::components::Component;
+    use gemini_cli_manager::components::import_dialog::ImportDialog;
+
+    fn create_key_event(code: KeyCode) -> gemini_cli_manager::tui::Event {
+        use crossterm::event::KeyModifiers;
+        gemini_cli_manager::tui::Event::Key(KeyEvent {
+            code,
+            modifiers: KeyModifiers::NONE,
+            kind: KeyEventKind::Press,
+            state: crossterm::event::KeyEventState::NONE,
+        })
+    }
+
+    /// Write an extension manifest somewhere outside of storage, as if it came from another source
+    fn write_manifest(dir: &std::path::Path, name: &str, version: &str) -> std::path::PathBuf {
+        let path = dir.join("extension.json");
+        let manifest = serde_json::json!({
+            "name": name,
+            "version": version,
+            "description": "Installed from a second source",
+        });
+        std::fs::write(&path, manifest.to_string()).unwrap();
+        path
+    }
+
+    #[test]
+    fn test_import_of_installed_extension_asks_to_update() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let mut existing = ExtensionBuilder::new("Database Tools").build();
+        existing.metadata.source_path = Some("/opt/extensions/db-tools".to_string());
+        storage.save_extension(&existing).unwrap();
+
+        let source = tempfile::TempDir::new().unwrap();
+        let manifest = write_manifest(source.path(), "database tools", "2.0.0");
+
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(manifest.clone()).unwrap();
+
+        // Detected as the same extension: nothing is written until confirmed
+        assert!(dialog.is_confirming_update());
+        assert_eq!(storage.list_extensions().unwrap().len(), 1);
+
+        let mut terminal = setup_test_terminal(100, 30).unwrap();
+        terminal
+            .draw(|f| {
+                dialog.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "already installed");
+
+        dialog
+            .handle_events(Some(create_key_event(KeyCode::Char('y'))))
+            .unwrap();
+
+        // Updated in place rather than duplicated
+        let extensions = storage.list_extensions().unwrap();
+        assert_eq!(extensions.len(), 1);
+        let updated = &extensions[0];
+        assert_eq!(updated.id, existing.id);
+        assert_eq!(updated.version, "2.0.0");
+        assert_eq!(
+            updated.metadata.source_path.as_deref(),
+            Some(manifest.to_string_lossy().as_ref())
+        );
+        assert!(updated.metadata.updated_at.is_some());
+    }
+
+    #[test]
+    fn test_declined_update_leaves_installed_extension() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let existing = ExtensionBuilder::new("Database Tools").build();
+        storage.save_extension(&existing).unwrap();
+
+        let source = tempfile::TempDir::new().unwrap();
+        let manifest = write_manifest(source.path(), "Database Tools", "2.0.0");
+
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(manifest).unwrap();
+        dialog
+            .handle_events(Some(create_key_event(KeyCode::Esc)))
+            .unwrap();
+
+        assert!(!dialog.is_confirming_update());
+        let extensions = storage.list_extensions().unwrap();
+        assert_eq!(extensions.len(), 1);
+        assert_eq!(extensions[0].version, existing.version);
+    }
+
+    #[test]
+    fn test_import_of_new_extension_is_saved() {
+        let (storage, _temp_dir) = create_temp_storage();
+        storage
+            .save_extension(&ExtensionBuilder::new("Database Tools").build())
+            .unwrap();
+
+        let source = tempfile::TempDir::new().unwrap();
+        let manifest = write_manifest(source.path(), "Web Tools", "1.0.0");
+
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(manifest).unwrap();
+
+        assert!(!dialog.is_confirming_update());
+        assert_eq!(storage.list_extensions().unwrap().len(), 2);
+    }
+
+    #[test]
+    fn test_context_file_with_invalid_utf8_is_transcoded() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let source = tempfile::TempDir::new().unwrap();
+
+        let path = source.path().join("NOTES.md");
+        std::fs::write(&path, b"# Caf\xe9 notes\n").unwrap();
+
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(path).unwrap();
+
+        let extensions = storage.list_extensions().unwrap();
+        assert_eq!(
+            extensions[0].context_content.as_deref(),
+            Some("# Caf\u{fffd} notes\n")
+        );
+    }
+
+    #[cfg(unix)]
+    #[test]
+    fn test_context_links_outside_the_directory_are_skipped() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let outside = tempfile::TempDir::new().unwrap();
+        let secret = outside.path().join("secrets.md");
+        std::fs::write(&secret, "API_KEY=hunter2\n").unwrap();
+
+        let source = tempfile::TempDir::new().unwrap();
+        let ext_dir = source.path().join("my-ext");
+        std::fs::create_dir(&ext_dir).unwrap();
+        std::os::unix::fs::symlink(&secret, ext_dir.join("GEMINI.md")).unwrap();
+        std::fs::write(ext_dir.join("guide.md"), "# Guide\n").unwrap();
+        // A link that stays inside the directory is fine
+        std::os::unix::fs::symlink(ext_dir.join("guide.md"), ext_dir.join("CONTEXT.md")).unwrap();
+
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(ext_dir.clone()).unwrap();
+        let extensions = storage.list_extensions().unwrap();
+        assert_eq!(extensions.len(), 1);
+        assert_eq!(extensions[0].context_file_name.as_deref(), Some("guide.md"));
+        assert_eq!(extensions[0].context_content.as_deref(), Some("# Guide\n"));
+
+        // The same goes for context files found beside a manifest
+        let with_manifest = source.path().join("with-manifest");
+        std::fs::create_dir(&with_manifest).unwrap();
+        write_manifest(&with_manifest, "Linked", "1.0.0");
+        std::os::unix::fs::symlink(&secret, with_manifest.join("GEMINI.md")).unwrap();
+        std::os::unix::fs::symlink(ext_dir.join("guide.md"), with_manifest.join("README.md"))
+            .unwrap();
+
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(with_manifest).unwrap();
+        let linked = storage
+            .list_extensions()
+            .unwrap()
+            .into_iter()
+            .find(|e| e.name == "Linked")
+            .unwrap();
+        assert_eq!(linked.context_file_name, None);
+        assert_eq!(linked.context_content, None);
+    }
+
+    #[test]
+    fn test_monorepo_import_needs_a_single_extension() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let repo = tempfile::TempDir::new().unwrap();
+        std::fs::write(repo.path().join("README.md"), "# Monorepo\n").unwrap();
+        for name in ["beta", "alpha"] {
+            let dir = repo.path().join("packages").join(name);
+            std::fs::create_dir_all(&dir).unwrap();
+            write_manifest(&dir, name, "1.0.0");
+        }
+        // Dependencies are never taken for the repo's own extensions
+        let vendored = repo.path().join("node_modules/dep");
+        std::fs::create_dir_all(&vendored).unwrap();
+        write_manifest(&vendored, "dep", "1.0.0");
+
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(repo.path().to_path_buf()).unwrap();
+        assert_eq!(
+            dialog.error_message(),
+            Some("Found several extensions, choose one of: packages/alpha, packages/beta")
+        );
+        assert!(storage.list_extensions().unwrap().is_empty());
+
+        // With one extension left it is imported rather than the README
+        std::fs::remove_dir_all(repo.path().join("packages/beta")).unwrap();
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(repo.path().to_path_buf()).unwrap();
+        let extensions = storage.list_extensions().unwrap();
+        assert_eq!(extensions.len(), 1);
+        assert_eq!(extensions[0].name, "alpha");
+    }
+
+    #[test]
+    fn test_import_dialog_accepts_exported_profiles() {
+        let (source, _source_dir) = create_temp_storage();
+        let profile = ProfileBuilder::new("Shared Setup").build();
+        source.save_profile(&profile).unwrap();
+        let shared = tempfile::TempDir::new().unwrap();
+        let file = shared.path().join("shared.json");
+        source.export_profile(&profile.id, &file).unwrap();
+
+        let (storage, _temp_dir) = create_temp_storage();
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(file).unwrap();
+
+        assert_eq!(dialog.error_message(), None);
+        assert!(storage.list_extensions().unwrap().is_empty());
+        assert_eq!(
+            storage.load_profile(&profile.id).unwrap().name,
+            "Shared Setup"
+        );
+    }
+
+    #[test]
+    fn test_import_dialog_asks_about_taken_profile_ids() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let existing = ProfileBuilder::new("Shared Setup")
+            .with_description("mine")
+            .build();
+        storage.save_profile(&existing).unwrap();
+
+        let shared = tempfile::TempDir::new().unwrap();
+        let file = shared.path().join("shared.json");
+        let mut theirs = existing.clone();
+        theirs.description = Some("theirs".to_string());
+        storage.save_profile(&theirs).unwrap();
+        storage.export_profile(&existing.id, &file).unwrap();
+        storage.save_profile(&existing).unwrap();
+
+        let description = |storage: &gemini_cli_manager::storage::Storage| {
+            storage.load_profile(&existing.id).unwrap().description
+        };
+        let mut dialog = ImportDialog::new(storage.clone());
+        let mut import_with = |code: KeyCode| {
+            dialog.reset();
+            dialog.import_path(file.clone()).unwrap();
+            // Nothing is written until the user decides
+            assert!(dialog.is_confirming_profile_conflict());
+            assert_eq!(description(&storage).as_deref(), Some("mine"));
+            dialog.handle_events(Some(create_key_event(code))).unwrap();
+        };
+
+        import_with(KeyCode::Esc);
+        import_with(KeyCode::Char('s'));
+        assert_eq!(storage.list_profiles().unwrap().len(), 1);
+
+        import_with(KeyCode::Enter);
+        let renamed = format!("{}-2", existing.id);
+        assert_eq!(
+            storage
+                .load_profile(&renamed)
+                .unwrap()
+                .description
+                .as_deref(),
+            Some("theirs")
+        );
+        assert_eq!(description(&storage).as_deref(), Some("mine"));
+
+        import_with(KeyCode::Char('o'));
+        assert_eq!(description(&storage).as_deref(), Some("theirs"));
+        assert_eq!(storage.list_profiles().unwrap().len(), 2);
+    }
+
+    #[test]
+    fn test_binary_context_file_is_not_imported() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let source = tempfile::TempDir::new().unwrap();
+
+        let path = source.path().join("logo.md");
+        std::fs::write(&path, b"\x89PNG\r\n\x1a\n\0\0\0\rIHDR").unwrap();
+
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(path).unwrap();
+
+        assert_eq!(
+            dialog.error_message(),
+            Some("logo.md is a binary file, not a text context file")
+        );
+        assert!(storage.list_extensions().unwrap().is_empty());
+    }
+
+    #[test]
+    fn test_min_gemini_version_manifest_field() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let source = tempfile::TempDir::new().unwrap();
+
+        let path = source.path().join("new-gemini.json");
+        let manifest = serde_json::json!({
+            "name": "New Gemini",
+            "version": "1.0.0",
+            "minGeminiVersion": "0.3.0",
+        });
+        std::fs::write(&path, manifest.to_string()).unwrap();
+
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(path).unwrap();
+
+        let extensions = storage.list_extensions().unwrap();
+        assert_eq!(extensions[0].min_gemini_version.as_deref(), Some("0.3.0"));
+    }
+
+    #[test]
+    fn test_import_reports_dropped_manifest_fields() {
+        use gemini_cli_manager::action::Action;
+
+        let (storage, _temp_dir) = create_temp_storage();
+        let source = tempfile::TempDir::new().unwrap();
+
+        let path = source.path().join("gemini-extension.json");
+        let manifest = serde_json::json!({
+            "name": "Search Tools",
+            "version": "1.2.0",
+            "description": "Find things",
+            "contextFileName": "SEARCH.md",
+            "minGeminiVersion": "0.3.0",
+            "excludeTools": ["run_shell_command"],
+            "homepage": "https://example.com",
+        });
+        std::fs::write(&path, manifest.to_string()).unwrap();
+
+        let (tx, mut rx) = tokio::sync::mpsc::unbounded_channel();
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.register_action_handler(tx).unwrap();
+        dialog.import_path(path).unwrap();
+
+        let extensions = storage.list_extensions().unwrap();
+        let imported = &extensions[0];
+        assert_eq!(imported.version, "1.2.0");
+        assert_eq!(imported.description.as_deref(), Some("Find things"));
+        assert_eq!(imported.context_file_name.as_deref(), Some("SEARCH.md"));
+        assert_eq!(imported.min_gemini_version.as_deref(), Some("0.3.0"));
+
+        assert!(matches!(
+            rx.try_recv(),
+            Ok(Action::Success(message))
+                if message == "Successfully imported: Search Tools (ignored unknown fields: excludeTools, homepage)"
+        ));
+    }
+
+    #[test]
+    fn test_enabled_by_default_manifest_flag() {
+        use gemini_cli_manager::components::profile_form::ProfileForm;
+
+        let (storage, _temp_dir) = create_temp_storage();
+        let source = tempfile::TempDir::new().unwrap();
+
+        let on_path = source.path().join("always-on.json");
+        let manifest = serde_json::json!({
+            "name": "Always On",
+            "version": "1.0.0",
+            "enabledByDefault": true,
+        });
+        std::fs::write(&on_path, manifest.to_string()).unwrap();
+
+        let off_path = write_manifest(source.path(), "Opt In", "1.0.0");
+
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(on_path).unwrap();
+        dialog.import_path(off_path).unwrap();
+
+        let extensions = storage.list_extensions().unwrap();
+        let always_on = extensions.iter().find(|e| e.name == "Always On").unwrap();
+        let opt_in = extensions.iter().find(|e| e.name == "Opt In").unwrap();
+        assert!(always_on.enabled_by_default);
+        assert!(!opt_in.enabled_by_default);
+
+        // New profiles start with it selected
+        let form = ProfileForm::new(storage);
+        assert_eq!(form.selected_extensions(), [always_on.id.clone()]);
+    }
+
+    #[test]
+    fn test_suggested_profile_defaults_apply_on_confirmation() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let mut profile = ProfileBuilder::new("Everyday").as_default().build();
+        profile
+            .environment_variables
+            .insert("SEARCH_REGION".to_string(), "eu".to_string());
+        storage.save_profile(&profile).unwrap();
+
+        let source = tempfile::TempDir::new().unwrap();
+        let manifest = serde_json::json!({
+            "name": "Search Tools",
+            "version": "1.0.0",
+            "profileDefaults": {
+                "environmentVariables": {
+                    "SEARCH_API_URL": "https://search.example.com",
+                    "SEARCH_REGION": "us",
+                },
+            },
+        });
+        let write = |name: &str| {
+            let path = source.path().join(name);
+            std::fs::write(&path, manifest.to_string()).unwrap();
+            path
+        };
+
+        // Declining keeps the extension but leaves the profile alone
+        let mut dialog = ImportDialog::new(storage.clone());
+        dialog.import_path(write("first.json")).unwrap();
+        assert!(dialog.is_confirming_defaults());
+        assert_eq!(storage.list_extensions().unwrap().len(), 1);
+        dialog
+            .handle_events(Some(create_key_event(KeyCode::Char('n'))))
+            .unwrap();
+        assert!(!dialog.is_confirming_defaults());
+        let unchanged = storage.load_profile(&profile.id).unwrap();
+        assert!(unchanged.extension_ids.is_empty());
+        assert_eq!(unchanged.environment_variables.len(), 1);
+
+        // Importing again updates the extension, then offers the settings once more
+        dialog.import_path(write("second.json")).unwrap();
+        dialog
+            .handle_events(Some(create_key_event(KeyCode::Char('y'))))
+            .unwrap();
+        assert!(dialog.is_confirming_defaults());
+
+        let mut terminal = setup_test_terminal(100, 30).unwrap();
+        terminal
+            .draw(|f| {
+                dialog.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "SEARCH_API_URL=https://search.example.com");
+        assert_buffer_not_contains(&terminal, "SEARCH_REGION=us");
+
+        dialog
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+
+        let extension = storage.list_extensions().unwrap().remove(0);
+        let updated = storage.load_profile(&profile.id).unwrap();
+        assert_eq!(updated.extension_ids, [extension.id]);
+        assert_eq!(
+            updated
+                .environment_variables
+                .get("SEARCH_API_URL")
+                .map(String::as_str),
+            Some("https://search.example.com")
+        );
+        // A value the user already set is kept
+        assert_eq!(
+            updated
+                .environment_variables
+                .get("SEARCH_REGION")
+                .map(String::as_str),
+            Some("eu")
+        );
+    }
+}
@@ -0,0 +1,93 @@
+#[cfg(test)]
+mod tests {
+    use crate::test_utils::*;
+    use crossterm::event::{KeyCode, KeyEvent, KeyEventKind, KeyModifiers};
+    use gemini_cli_manager::components::Component;
+    use gemini_cli_manager::components::list_scroll::ListScroll;
+    use gemini_cli_manager::components::profile_list::ProfileList;
+    use gemini_cli_manager::icons::Icon;
+
+    #[test]
+    fn test_follow_keeps_selection_in_view() {
+        let heights = [3; 10];
+        let mut scroll = ListScroll::default();
+
+        // Everything up to the fourth card fits in 12 rows
+        assert_eq!(scroll.follow(&heights, 3, 12), 0);
+        assert_eq!(scroll.hidden(&heights, 12), (0, 6));
+
+        // Moving past the bottom scrolls just far enough
+        assert_eq!(scroll.follow(&heights, 5, 12), 2);
+        assert_eq!(scroll.hidden(&heights, 12), (2, 4));
+
+        // Moving back up inside the view doesn't scroll
+        assert_eq!(scroll.follow(&heights, 3, 12), 2);
+
+        // Moving above the view brings that card to the top
+        assert_eq!(scroll.follow(&heights, 0, 12), 0);
+
+        // A selection past a shrunken list lands on the last card
+        assert_eq!(scroll.follow(&heights[..2], 9, 12), 0);
+        assert_eq!(scroll.follow(&[], 0, 12), 0);
+    }
+
+    #[test]
+    fn test_hint_counts_cards_out_of_view() {
+        let (up, down) = (Icon::MoreAbove, Icon::MoreBelow);
+        let heights = [4, 2, 2, 4, 1];
+        let mut scroll = ListScroll::default();
+
+        assert_eq!(scroll.hint(&heights, 20), None);
+        assert_eq!(
+            scroll.hint(&heights, 8).as_deref(),
+            Some(format!("{down} 2 more").as_str())
+        );
+
+        scroll.follow(&heights, 4, 8);
+        assert_eq!(scroll.offset(), 2);
+        assert_eq!(
+            scroll.hint(&heights, 8).as_deref(),
+            Some(format!("{up} 2 more").as_str())
+        );
+
+        scroll.follow(&heights, 3, 4);
+        assert_eq!(
+            scroll.hint(&heights, 4).as_deref(),
+            Some(format!("{up} 3 more · {down} 1 more").as_str())
+        );
+
+        // A card taller than the view is still shown
+        assert_eq!(ListScroll::default().hidden(&[10], 3), (0, 0));
+    }
+
+    #[test]
+    fn test_long_profile_list_scrolls_with_cursor() {
+        let storage = create_test_storage();
+        for i in 0..50 {
+            let profile = ProfileBuilder::new(&format!("Profile {i:02}")).build();
+            storage.save_profile(&profile).unwrap();
+        }
+        let mut list = ProfileList::with_storage(storage);
+        let mut terminal = setup_test_terminal(80, 24).unwrap();
+
+        terminal.draw(|f| list.draw(f, f.area()).unwrap()).unwrap();
+        assert_buffer_contains(&terminal, "Profile 00");
+        assert_buffer_not_contains(&terminal, "Profile 49");
+        assert_buffer_contains(&terminal, "more");
+
+        for _ in 0..49 {
+            list.handle_events(Some(gemini_cli_manager::tui::Event::Key(KeyEvent {
+                code: KeyCode::Down,
+                modifiers: KeyModifiers::NONE,
+                kind: KeyEventKind::Press,
+                state: crossterm::event::KeyEventState::NONE,
+            })))
+            .unwrap();
+        }
+        terminal.draw(|f| list.draw(f, f.area()).unwrap()).unwrap();
+        // The cursor's card is on screen and the earlier ones are counted
+        assert_buffer_contains(&terminal, "Profile 49");
+        assert_buffer_not_contains(&terminal, "Profile 00");
+        assert_buffer_contains(&terminal, "more");
+    }
+}
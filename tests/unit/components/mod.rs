@@ -1,11 +1,16 @@
+pub mod badge_test;
 pub mod confirm_dialog_test;
 pub mod extension_detail_additional_test;
 pub mod extension_detail_test;
 pub mod extension_form_test;
 pub mod extension_list_test;
+pub mod icon_picker_test;
+pub mod import_dialog_test;
+pub mod list_scroll_test;
 pub mod profile_detail_additional_test;
 pub mod profile_detail_test;
 pub mod profile_form_test;
 pub mod profile_list_test;
+pub mod settings_view_test;
 /// Unit tests for UI components
 pub mod tab_bar_test;
@@ -353,6 +353,32 @@ mod tests {
         assert_buffer_contains(&terminal, "NORMAL_VAR = normal-value"); // Not masked
     }
 
+    #[test]
+    fn test_environment_masks_values_that_reference_secrets() {
+        let storage = create_test_storage();
+        let mut profile = ProfileBuilder::new("Referencing Profile").build();
+        profile
+            .environment_variables
+            .insert("MY_TOKEN".to_string(), "ghp_1234567890abcdef".to_string());
+        profile
+            .environment_variables
+            .insert("GH".to_string(), "$MY_TOKEN".to_string());
+        storage.save_profile(&profile).unwrap();
+
+        let mut detail = ProfileDetail::new(storage, profile.id);
+        let mut terminal = setup_test_terminal(80, 30).unwrap();
+        terminal
+            .draw(|f| {
+                detail.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+
+        // The expanded copy is masked like the secret it came from
+        assert_buffer_contains(&terminal, "GH = ghp_...cdef");
+        assert_buffer_contains(&terminal, "MY_TOKEN = ghp_...cdef");
+        assert_buffer_not_contains(&terminal, "1234567890");
+    }
+
     #[test]
     fn test_profile_with_extensions_with_mcp_servers() {
         let storage = create_test_storage();
@@ -633,4 +659,184 @@ mod tests {
         assert_buffer_contains(&terminal, "• 2 environment variables");
         assert_buffer_contains(&terminal, "• 3 MCP servers total"); // 1 + 2 servers
     }
+
+    #[test]
+    fn test_copy_launch_command() {
+        use gemini_cli_manager::launcher::Launcher;
+        use gemini_cli_manager::utils::clipboard::MemoryClipboard;
+
+        let storage = create_test_storage();
+        let mut profile = ProfileBuilder::new("Copy Profile").build();
+        profile
+            .environment_variables
+            .insert("API_URL".to_string(), "http://localhost".to_string());
+        storage.save_profile(&profile).unwrap();
+
+        let expected = Launcher::with_storage(storage.clone())
+            .command_line(&profile)
+            .unwrap();
+
+        let clipboard = MemoryClipboard::new();
+        let mut detail = ProfileDetail::new(storage, profile.id.clone());
+        detail.set_clipboard(Box::new(clipboard.clone()));
+
+        let action = detail
+            .handle_events(Some(create_key_event(KeyCode::Char('y'))))
+            .unwrap();
+
+        assert!(matches!(action, Some(Action::Success(_))));
+        assert_eq!(clipboard.contents(), Some(expected));
+    }
+
+    #[test]
+    fn test_copy_launch_command_without_profile() {
+        let mut detail = ProfileDetail::default();
+
+        let action = detail
+            .handle_events(Some(create_key_event(KeyCode::Char('y'))))
+            .unwrap();
+
+        assert_eq!(action, None);
+    }
+
+    #[test]
+    fn test_launch_review_skips_extension_once() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let web = ExtensionBuilder::new("Web Tools").build();
+        let db = ExtensionBuilder::new("Db Tools").build();
+        storage.save_extension(&web).unwrap();
+        storage.save_extension(&db).unwrap();
+        let profile = ProfileBuilder::new("Work")
+            .with_extensions(vec![&web.id, &db.id])
+            .build();
+        storage.save_profile(&profile).unwrap();
+
+        let mut detail = ProfileDetail::new(storage.clone(), profile.id.clone());
+        for code in [KeyCode::Char('r'), KeyCode::Down, KeyCode::Char(' ')] {
+            detail.handle_events(Some(create_key_event(code))).unwrap();
+        }
+
+        let mut terminal = setup_test_terminal(80, 20).unwrap();
+        terminal
+            .draw(|f| detail.draw(f, f.area()).unwrap())
+            .unwrap();
+        assert_buffer_contains(&terminal, "[x] Web Tools v1.0.0");
+        assert_buffer_contains(&terminal, "[ ] Db Tools v1.0.0");
+
+        let action = detail
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert_eq!(
+            action,
+            Some(Action::LaunchWithout(
+                profile.id.clone(),
+                vec![db.id.clone()]
+            ))
+        );
+        // Only this launch is affected
+        assert_eq!(
+            storage.load_profile(&profile.id).unwrap().extension_ids,
+            profile.extension_ids
+        );
+
+        // With everything checked it is an ordinary launch
+        detail
+            .handle_events(Some(create_key_event(KeyCode::Char('r'))))
+            .unwrap();
+        let action = detail
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert_eq!(action, Some(Action::LaunchWithProfile(profile.id.clone())));
+    }
+
+    #[test]
+    fn test_detail_uses_inherited_settings() {
+        use gemini_cli_manager::launcher::Launcher;
+        use gemini_cli_manager::utils::clipboard::MemoryClipboard;
+
+        let (storage, _temp_dir) = create_temp_storage();
+        let web = ExtensionBuilder::new("Web Tools").build();
+        storage.save_extension(&web).unwrap();
+        let mut base = ProfileBuilder::new("Base")
+            .with_extensions(vec![&web.id])
+            .build();
+        base.environment_variables
+            .insert("LOG_LEVEL".to_string(), "debug".to_string());
+        base.environment_variables
+            .insert("REGION".to_string(), "eu".to_string());
+        storage.save_profile(&base).unwrap();
+        let mut child = ProfileBuilder::new("Child").build();
+        child.inherits = Some(base.id.clone());
+        child
+            .environment_variables
+            .insert("REGION".to_string(), "us".to_string());
+        storage.save_profile(&child).unwrap();
+
+        let clipboard = MemoryClipboard::new();
+        let mut detail = ProfileDetail::new(storage.clone(), child.id.clone());
+        detail.set_clipboard(Box::new(clipboard.clone()));
+
+        // The environment shows what the child inherits
+        let mut terminal = setup_test_terminal(100, 60).unwrap();
+        terminal
+            .draw(|f| detail.draw(f, f.area()).unwrap())
+            .unwrap();
+        assert_buffer_contains(&terminal, "LOG_LEVEL = debug");
+        assert_buffer_contains(&terminal, "REGION = us");
+        assert_buffer_contains(&terminal, "Overrides parent profile: REGION");
+
+        // The copied command is the one a launch would run
+        detail
+            .handle_events(Some(create_key_event(KeyCode::Char('y'))))
+            .unwrap();
+        let expected = Launcher::with_storage(storage.clone())
+            .command_line(&storage.resolve_profile(&child.id).unwrap())
+            .unwrap();
+        assert_eq!(clipboard.contents(), Some(expected));
+
+        // The launch review lists the inherited extension
+        detail
+            .handle_events(Some(create_key_event(KeyCode::Char('r'))))
+            .unwrap();
+        terminal
+            .draw(|f| detail.draw(f, f.area()).unwrap())
+            .unwrap();
+        assert_buffer_contains(&terminal, "[x] Web Tools v1.0.0");
+    }
+
+    #[test]
+    fn test_launch_plan_toggles_with_p() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let web = ExtensionBuilder::new("Web Tools").build();
+        storage.save_extension(&web).unwrap();
+        let profile = ProfileBuilder::new("Work")
+            .with_extensions(vec![&web.id])
+            .build();
+        storage.save_profile(&profile).unwrap();
+
+        let mut detail = ProfileDetail::new(storage, profile.id.clone());
+        let action = detail
+            .handle_events(Some(create_key_event(KeyCode::Char('p'))))
+            .unwrap();
+        assert_eq!(action, Some(Action::Render));
+
+        let mut terminal = setup_test_terminal(100, 30).unwrap();
+        terminal
+            .draw(|f| detail.draw(f, f.area()).unwrap())
+            .unwrap();
+        assert_buffer_contains(&terminal, "Launch plan");
+        assert_buffer_contains(&terminal, "Extensions to Install");
+        assert_buffer_contains(&terminal, &format!("{} →", web.id));
+
+        // Back closes the plan rather than leaving the view
+        let action = detail
+            .handle_events(Some(create_key_event(KeyCode::Esc)))
+            .unwrap();
+        assert_eq!(action, Some(Action::Render));
+        terminal
+            .draw(|f| detail.draw(f, f.area()).unwrap())
+            .unwrap();
+        assert_buffer_not_contains(&terminal, "Launch plan");
+        assert_buffer_contains(&terminal, "ID: work");
+    }
 }
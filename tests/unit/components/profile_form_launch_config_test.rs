@@ -30,6 +30,7 @@ fn test_profile_form_loads_existing_launch_config() {
         id: "test-profile".to_string(),
         name: "Test Profile".to_string(),
         description: Some("Test description".to_string()),
+        inherits: None,
         extension_ids: vec![],
         environment_variables: HashMap::new(),
         working_directory: None,
@@ -312,6 +312,78 @@ mod tests {
     //     assert!(!form.is_default());
     // }
 
+    #[test]
+    fn test_pick_icon_and_save() {
+        let storage = create_test_storage();
+        let mut form = ProfileForm::new(storage.clone());
+        for ch in "Rusty".chars() {
+            form.handle_events(Some(create_key_event(KeyCode::Char(ch))))
+                .unwrap();
+        }
+
+        let ctrl = |c| {
+            gemini_cli_manager::tui::Event::Key(KeyEvent {
+                code: KeyCode::Char(c),
+                modifiers: crossterm::event::KeyModifiers::CONTROL,
+                kind: KeyEventKind::Press,
+                state: crossterm::event::KeyEventState::NONE,
+            })
+        };
+
+        // The picker takes the typing until something is chosen
+        form.handle_events(Some(ctrl('p'))).unwrap();
+        for ch in "crab".chars() {
+            form.handle_events(Some(create_key_event(KeyCode::Char(ch))))
+                .unwrap();
+        }
+        form.handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+
+        let mut terminal = setup_test_terminal(80, 40).unwrap();
+        terminal
+            .draw(|f| {
+                form.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "Rusty");
+        assert_buffer_not_contains(&terminal, "Rustycrab");
+
+        form.handle_events(Some(ctrl('s'))).unwrap();
+        let saved = storage.list_profiles().unwrap();
+        assert_eq!(saved.len(), 1);
+        assert_eq!(saved[0].metadata.icon.as_deref(), Some("🦀"));
+        assert_eq!(saved[0].display_name(), "🦀 Rusty");
+    }
+
+    #[test]
+    fn test_reserved_name_is_not_saved() {
+        use gemini_cli_manager::action::Action;
+
+        let storage = create_test_storage();
+        let mut form = ProfileForm::new(storage.clone());
+        for ch in "Default".chars() {
+            form.handle_events(Some(create_key_event(KeyCode::Char(ch))))
+                .unwrap();
+        }
+
+        let action = form
+            .handle_events(Some(gemini_cli_manager::tui::Event::Key(KeyEvent {
+                code: KeyCode::Char('s'),
+                modifiers: crossterm::event::KeyModifiers::CONTROL,
+                kind: KeyEventKind::Press,
+                state: crossterm::event::KeyEventState::NONE,
+            })))
+            .unwrap();
+
+        assert_eq!(
+            action,
+            Some(Action::Error(
+                "Failed to save profile: 'default' is reserved, choose another name".to_string()
+            ))
+        );
+        assert!(storage.list_profiles().unwrap().is_empty());
+    }
+
     #[test]
     fn test_environment_variables() {
         let mut form = create_test_form();
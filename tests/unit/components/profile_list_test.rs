@@ -80,6 +80,57 @@ mod tests {
         assert_buffer_contains(&terminal, "Press 'n' to create your first profile");
     }
 
+    #[test]
+    fn test_empty_profile_list_ignores_navigation_and_select() {
+        use gemini_cli_manager::components::settings_view::UserSettings;
+        use std::sync::{Arc, RwLock};
+
+        let storage = create_test_storage();
+        let mut list = ProfileList::with_storage(storage);
+        list.register_settings_handler(Arc::new(RwLock::new(UserSettings::default())))
+            .unwrap();
+
+        for code in [
+            KeyCode::Down,
+            KeyCode::Up,
+            KeyCode::Char('j'),
+            KeyCode::Char('k'),
+        ] {
+            list.handle_events(Some(create_key_event(code))).unwrap();
+            assert_eq!(list.selected_index(), 0);
+        }
+
+        // Nothing to open, edit, launch or delete
+        for code in [
+            KeyCode::Enter,
+            KeyCode::Char('e'),
+            KeyCode::Char('l'),
+            KeyCode::Char('d'),
+            KeyCode::Char('x'),
+            KeyCode::Char('o'),
+        ] {
+            assert_eq!(
+                list.handle_events(Some(create_key_event(code))).unwrap(),
+                None
+            );
+        }
+
+        // Creating one is still offered
+        assert_eq!(
+            list.handle_events(Some(create_key_event(KeyCode::Char('n'))))
+                .unwrap(),
+            Some(gemini_cli_manager::action::Action::CreateProfile)
+        );
+
+        let mut terminal = setup_test_terminal(60, 20).unwrap();
+        terminal
+            .draw(|f| {
+                list.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "Press 'n' to create your first profile");
+    }
+
     #[test]
     fn test_profile_descriptions() {
         let mut list = create_test_profile_list();
@@ -137,6 +188,40 @@ mod tests {
         assert_eq!(list.selected_index(), 1);
     }
 
+    #[test]
+    fn test_launch_targets_highlighted_profile() {
+        use gemini_cli_manager::action::Action;
+        use gemini_cli_manager::components::settings_view::UserSettings;
+        use std::sync::{Arc, RwLock};
+
+        let mut list = create_test_profile_list();
+        list.register_settings_handler(Arc::new(RwLock::new(UserSettings::default())))
+            .unwrap();
+
+        // Whatever is under the cursor is launched, not the default profile
+        let mut launched = Vec::new();
+        for _ in 0..3 {
+            let Some(Action::ViewProfileDetails(highlighted)) = list
+                .handle_events(Some(create_key_event(KeyCode::Enter)))
+                .unwrap()
+            else {
+                panic!("expected a highlighted profile");
+            };
+            let action = list
+                .handle_events(Some(create_key_event(KeyCode::Char('l'))))
+                .unwrap();
+            assert_eq!(action, Some(Action::LaunchWithProfile(highlighted.clone())));
+            launched.push(highlighted);
+
+            list.handle_events(Some(create_key_event(KeyCode::Down)))
+                .unwrap();
+        }
+
+        launched.sort();
+        launched.dedup();
+        assert_eq!(launched.len(), 3);
+    }
+
     #[test]
     fn test_default_profile_selection() {
         let mut list = create_test_profile_list();
@@ -161,6 +246,32 @@ mod tests {
         assert!(content.contains("Development") && content.contains("(default)"));
     }
 
+    #[test]
+    fn test_set_default_keeps_changes_made_elsewhere() {
+        let storage = create_test_storage();
+        let work = ProfileBuilder::new("Work").as_default().build();
+        let home = ProfileBuilder::new("Home").build();
+        storage.save_profile(&work).unwrap();
+        storage.save_profile(&home).unwrap();
+        let mut list = ProfileList::with_storage(storage.clone());
+
+        // Another instance edits a profile after the list was loaded
+        let mut edited = storage.load_profile(&work.id).unwrap();
+        edited.description = Some("Edited elsewhere".to_string());
+        storage.save_profile(&edited).unwrap();
+
+        // Profiles are listed by file name, so Home is selected first
+        assert_eq!(
+            list.handle_events(Some(create_key_event(KeyCode::Char('x'))))
+                .unwrap(),
+            Some(gemini_cli_manager::action::Action::RefreshProfiles)
+        );
+        assert!(storage.load_profile(&home.id).unwrap().metadata.is_default);
+        let work = storage.load_profile(&work.id).unwrap();
+        assert!(!work.metadata.is_default);
+        assert_eq!(work.description.as_deref(), Some("Edited elsewhere"));
+    }
+
     #[test]
     fn test_profile_search() {
         let mut list = create_test_profile_list();
@@ -259,4 +370,66 @@ mod tests {
             assert!(result.is_ok(), "Failed to render at {width}x{height}");
         }
     }
+
+    #[test]
+    fn test_compact_toggle_hides_card_metadata() {
+        let mut list = create_test_profile_list();
+        assert!(!list.is_compact());
+
+        list.handle_events(Some(create_key_event(KeyCode::Char('m'))))
+            .unwrap();
+        assert!(list.is_compact());
+
+        let mut terminal = setup_test_terminal(80, 20).unwrap();
+        terminal
+            .draw(|f| {
+                list.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "Development");
+        assert_buffer_contains(&terminal, "Development environment");
+        assert_buffer_not_contains(&terminal, "2 extensions");
+
+        list.handle_events(Some(create_key_event(KeyCode::Char('m'))))
+            .unwrap();
+        assert!(!list.is_compact());
+    }
+
+    #[test]
+    fn test_capture_defaults_into_highlighted_profile() {
+        use gemini_cli_manager::action::Action;
+
+        let storage = create_test_storage();
+        let mut enabled = ExtensionBuilder::new("Enabled").build();
+        enabled.enabled_by_default = true;
+        storage.save_extension(&enabled).unwrap();
+        storage
+            .save_extension(&ExtensionBuilder::new("Disabled").build())
+            .unwrap();
+        let profile = ProfileBuilder::new("Work")
+            .with_extensions(vec!["disabled"])
+            .build();
+        storage.save_profile(&profile).unwrap();
+
+        let mut list = ProfileList::with_storage(storage.clone());
+        let action = list
+            .handle_events(Some(create_key_event(KeyCode::Char('c'))))
+            .unwrap();
+
+        // Nothing changes until the user confirms
+        assert_eq!(action, Some(Action::CaptureDefaults("work".to_string())));
+        assert_eq!(
+            storage.load_profile("work").unwrap().extension_ids,
+            vec!["disabled"]
+        );
+
+        let action = list
+            .update(Action::CaptureDefaultsConfirmed("work".to_string()))
+            .unwrap();
+        assert!(matches!(action, Some(Action::Success(_))));
+        assert_eq!(
+            storage.load_profile("work").unwrap().extension_ids,
+            vec!["enabled"]
+        );
+    }
 }
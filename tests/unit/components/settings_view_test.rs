@@ -0,0 +1,278 @@
+#[cfg(test)]
+mod tests {
+    use crate::test_utils::*;
+    use crossterm::event::{KeyCode, KeyEvent, KeyEventKind};
+    use gemini_cli_manager::components::Component;
+    use gemini_cli_manager::components::settings_view::{Settings, SettingsRow};
+
+    fn create_key_event(code: KeyCode) -> gemini_cli_manager::tui::Event {
+        use crossterm::event::KeyModifiers;
+        gemini_cli_manager::tui::Event::Key(KeyEvent {
+            code,
+            modifiers: KeyModifiers::NONE,
+            kind: KeyEventKind::Press,
+            state: crossterm::event::KeyEventState::NONE,
+        })
+    }
+
+    fn search(settings: &mut Settings, query: &str) {
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Char('/'))))
+            .unwrap();
+        for ch in query.chars() {
+            settings
+                .handle_events(Some(create_key_event(KeyCode::Char(ch))))
+                .unwrap();
+        }
+    }
+
+    #[test]
+    fn test_search_matches_rows_across_sections() {
+        let mut settings = Settings::default();
+        search(&mut settings, "te");
+
+        assert!(settings.is_search_mode());
+        // "Latte", the "delete" and "create" keybindings, and the extensions directory
+        assert_eq!(
+            settings.matching_rows(),
+            vec![
+                SettingsRow::Theme(3),
+                SettingsRow::Keybinding(7),
+                SettingsRow::Keybinding(8),
+                SettingsRow::ExtensionsDir,
+            ]
+        );
+
+        let mut terminal = setup_test_terminal(100, 30).unwrap();
+        terminal
+            .draw(|f| {
+                settings.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+        assert_buffer_contains(&terminal, "Results (4)");
+        assert_buffer_contains(&terminal, "Latte (Light)");
+        assert_buffer_contains(&terminal, "Gemini extensions directory");
+    }
+
+    #[test]
+    fn test_search_navigation_moves_between_sections() {
+        let mut settings = Settings::default();
+        assert_eq!(settings.current_row(), SettingsRow::Theme(0));
+
+        // Down twice lands on a keybinding row
+        search(&mut settings, "te");
+        for _ in 0..2 {
+            settings
+                .handle_events(Some(create_key_event(KeyCode::Down)))
+                .unwrap();
+        }
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert!(!settings.is_search_mode());
+        assert_eq!(settings.current_row(), SettingsRow::Keybinding(8));
+
+        // Up from the first result wraps to the last, in the paths section
+        search(&mut settings, "te");
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Up)))
+            .unwrap();
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert_eq!(settings.current_row(), SettingsRow::ExtensionsDir);
+
+        // And back to a theme
+        search(&mut settings, "latte");
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert_eq!(settings.current_row(), SettingsRow::Theme(3));
+    }
+
+    #[test]
+    fn test_search_by_section_name_and_cancel() {
+        let mut settings = Settings::default();
+        search(&mut settings, "paths");
+        assert_eq!(
+            settings.matching_rows(),
+            vec![SettingsRow::GeminiCliPath, SettingsRow::ExtensionsDir]
+        );
+
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Esc)))
+            .unwrap();
+        assert!(!settings.is_search_mode());
+        // Cancelling leaves the cursor where it was
+        assert_eq!(settings.current_row(), SettingsRow::Theme(0));
+    }
+
+    #[test]
+    fn test_compact_cards_toggle_updates_shared_settings() {
+        use gemini_cli_manager::action::Action;
+        use gemini_cli_manager::components::settings_view::UserSettings;
+        use std::sync::{Arc, RwLock};
+
+        let shared = Arc::new(RwLock::new(UserSettings::default()));
+        let mut settings = Settings::default();
+        settings.register_settings_handler(shared.clone()).unwrap();
+        assert!(!settings.compact_cards());
+
+        // Only the Appearance content pane reacts to 'c'
+        let action = settings
+            .handle_events(Some(create_key_event(KeyCode::Char('c'))))
+            .unwrap();
+        assert_eq!(action, None);
+
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Right)))
+            .unwrap();
+        let action = settings
+            .handle_events(Some(create_key_event(KeyCode::Char('c'))))
+            .unwrap();
+        assert_eq!(action, Some(Action::SetCompactCards(true)));
+        assert!(shared.read().unwrap().compact_cards);
+
+        let action = settings
+            .handle_events(Some(create_key_event(KeyCode::Char('c'))))
+            .unwrap();
+        assert_eq!(action, Some(Action::SetCompactCards(false)));
+        assert!(!shared.read().unwrap().compact_cards);
+    }
+
+    #[test]
+    fn test_gemini_cli_path_is_validated_before_saving() {
+        use gemini_cli_manager::components::settings_view::UserSettings;
+        use std::sync::{Arc, RwLock};
+
+        let shared = Arc::new(RwLock::new(UserSettings::default()));
+        let mut settings = Settings::default();
+        settings.register_settings_handler(shared.clone()).unwrap();
+
+        // The CLI path is the first row of the paths section
+        search(&mut settings, "cli path");
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert_eq!(settings.current_row(), SettingsRow::GeminiCliPath);
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Down)))
+            .unwrap();
+        assert_eq!(settings.current_row(), SettingsRow::ExtensionsDir);
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Up)))
+            .unwrap();
+
+        let type_path = |settings: &mut Settings, path: &str| {
+            settings
+                .handle_events(Some(create_key_event(KeyCode::Enter)))
+                .unwrap();
+            for ch in path.chars() {
+                settings
+                    .handle_events(Some(create_key_event(KeyCode::Char(ch))))
+                    .unwrap();
+            }
+            settings
+                .handle_events(Some(create_key_event(KeyCode::Enter)))
+                .unwrap();
+        };
+
+        // A path with nothing there isn't saved
+        let dir = tempfile::TempDir::new().unwrap();
+        let cli = dir.path().join("gemini");
+        type_path(&mut settings, &cli.to_string_lossy());
+        assert_eq!(shared.read().unwrap().gemini_cli_path, None);
+
+        // Once the file exists, the same path is accepted
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Esc)))
+            .unwrap();
+        std::fs::write(&cli, "#!/bin/sh\n").unwrap();
+        type_path(&mut settings, &cli.to_string_lossy());
+        assert_eq!(
+            shared.read().unwrap().gemini_cli_path.as_deref(),
+            Some(cli.to_string_lossy().as_ref())
+        );
+    }
+
+    #[test]
+    fn test_extensions_dir_matches_launch_plan() {
+        use gemini_cli_manager::components::settings_view::UserSettings;
+        use gemini_cli_manager::launcher::Launcher;
+        use std::sync::{Arc, RwLock};
+
+        let (storage, _temp) = create_temp_storage();
+        let ext = ExtensionBuilder::new("Planned").build();
+        storage.save_extension(&ext).unwrap();
+        let profile = ProfileBuilder::new("Planned")
+            .with_extensions(vec![&ext.id])
+            .build();
+
+        let shared = Arc::new(RwLock::new(UserSettings::default()));
+        let mut settings = Settings::default();
+        settings.register_settings_handler(shared.clone()).unwrap();
+
+        let planned_dir = |shared: &Arc<RwLock<UserSettings>>| {
+            let configured = shared.read().unwrap().gemini_extensions_dir.clone();
+            let plan = Launcher::with_storage(storage.clone())
+                .with_extensions_dir(configured.as_deref())
+                .plan(&profile)
+                .unwrap();
+            plan.installs[0].1.parent().unwrap().to_path_buf()
+        };
+        assert_eq!(settings.extensions_dir(), planned_dir(&shared));
+
+        // Saving the untouched editor keeps the default
+        search(&mut settings, "extensions directory");
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert_eq!(settings.current_row(), SettingsRow::ExtensionsDir);
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        settings
+            .handle_events(Some(create_key_event(KeyCode::Enter)))
+            .unwrap();
+        assert_eq!(shared.read().unwrap().gemini_extensions_dir, None);
+
+        // A configured directory is shown and used alike
+        let dir = tempfile::TempDir::new().unwrap();
+        shared.write().unwrap().gemini_extensions_dir =
+            Some(dir.path().to_string_lossy().to_string());
+        assert_eq!(settings.extensions_dir(), dir.path());
+        assert_eq!(settings.extensions_dir(), planned_dir(&shared));
+    }
+
+    #[test]
+    fn test_keybindings_markdown_table() {
+        use gemini_cli_manager::components::settings_view::KeybindingConfig;
+
+        let mut keybindings = KeybindingConfig::default();
+        keybindings.actions.search = vec!["|".to_string(), "Ctrl+f".to_string()];
+
+        let markdown = keybindings.to_markdown();
+        let lines: Vec<&str> = markdown.lines().collect();
+
+        assert_eq!(lines[0], "| Action | Keys |");
+        assert_eq!(lines[1], "| --- | --- |");
+        assert_eq!(lines[2], "| up | `Up`, `k` |");
+        // One row per customizable action, reflecting overrides
+        assert_eq!(lines.len(), 2 + 13);
+        assert_eq!(lines.last(), Some(&"| search | `\\|`, `Ctrl+f` |"));
+    }
+
+    #[test]
+    fn test_fixed_keys_markdown_table() {
+        use gemini_cli_manager::components::settings_view::KeybindingConfig;
+
+        let markdown = KeybindingConfig::default().fixed_keys_markdown();
+        let lines: Vec<&str> = markdown.lines().collect();
+
+        assert_eq!(lines[0], "| Key | Action |");
+        assert_eq!(lines[2], "| `s` | Sort extensions |");
+        assert!(lines.contains(&"| `Space` | Toggle, enable or disable |"));
+        assert_eq!(lines.last(), Some(&"| `Tab` | Next tab or field |"));
+        assert_eq!(lines.len(), 2 + 13);
+    }
+}
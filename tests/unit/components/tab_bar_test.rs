@@ -62,4 +62,65 @@ mod tests {
 
         assert!(result.is_ok());
     }
+
+    #[test]
+    fn test_tab_bar_custom_segment() {
+        let mut terminal = setup_test_terminal(100, 3).unwrap();
+        let mut tab_bar = TabBar::new();
+        tab_bar.add_segment(|| Ok("branch: main".to_string()));
+
+        terminal
+            .draw(|f| {
+                tab_bar.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+
+        assert_buffer_contains(&terminal, "Extensions");
+        assert_buffer_contains(&terminal, "branch: main");
+    }
+
+    #[test]
+    fn test_tab_bar_failing_segment_is_skipped() {
+        let mut terminal = setup_test_terminal(100, 3).unwrap();
+        let mut tab_bar = TabBar::new();
+        tab_bar.add_segment(|| Err(color_eyre::eyre::eyre!("not a git repo")));
+        tab_bar.add_segment(|| Ok("ok-segment".to_string()));
+
+        terminal
+            .draw(|f| {
+                tab_bar.draw(f, f.area()).unwrap();
+            })
+            .unwrap();
+
+        assert_buffer_not_contains(&terminal, "not a git repo");
+        assert_buffer_contains(&terminal, "ok-segment");
+    }
+
+    #[test]
+    fn test_tab_bar_slow_segment_is_disabled() {
+        use std::cell::Cell;
+        use std::rc::Rc;
+        use std::time::Duration;
+
+        let mut terminal = setup_test_terminal(100, 3).unwrap();
+        let mut tab_bar = TabBar::new();
+        let calls = Rc::new(Cell::new(0));
+        let counter = calls.clone();
+        tab_bar.add_segment(move || {
+            counter.set(counter.get() + 1);
+            std::thread::sleep(Duration::from_millis(100));
+            Ok("slow".to_string())
+        });
+
+        for _ in 0..2 {
+            terminal
+                .draw(|f| {
+                    tab_bar.draw(f, f.area()).unwrap();
+                })
+                .unwrap();
+        }
+
+        // The segment ran once, blew its budget, and was not called again
+        assert_eq!(calls.get(), 1);
+    }
 }
@@ -0,0 +1,58 @@
+#[cfg(test)]
+mod tests {
+    use gemini_cli_manager::utils::fuzzy::fuzzy_match;
+
+    fn positions(query: &str, text: &str) -> Option<Vec<usize>> {
+        fuzzy_match(query, text).map(|m| m.positions)
+    }
+
+    fn score(query: &str, text: &str) -> i32 {
+        fuzzy_match(query, text)
+            .unwrap_or_else(|| panic!("{query:?} should match {text:?}"))
+            .score
+    }
+
+    #[test]
+    fn test_matches_across_gaps() {
+        assert_eq!(
+            positions("mkasst", "Markdown Assistant"),
+            Some(vec![0, 3, 9, 10, 11, 14])
+        );
+        // Spaces in the query are ignored and case doesn't matter
+        assert!(fuzzy_match("MD assist", "Markdown Assistant").is_some());
+        assert_eq!(positions("gh", "GitHub"), Some(vec![0, 3]));
+    }
+
+    #[test]
+    fn test_transposed_characters_do_not_match() {
+        // Characters have to appear in the order they were typed
+        assert!(fuzzy_match("mrak", "Markdown").is_none());
+        assert!(fuzzy_match("tsil", "list").is_none());
+        assert!(fuzzy_match("markx", "Markdown").is_none());
+    }
+
+    #[test]
+    fn test_prefers_word_starts_and_runs() {
+        // "da" is the run at the start; with a "c" after it, the word start
+        // of "Access" lines up better
+        assert_eq!(positions("da", "Data Access"), Some(vec![0, 1]));
+        assert_eq!(positions("dac", "Data Access"), Some(vec![0, 5, 6]));
+
+        // A run at the start of the name beats the same letters scattered
+        assert!(score("git", "GitHub Tools") > score("git", "Go Integration Tests"));
+        // Word starts beat letters in the middle of words
+        assert!(score("dt", "Database Tools") > score("dt", "Credit"));
+        // Shorter gaps beat longer ones
+        assert!(score("ab", "a-b") > score("ab", "a------b"));
+    }
+
+    #[test]
+    fn test_empty_query_matches_everything() {
+        assert_eq!(fuzzy_match("", "anything").unwrap().score, 0);
+        assert_eq!(
+            fuzzy_match("  ", "").unwrap().positions,
+            Vec::<usize>::new()
+        );
+        assert!(fuzzy_match("a", "").is_none());
+    }
+}
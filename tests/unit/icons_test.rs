@@ -0,0 +1,23 @@
+#[cfg(test)]
+mod tests {
+    use gemini_cli_manager::icons::Icon;
+
+    #[test]
+    fn test_ascii_icons_are_ascii_only() {
+        for icon in Icon::ALL {
+            assert!(
+                icon.ascii().is_ascii(),
+                "{icon:?} has non-ASCII fallback {:?}",
+                icon.ascii()
+            );
+            assert!(!icon.ascii().is_empty(), "{icon:?} has an empty fallback");
+        }
+    }
+
+    #[test]
+    fn test_emoji_icons_differ_from_ascii() {
+        for icon in Icon::ALL {
+            assert_ne!(icon.emoji(), icon.ascii(), "{icon:?} has no ASCII variant");
+        }
+    }
+}
@@ -124,8 +124,109 @@ mod tests {
 
         let env = launcher.prepare_environment(&profile);
 
-        // Should keep the original value when expansion fails
-        assert_eq!(env.get("EXPANDED"), Some(&"$NONEXISTENT_VAR".to_string()));
+        // An unset variable expands to nothing, as in a shell
+        assert_eq!(env.get("EXPANDED"), Some(&String::new()));
+    }
+
+    #[test]
+    fn test_launch_plan_touches_nothing() {
+        let temp_dir = TempDir::new().unwrap();
+        let (storage, _storage_dir) = crate::test_utils::create_temp_storage();
+        let ext = McpFixtures::echo_extension();
+        storage.save_extension(&ext).unwrap();
+
+        let mut profile = ProfileBuilder::new("Planned")
+            .with_extensions(vec![&ext.id, "not-stored"])
+            .build();
+        let working_dir = temp_dir.path().join("project");
+        profile.working_directory = Some(working_dir.to_string_lossy().to_string());
+        profile
+            .environment_variables
+            .insert("MODE".to_string(), "review".to_string());
+
+        let launcher = Launcher::with_storage(storage);
+        let plan = launcher.plan(&profile).unwrap();
+
+        assert_eq!(plan.working_dir, working_dir);
+        assert_eq!(
+            plan.installs,
+            vec![(
+                ext.id.clone(),
+                working_dir.join(".gemini").join("extensions").join(&ext.id)
+            )]
+        );
+        assert_eq!(plan.missing, vec!["not-stored".to_string()]);
+        assert!(plan.args.is_empty());
+        assert_eq!(
+            plan.program.file_stem().and_then(|s| s.to_str()),
+            Some("gemini")
+        );
+        assert_eq!(plan.environment["MODE"], "review");
+        assert_eq!(plan.environment["GEMINI_PROFILE"], profile.id);
+
+        // Planning neither creates the working directory nor installs anything
+        assert!(!working_dir.exists());
+    }
+
+    #[test]
+    fn test_launch_plan_preview_masks_secret_references() {
+        use gemini_cli_manager::launcher::environment_preview;
+
+        let mut profile = ProfileBuilder::new("Planned").build();
+        profile
+            .environment_variables
+            .insert("MY_TOKEN".to_string(), "ghp_1234567890abcdef".to_string());
+        profile
+            .environment_variables
+            .insert("GH".to_string(), "${MY_TOKEN}".to_string());
+
+        let plan = Launcher::new().plan(&profile).unwrap();
+        // The plan holds the real value; only the preview hides it
+        assert_eq!(plan.environment["GH"], "ghp_1234567890abcdef");
+        assert_eq!(plan.secret_references, vec!["GH".to_string()]);
+
+        let preview = environment_preview(&plan.environment, &plan.secret_references);
+        let gh = preview.iter().find(|(key, _)| key == "GH").unwrap();
+        assert_eq!(gh.1, "ghp_...cdef");
+    }
+
+    #[test]
+    fn test_expand_environment_references() {
+        use gemini_cli_manager::launcher::expand_environment;
+        use std::collections::HashMap;
+
+        let os = |name: &str| match name {
+            "HOME" => Some("/home/dev".to_string()),
+            "PATH" => Some("/usr/bin".to_string()),
+            _ => None,
+        };
+        let vars: HashMap<String, String> = [
+            ("TOOLS", "$HOME/tools"),
+            ("CLI", "${TOOLS}/gemini"),
+            ("PATH", "$CLI:$PATH"),
+            ("PRICE", "$$5 and $"),
+            ("ESCAPED", "$${HOME}"),
+            ("MISSING", "[${NOT_SET}$ALSO_NOT_SET]"),
+            ("LOOP_A", "a$LOOP_B"),
+            ("LOOP_B", "b$LOOP_A"),
+        ]
+        .into_iter()
+        .map(|(k, v)| (k.to_string(), v.to_string()))
+        .collect();
+
+        let env = expand_environment(&vars, os);
+
+        assert_eq!(env["TOOLS"], "/home/dev/tools");
+        // References to other profile variables are expanded in turn
+        assert_eq!(env["CLI"], "/home/dev/tools/gemini");
+        // Referring to itself reads the inherited value
+        assert_eq!(env["PATH"], "/home/dev/tools/gemini:/usr/bin");
+        assert_eq!(env["PRICE"], "$5 and $");
+        assert_eq!(env["ESCAPED"], "${HOME}");
+        assert_eq!(env["MISSING"], "[]");
+        // A cycle stops where it would repeat
+        assert_eq!(env["LOOP_A"], "ab");
+        assert_eq!(env["LOOP_B"], "ba");
     }
 
     #[test]
@@ -158,6 +259,113 @@ mod tests {
         assert!(!ext_dir.join("CUSTOM.md").exists());
     }
 
+    #[test]
+    fn test_installed_manifest_is_normalized() {
+        use crate::test_utils::ExtensionBuilder;
+        use gemini_cli_manager::models::extension::McpServerConfig;
+
+        let temp_dir = TempDir::new().unwrap();
+        let (storage, _storage_dir) = crate::test_utils::create_temp_storage();
+        let launcher = Launcher::with_storage(storage);
+
+        let mut ext = ExtensionBuilder::new("Search Tools")
+            .with_description("Find things")
+            .build();
+        ext.context_file_name = Some("SEARCH.md".to_string());
+        ext.context_content = Some("# Search".to_string());
+        ext.mcp_servers.insert(
+            "search".to_string(),
+            McpServerConfig {
+                url: None,
+                command: Some("search-server".to_string()),
+                args: Some(vec!["--stdio".to_string()]),
+                cwd: None,
+                env: None,
+                timeout: None,
+                trust: Some(true),
+            },
+        );
+        launcher.storage.save_extension(&ext).unwrap();
+
+        let profile = ProfileBuilder::new("normalized")
+            .with_extensions(vec![&ext.id])
+            .build();
+        let workspace = temp_dir.path().join(&profile.id);
+        launcher
+            .install_extensions_for_profile(&profile, &workspace)
+            .unwrap();
+
+        // Recognized optional fields survive, keys are sorted and unset
+        // server fields are left out
+        let manifest = std::fs::read_to_string(
+            workspace
+                .join(".gemini")
+                .join("extensions")
+                .join(&ext.id)
+                .join("gemini-extension.json"),
+        )
+        .unwrap();
+        assert_eq!(
+            manifest,
+            r#"{
+  "contextFileName": "GEMINI.md",
+  "description": "Find things",
+  "mcpServers": {
+    "search": {
+      "args": [
+        "--stdio"
+      ],
+      "command": "search-server",
+      "trust": true
+    }
+  },
+  "name": "Search Tools",
+  "version": "1.0.0"
+}"#
+        );
+    }
+
+    #[test]
+    fn test_one_off_exclusion_reduces_launched_set() {
+        let temp_dir = TempDir::new().unwrap();
+        let (storage, _storage_dir) = crate::test_utils::create_temp_storage();
+        let launcher = Launcher::with_storage(storage);
+
+        let echo = McpFixtures::echo_extension();
+        let multi = McpFixtures::multi_server_extension();
+        launcher.storage.save_extension(&echo).unwrap();
+        launcher.storage.save_extension(&multi).unwrap();
+        let profile = ProfileBuilder::new("one-off")
+            .with_extensions(vec![&echo.id, &multi.id])
+            .build();
+        launcher.storage.save_profile(&profile).unwrap();
+
+        let reduced = profile.without_extensions(std::slice::from_ref(&multi.id));
+        assert_eq!(reduced.extension_ids, vec![echo.id.clone()]);
+
+        let workspace = temp_dir.path().join(&profile.id);
+        launcher
+            .install_extensions_for_profile(&reduced, &workspace)
+            .unwrap();
+        let extensions_dir = workspace.join(".gemini").join("extensions");
+        assert!(extensions_dir.join(&echo.id).exists());
+        assert!(!extensions_dir.join(&multi.id).exists());
+
+        // Neither the profile nor its stored copy changed
+        assert_eq!(
+            profile.extension_ids,
+            vec![echo.id.clone(), multi.id.clone()]
+        );
+        assert_eq!(
+            launcher
+                .storage
+                .load_profile(&profile.id)
+                .unwrap()
+                .extension_ids,
+            profile.extension_ids
+        );
+    }
+
     #[test]
     fn test_install_extension_empty_context_filename() {
         let temp_dir = TempDir::new().unwrap();
@@ -222,6 +430,718 @@ mod tests {
         assert!(test_dir.exists());
     }
 
+    #[test]
+    fn test_command_line_includes_profile_environment() {
+        let temp_dir = TempDir::new().unwrap();
+        let launcher =
+            Launcher::with_storage(Storage::with_data_dir(temp_dir.path().to_path_buf()));
+
+        let mut profile = ProfileBuilder::new("cmd").build();
+        profile.working_directory = Some(temp_dir.path().to_string_lossy().to_string());
+        profile
+            .environment_variables
+            .insert("NODE_ENV".to_string(), "development".to_string());
+        profile
+            .environment_variables
+            .insert("GREETING".to_string(), "hello world".to_string());
+
+        let command = launcher.command_line(&profile).unwrap();
+
+        assert!(command.starts_with("cd "));
+        assert!(command.ends_with(" gemini"));
+        assert!(command.contains(&format!("GEMINI_PROFILE={}", profile.id)));
+        assert!(command.contains("NODE_ENV=development"));
+        assert!(command.contains("GREETING='hello world'"));
+
+        let env = launcher.profile_environment(&profile);
+        assert_eq!(env.get("GEMINI_PROFILE"), Some(&profile.id));
+        assert_eq!(env.get("NODE_ENV").map(String::as_str), Some("development"));
+        assert!(!command.contains("env -i"));
+
+        // A clean launch spells out the planned environment under `env -i`
+        profile.launch_config.clean_environment = true;
+        profile.launch_config.env_allowlist = vec!["PATH".to_string()];
+        let command = launcher.command_line(&profile).unwrap();
+        let plan = launcher.plan(&profile).unwrap();
+
+        assert!(command.contains(" && env -i "), "{command}");
+        assert!(command.ends_with(" gemini"));
+        for key in plan.environment.keys() {
+            assert!(command.contains(&format!(" {key}=")), "{key}: {command}");
+        }
+        // Inherited variables off the allowlist are left out
+        assert!(!command.contains(" HOME="), "{command}");
+    }
+
+    #[test]
+    fn test_configured_gemini_cli_replaces_path_lookup() {
+        let temp_dir = TempDir::new().unwrap();
+        let cli = temp_dir.path().join("bin dir").join("gemini-nightly");
+        let launcher =
+            Launcher::with_storage(Storage::with_data_dir(temp_dir.path().to_path_buf()))
+                .with_gemini_cli(Some(&cli.to_string_lossy()));
+
+        let mut profile = ProfileBuilder::new("nightly").build();
+        profile.working_directory = Some(temp_dir.path().to_string_lossy().to_string());
+
+        assert_eq!(launcher.plan(&profile).unwrap().program, cli);
+        let command = launcher.command_line(&profile).unwrap();
+        assert!(
+            command.ends_with(&format!(" '{}'", cli.to_string_lossy())),
+            "{command}"
+        );
+
+        // A blank path keeps looking up `gemini`
+        let launcher =
+            Launcher::with_storage(Storage::with_data_dir(temp_dir.path().to_path_buf()))
+                .with_gemini_cli(Some("  "));
+        assert!(
+            launcher
+                .command_line(&profile)
+                .unwrap()
+                .ends_with(" gemini")
+        );
+    }
+
+    #[test]
+    fn test_configured_extensions_dir_replaces_workspace() {
+        let (storage, _storage_dir) = crate::test_utils::create_temp_storage();
+        let ext = McpFixtures::echo_extension();
+        storage.save_extension(&ext).unwrap();
+        let temp_dir = TempDir::new().unwrap();
+        let shared = temp_dir.path().join("shared extensions");
+        let launcher =
+            Launcher::with_storage(storage).with_extensions_dir(Some(&shared.to_string_lossy()));
+
+        let mut profile = ProfileBuilder::new("shared")
+            .with_extensions(vec![&ext.id])
+            .build();
+        profile.working_directory = Some(temp_dir.path().to_string_lossy().to_string());
+
+        let plan = launcher.plan(&profile).unwrap();
+        assert_eq!(plan.installs, vec![(ext.id.clone(), shared.join(&ext.id))]);
+
+        launcher.setup_workspace(temp_dir.path()).unwrap();
+        launcher
+            .install_extensions_for_profile(&profile, temp_dir.path())
+            .unwrap();
+        assert!(shared.join(&ext.id).join("gemini-extension.json").exists());
+        assert!(
+            !temp_dir
+                .path()
+                .join(".gemini/extensions")
+                .join(&ext.id)
+                .exists()
+        );
+    }
+
+    #[test]
+    fn test_build_config_merges_overlapping_servers() {
+        use crate::test_utils::ExtensionBuilder;
+        use gemini_cli_manager::launcher::build_config;
+
+        let mut first = ExtensionBuilder::new("First").build();
+        first
+            .mcp_servers
+            .insert("echo".to_string(), McpFixtures::echo_server_simple());
+        let mut second = ExtensionBuilder::new("Second").build();
+        second
+            .mcp_servers
+            .insert("echo".to_string(), McpFixtures::echo_server_python());
+        second
+            .mcp_servers
+            .insert("docker".to_string(), McpFixtures::echo_server_docker());
+
+        let mut profile = ProfileBuilder::new("merge").build();
+        profile
+            .environment_variables
+            .insert("API_KEY".to_string(), "$SECRET_KEY".to_string());
+
+        let output = build_config(&profile, &[first, second]).unwrap();
+        let config: serde_json::Value = serde_json::from_str(&output).unwrap();
+
+        // The first extension to define a server wins
+        assert_eq!(config["mcpServers"]["echo"]["command"], "node");
+        assert_eq!(config["mcpServers"]["docker"]["command"], "docker");
+        assert_eq!(config["mcpServers"].as_object().unwrap().len(), 2);
+
+        // The hidden definition is reported rather than silently dropped
+        let shadowed = config["shadowedServers"].as_array().unwrap();
+        assert_eq!(shadowed.len(), 1);
+        assert_eq!(shadowed[0]["server"], "echo");
+        assert_eq!(shadowed[0]["extension"], "Second");
+        assert_eq!(shadowed[0]["shadowedBy"], "First");
+
+        // Environment values are shown as written, not expanded
+        assert_eq!(config["environment"]["API_KEY"], "$SECRET_KEY");
+        assert_eq!(config["environment"]["GEMINI_PROFILE"], profile.id.as_str());
+        assert_eq!(config["extensions"][1]["name"], "Second");
+    }
+
+    #[test]
+    fn test_build_config_without_overlaps_has_no_shadowed_servers() {
+        use gemini_cli_manager::launcher::build_config;
+
+        let profile = ProfileBuilder::new("plain").build();
+        let output = build_config(&profile, &[McpFixtures::echo_extension()]).unwrap();
+        let config: serde_json::Value = serde_json::from_str(&output).unwrap();
+
+        assert!(config.get("shadowedServers").is_none());
+        assert!(!config["mcpServers"].as_object().unwrap().is_empty());
+    }
+
+    #[cfg(unix)]
+    #[test]
+    fn test_smoke_test_server_detects_immediate_exit() {
+        use gemini_cli_manager::launcher::smoke_test_server;
+        use gemini_cli_manager::models::extension::McpServerConfig;
+        use std::collections::HashMap;
+        use std::time::Duration;
+
+        let temp_dir = TempDir::new().unwrap();
+        let server = |command: &str, args: &[&str]| McpServerConfig {
+            command: Some(command.to_string()),
+            args: Some(args.iter().map(|arg| arg.to_string()).collect()),
+            cwd: None,
+            env: None,
+            timeout: None,
+            trust: None,
+            url: None,
+        };
+        let wait = Duration::from_millis(200);
+
+        // A command that exits straight away fails the check
+        let result = smoke_test_server(&server("true", &[]), temp_dir.path(), wait);
+        assert!(
+            result
+                .unwrap_err()
+                .to_string()
+                .contains("exited immediately")
+        );
+
+        // One that leaves a child holding stderr open is still reported promptly
+        let started = std::time::Instant::now();
+        let result = smoke_test_server(
+            &server("sh", &["-c", "sleep 30 & echo 'missing key' >&2; exit 1"]),
+            temp_dir.path(),
+            wait,
+        );
+        assert!(result.unwrap_err().to_string().contains("missing key"));
+        assert!(started.elapsed() < Duration::from_secs(5));
+
+        // One that stays up passes and is killed afterwards
+        assert!(smoke_test_server(&server("sleep", &["30"]), temp_dir.path(), wait).is_ok());
+
+        // Environment values are expanded the way a launch expands them
+        let mut needs_env = server(
+            "sh",
+            &["-c", "[ \"$TOOLS\" = \"$HOME/tools\" ] && sleep 30"],
+        );
+        needs_env.env = Some(HashMap::from([(
+            "TOOLS".to_string(),
+            "${HOME}/tools".to_string(),
+        )]));
+        assert!(smoke_test_server(&needs_env, temp_dir.path(), wait).is_ok());
+
+        // A missing binary is reported rather than panicking
+        let result = smoke_test_server(
+            &server("definitely-not-a-real-mcp-server", &[]),
+            temp_dir.path(),
+            wait,
+        );
+        assert!(result.unwrap_err().to_string().contains("failed to start"));
+
+        // URL-based servers have nothing to spawn
+        let mut url_server = server("unused", &[]);
+        url_server.command = None;
+        url_server.url = Some("http://localhost:3000/sse".to_string());
+        assert!(smoke_test_server(&url_server, temp_dir.path(), wait).is_err());
+    }
+
+    #[test]
+    fn test_check_compatibility_with_mock_versions() {
+        use crate::test_utils::ExtensionBuilder;
+        use gemini_cli_manager::launcher::check_compatibility;
+        use semver::Version;
+
+        let mut needs_new = ExtensionBuilder::new("Needs New").build();
+        needs_new.min_gemini_version = Some("0.2.0".to_string());
+        let mut needs_old = ExtensionBuilder::new("Needs Old").build();
+        needs_old.min_gemini_version = Some("0.1.0".to_string());
+        let anything = ExtensionBuilder::new("Anything").build();
+        let extensions = [needs_new, needs_old, anything];
+
+        // Older than one requirement
+        let problems = check_compatibility(&extensions, &Version::new(0, 1, 9));
+        assert_eq!(problems.len(), 1);
+        assert!(problems[0].contains("'Needs New' requires Gemini CLI 0.2.0"));
+        assert!(problems[0].contains("found 0.1.9"));
+
+        // Exactly the minimum is enough
+        assert!(check_compatibility(&extensions, &Version::new(0, 2, 0)).is_empty());
+        assert!(check_compatibility(&extensions, &Version::new(1, 0, 0)).is_empty());
+
+        // Pre-releases sort before the release they lead up to
+        let pre = Version::parse("0.2.0-nightly.1").unwrap();
+        assert_eq!(check_compatibility(&extensions, &pre).len(), 1);
+
+        // A requirement that can't be parsed is reported, not ignored
+        let mut broken = ExtensionBuilder::new("Broken").build();
+        broken.min_gemini_version = Some("soon".to_string());
+        let problems = check_compatibility(&[broken], &Version::new(1, 0, 0));
+        assert!(problems[0].contains("invalid minGeminiVersion 'soon'"));
+    }
+
+    #[test]
+    fn test_parse_gemini_version_output() {
+        use gemini_cli_manager::launcher::parse_gemini_version;
+        use semver::Version;
+
+        assert_eq!(parse_gemini_version("0.1.9\n"), Some(Version::new(0, 1, 9)));
+        assert_eq!(
+            parse_gemini_version("gemini v1.2.3"),
+            Some(Version::new(1, 2, 3))
+        );
+        assert_eq!(parse_gemini_version("command not found"), None);
+    }
+
+    #[test]
+    fn test_environment_preview_masks_secrets() {
+        use gemini_cli_manager::launcher::{environment_preview, mask_env_value};
+        use std::collections::HashMap;
+
+        let env: HashMap<String, String> = [
+            ("GITHUB_TOKEN", "ghp_1234567890abcdef"),
+            ("client_secret", "short"),
+            ("OPENAI_API_KEY", "sk-ünïcødé-value"),
+            ("NODE_ENV", "development"),
+        ]
+        .into_iter()
+        .map(|(k, v)| (k.to_string(), v.to_string()))
+        .collect();
+
+        assert_eq!(
+            environment_preview(&env, &[]),
+            vec![
+                ("GITHUB_TOKEN".to_string(), "ghp_...cdef".to_string()),
+                ("NODE_ENV".to_string(), "development".to_string()),
+                ("OPENAI_API_KEY".to_string(), "sk-ü...alue".to_string()),
+                ("client_secret".to_string(), "***".to_string()),
+            ]
+        );
+        // Empty secrets are masked too, so their absence isn't revealed
+        assert_eq!(mask_env_value("API_KEY", ""), "***");
+    }
+
+    #[test]
+    fn test_environment_preview_masks_secret_references() {
+        use gemini_cli_manager::launcher::{environment_preview, secret_references};
+        use std::collections::HashMap;
+
+        let vars: HashMap<String, String> = [
+            ("GH", "$GITHUB_TOKEN"),
+            ("AUTH", "Bearer ${GH}"),
+            ("LOOP", "$LOOP:$AUTH"),
+            ("PRICE", "$$GITHUB_TOKEN"),
+            ("PATH", "$HOME/bin:$PATH"),
+        ]
+        .into_iter()
+        .map(|(k, v)| (k.to_string(), v.to_string()))
+        .collect();
+
+        // Directly, through another entry, but not through `$$` or `$PATH`
+        let refs = secret_references(&vars);
+        assert_eq!(refs, vec!["AUTH", "GH", "LOOP"]);
+
+        let expanded: HashMap<String, String> =
+            [("GH", "ghp_1234567890abcdef"), ("PRICE", "$GITHUB_TOKEN")]
+                .into_iter()
+                .map(|(k, v)| (k.to_string(), v.to_string()))
+                .collect();
+        assert_eq!(
+            environment_preview(&expanded, &refs),
+            vec![
+                ("GH".to_string(), "ghp_...cdef".to_string()),
+                ("PRICE".to_string(), "$GITHUB_TOKEN".to_string()),
+            ]
+        );
+    }
+
+    #[test]
+    fn test_clean_environment_keeps_only_allowlisted_vars() {
+        let temp_dir = TempDir::new().unwrap();
+        let launcher =
+            Launcher::with_storage(Storage::with_data_dir(temp_dir.path().to_path_buf()));
+
+        let os_vars = || {
+            [
+                ("PATH", "/usr/bin"),
+                ("HOME", "/home/tester"),
+                ("AWS_SECRET_ACCESS_KEY", "hunter2"),
+                ("EDITOR", "vim"),
+            ]
+            .map(|(k, v)| (k.to_string(), v.to_string()))
+        };
+
+        let mut profile = ProfileBuilder::new("clean").build();
+        profile
+            .environment_variables
+            .insert("NODE_ENV".to_string(), "production".to_string());
+
+        // By default everything is inherited
+        let env = launcher.assemble_environment(&profile, os_vars());
+        assert_eq!(env.get("EDITOR").map(String::as_str), Some("vim"));
+        assert!(env.contains_key("AWS_SECRET_ACCESS_KEY"));
+
+        // Clean mode keeps the allowlist plus the profile's own variables
+        profile.launch_config.clean_environment = true;
+        profile.launch_config.env_allowlist = vec!["PATH".to_string(), "HOME".to_string()];
+        let env = launcher.assemble_environment(&profile, os_vars());
+
+        let mut keys: Vec<&str> = env.keys().map(String::as_str).collect();
+        keys.sort();
+        assert_eq!(keys, ["GEMINI_PROFILE", "HOME", "NODE_ENV", "PATH"]);
+        assert_eq!(env.get("PATH").map(String::as_str), Some("/usr/bin"));
+        assert_eq!(env.get("NODE_ENV").map(String::as_str), Some("production"));
+    }
+
+    #[test]
+    fn test_shadowed_variables_detected_during_resolution() {
+        let temp_dir = TempDir::new().unwrap();
+        let launcher =
+            Launcher::with_storage(Storage::with_data_dir(temp_dir.path().to_path_buf()));
+
+        let os_vars = || {
+            [
+                ("API_BASE", "https://api.example.com"),
+                ("NODE_ENV", "production"),
+                ("EDITOR", "vim"),
+            ]
+            .map(|(k, v)| (k.to_string(), v.to_string()))
+        };
+
+        let mut profile = ProfileBuilder::new("shadow").build();
+        for (key, value) in [
+            ("API_BASE", "http://localhost:8080"),
+            ("NODE_ENV", "production"),
+            ("DEBUG", "1"),
+        ] {
+            profile
+                .environment_variables
+                .insert(key.to_string(), value.to_string());
+        }
+
+        // Only a different value counts; an identical one hides nothing
+        assert_eq!(
+            launcher.shadowed_variables(&profile, os_vars()),
+            vec!["API_BASE".to_string()]
+        );
+        // The profile still wins in the resolved environment
+        let env = launcher.assemble_environment(&profile, os_vars());
+        assert_eq!(
+            env.get("API_BASE").map(String::as_str),
+            Some("http://localhost:8080")
+        );
+
+        // Variables a clean environment drops are not inherited, so not shadowed
+        profile.launch_config.clean_environment = true;
+        profile.launch_config.env_allowlist = vec!["EDITOR".to_string()];
+        assert!(launcher.shadowed_variables(&profile, os_vars()).is_empty());
+    }
+
+    #[test]
+    fn test_launch_config_without_env_fields_loads() {
+        use gemini_cli_manager::models::profile::{LaunchConfig, default_env_allowlist};
+
+        let config: LaunchConfig =
+            serde_json::from_str(r#"{"clean_launch": false, "cleanup_on_exit": true}"#).unwrap();
+        assert!(!config.clean_environment);
+        assert_eq!(config.env_allowlist, default_env_allowlist());
+    }
+
+    #[test]
+    fn test_install_refuses_paths_outside_extensions_dir() {
+        let (storage, _data) = crate::test_utils::create_temp_storage();
+        let workspace = TempDir::new().unwrap();
+
+        // A crafted id that would climb out of .gemini/extensions
+        let mut ext = crate::test_utils::ExtensionBuilder::new("escape").build();
+        ext.id = "../escaped".to_string();
+        storage.save_extension(&ext).unwrap();
+
+        let launcher = Launcher::with_storage(storage);
+        launcher.setup_workspace(workspace.path()).unwrap();
+
+        let profile = ProfileBuilder::new("escape")
+            .with_extensions(vec!["../escaped"])
+            .build();
+        let result = launcher.install_extensions_for_profile(&profile, workspace.path());
+
+        assert!(result.is_err());
+        assert!(!workspace.path().join(".gemini").join("escaped").exists());
+    }
+
+    #[cfg(unix)]
+    #[test]
+    fn test_install_refuses_to_replace_linked_extension() {
+        let (storage, _data) = crate::test_utils::create_temp_storage();
+        let workspace = TempDir::new().unwrap();
+        let dev_tree = TempDir::new().unwrap();
+        std::fs::write(
+            dev_tree.path().join("gemini-extension.json"),
+            "{\"dev\": true}",
+        )
+        .unwrap();
+
+        let ext = McpFixtures::echo_extension();
+        storage.save_extension(&ext).unwrap();
+
+        // The user linked their working copy in under the same id
+        let extensions_dir = workspace.path().join(".gemini").join("extensions");
+        std::fs::create_dir_all(&extensions_dir).unwrap();
+        let link = extensions_dir.join(&ext.id);
+        std::os::unix::fs::symlink(dev_tree.path(), &link).unwrap();
+
+        let launcher = Launcher::with_storage(storage);
+        let profile = ProfileBuilder::new("linked")
+            .with_extensions(vec![&ext.id])
+            .build();
+        let err = launcher
+            .install_extensions_for_profile(&profile, workspace.path())
+            .unwrap_err();
+
+        assert!(err.to_string().contains("linked extension"));
+        assert!(link.is_symlink());
+        assert_eq!(
+            std::fs::read_to_string(dev_tree.path().join("gemini-extension.json")).unwrap(),
+            "{\"dev\": true}"
+        );
+        assert!(!dev_tree.path().join("GEMINI.md").exists());
+    }
+
+    #[cfg(unix)]
+    #[test]
+    fn test_cleanup_keeps_directories_it_did_not_install() {
+        use gemini_cli_manager::launcher::remove_installed_extensions;
+
+        let (storage, _data) = crate::test_utils::create_temp_storage();
+        let workspace = TempDir::new().unwrap();
+        let dev_tree = TempDir::new().unwrap();
+
+        let ext = McpFixtures::echo_extension();
+        storage.save_extension(&ext).unwrap();
+        let launcher = Launcher::with_storage(storage);
+        let profile = ProfileBuilder::new("cleanup")
+            .with_extensions(vec![&ext.id])
+            .build();
+        launcher
+            .install_extensions_for_profile(&profile, workspace.path())
+            .unwrap();
+
+        // Alongside ours: one installed by Gemini itself, a link and a stray file
+        let extensions_dir = workspace.path().join(".gemini").join("extensions");
+        let gemini_installed = extensions_dir.join("gemini-installed");
+        std::fs::create_dir(&gemini_installed).unwrap();
+        std::fs::write(gemini_installed.join("gemini-extension.json"), "{}").unwrap();
+        let link = extensions_dir.join("linked");
+        std::os::unix::fs::symlink(dev_tree.path(), &link).unwrap();
+        std::fs::write(extensions_dir.join("notes.txt"), "keep me").unwrap();
+
+        let warnings = remove_installed_extensions(&extensions_dir).unwrap();
+
+        assert!(!extensions_dir.join(&ext.id).exists());
+        assert!(gemini_installed.join("gemini-extension.json").exists());
+        assert!(link.is_symlink());
+        assert!(extensions_dir.join("notes.txt").exists());
+        assert_eq!(
+            warnings,
+            [
+                "Kept gemini-installed: not installed by Gemini CLI Manager",
+                "Kept linked extension: linked",
+                "Kept notes.txt: not installed by Gemini CLI Manager",
+            ]
+        );
+    }
+
+    #[test]
+    fn test_install_only_takes_over_directories_it_installed() {
+        use gemini_cli_manager::launcher::{INSTALL_MARKER, remove_installed_extensions};
+
+        let (storage, _data) = crate::test_utils::create_temp_storage();
+        let workspace = TempDir::new().unwrap();
+        let extensions_dir = workspace.path().join(".gemini").join("extensions");
+
+        let ext = McpFixtures::echo_extension();
+        storage.save_extension(&ext).unwrap();
+        let launcher = Launcher::with_storage(storage);
+        let profile = ProfileBuilder::new("takeover")
+            .with_extensions(vec![&ext.id])
+            .build();
+
+        // A directory of the same name that someone else put there is left alone
+        let ext_dir = extensions_dir.join(&ext.id);
+        std::fs::create_dir_all(&ext_dir).unwrap();
+        std::fs::write(ext_dir.join("gemini-extension.json"), "{\"mine\": true}").unwrap();
+        let err = launcher
+            .install_extensions_for_profile(&profile, workspace.path())
+            .unwrap_err();
+        assert!(err.to_string().contains("didn't install"), "{err}");
+        assert!(!ext_dir.join(INSTALL_MARKER).exists());
+        assert_eq!(
+            std::fs::read_to_string(ext_dir.join("gemini-extension.json")).unwrap(),
+            "{\"mine\": true}"
+        );
+
+        // A copy installed before the marker existed is taken over, so cleanup
+        // removes it
+        std::fs::write(
+            ext_dir.join("gemini-extension.json"),
+            serde_json::to_string_pretty(&ext.manifest()).unwrap(),
+        )
+        .unwrap();
+        launcher
+            .install_extensions_for_profile(&profile, workspace.path())
+            .unwrap();
+        assert!(ext_dir.join(INSTALL_MARKER).is_file());
+        assert!(
+            remove_installed_extensions(&extensions_dir)
+                .unwrap()
+                .is_empty()
+        );
+        assert!(!ext_dir.exists());
+    }
+
+    #[test]
+    fn test_resolve_gemini_ext_dir_default() {
+        use gemini_cli_manager::launcher::resolve_gemini_ext_dir_from;
+
+        let workspace = PathBuf::from("/home/tester/project");
+
+        assert_eq!(
+            resolve_gemini_ext_dir_from(None, None, &workspace),
+            workspace.join(".gemini").join("extensions")
+        );
+        // An empty override is treated as unset
+        assert_eq!(
+            resolve_gemini_ext_dir_from(Some("  "), Some(String::new()), &workspace),
+            workspace.join(".gemini").join("extensions")
+        );
+    }
+
+    #[test]
+    fn test_resolve_gemini_ext_dir_with_override() {
+        use gemini_cli_manager::launcher::resolve_gemini_ext_dir_from;
+
+        let workspace = PathBuf::from("/home/tester/project");
+
+        // GEMINI_HOME beats the default
+        assert_eq!(
+            resolve_gemini_ext_dir_from(None, Some("/opt/gemini".to_string()), &workspace),
+            PathBuf::from("/opt/gemini").join("extensions")
+        );
+
+        // A directory configured in settings beats GEMINI_HOME
+        assert_eq!(
+            resolve_gemini_ext_dir_from(
+                Some("/srv/extensions"),
+                Some("/opt/gemini".to_string()),
+                &workspace
+            ),
+            PathBuf::from("/srv/extensions")
+        );
+    }
+
+    #[test]
+    fn test_platform_check_on_mismatched_os() {
+        use crate::test_utils::{ExtensionBuilder, create_test_storage};
+        use gemini_cli_manager::action::Action;
+        use gemini_cli_manager::app::launch_confirmation;
+        use gemini_cli_manager::launcher::check_platforms;
+        use gemini_cli_manager::models::extension::current_platform;
+
+        let mut mac_only = ExtensionBuilder::new("Mac Only").build();
+        mac_only.platforms = vec!["darwin".to_string()];
+        let anywhere = ExtensionBuilder::new("Anywhere").build();
+
+        assert_eq!(
+            check_platforms(&[mac_only.clone(), anywhere.clone()], "linux"),
+            ["'Mac Only' supports darwin only (this is linux)"]
+        );
+        assert!(check_platforms(&[mac_only.clone(), anywhere], "darwin").is_empty());
+        // Rust calls it macos; manifests call it darwin
+        assert!(mac_only.supports_platform("macos"));
+
+        // Launching asks first, whether or not empty profiles are confirmed
+        let storage = create_test_storage();
+        let mut elsewhere = ExtensionBuilder::new("Elsewhere").build();
+        elsewhere.platforms = vec!["plan9".to_string()];
+        storage.save_extension(&elsewhere).unwrap();
+        let profile = ProfileBuilder::new("Mixed")
+            .with_extensions(vec![&elsewhere.id])
+            .build();
+        storage.save_profile(&profile).unwrap();
+        assert_ne!(current_platform(), "plan9");
+        assert_eq!(
+            launch_confirmation(&storage, &profile.id, &[], false),
+            Some(Action::ConfirmPlatformLaunch(profile.id.clone(), vec![]))
+        );
+    }
+
+    #[test]
+    fn test_launch_config_is_recorded_for_later() {
+        use crate::test_utils::create_test_storage;
+        use crossterm::event::{KeyCode, KeyEvent};
+        use gemini_cli_manager::components::{Component, config_preview::ConfigPreview};
+        use gemini_cli_manager::tui::Event;
+
+        let storage = create_test_storage();
+        assert_eq!(storage.load_last_launch_config().unwrap(), None);
+
+        let ext = McpFixtures::echo_extension();
+        storage.save_extension(&ext).unwrap();
+        let profile = ProfileBuilder::new("Debugging")
+            .with_extensions(vec![&ext.id])
+            .build();
+        storage.save_profile(&profile).unwrap();
+
+        let mut profile = profile;
+        profile
+            .environment_variables
+            .insert("GITHUB_TOKEN".to_string(), "ghp_abcdef123456".to_string());
+        profile.environment_variables.insert(
+            "OPENAI_API_KEY".to_string(),
+            "${OPENAI_API_KEY}".to_string(),
+        );
+
+        let launcher = Launcher::with_storage(storage.clone());
+        let recorded = launcher.record_launch_config(&profile).unwrap();
+        assert_eq!(recorded, launcher.preview_config(&profile).unwrap());
+        assert!(recorded.contains("\"mcpServers\""));
+
+        // Inline secrets never reach the file; references stay readable
+        let saved = std::fs::read_to_string(storage.last_launch_config_file()).unwrap();
+        assert!(!saved.contains("ghp_abcdef123456"), "{saved}");
+        assert!(saved.contains("ghp_...3456"), "{saved}");
+        assert!(saved.contains("${OPENAI_API_KEY}"), "{saved}");
+        assert_eq!(
+            storage.load_last_launch_config().unwrap().as_deref(),
+            Some(recorded.as_str())
+        );
+
+        // The preview can switch to the recorded config after the profile changes
+        let mut changed = profile.without_extensions(&[ext.id.clone()]);
+        changed.name = "Changed".to_string();
+        let current = launcher.preview_config(&changed).unwrap();
+        let mut preview = ConfigPreview::new(&changed.name, current.clone())
+            .with_last_launch(storage.load_last_launch_config().unwrap());
+        assert_eq!(preview.config(), current);
+        preview
+            .handle_events(Some(Event::Key(KeyEvent::from(KeyCode::Tab))))
+            .unwrap();
+        assert!(preview.is_showing_last_launch());
+        assert_eq!(preview.config(), recorded);
+    }
+
     // Note: We can't easily test the actual launch_with_profile method
     // because it requires the 'gemini' command to be installed.
     // Similarly, launch_in_terminal just calls launch_with_profile.
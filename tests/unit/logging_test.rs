@@ -2,6 +2,7 @@
 mod tests {
     use gemini_cli_manager::logging;
     use std::env;
+    use tracing_subscriber::filter::LevelFilter;
 
     #[test]
     fn test_log_env_variable_name() {
@@ -59,4 +60,27 @@ mod tests {
             env::remove_var("RUST_LOG");
         }
     }
+
+    #[test]
+    fn test_filter_defaults_to_info() {
+        let filter = logging::build_filter(false, None).unwrap();
+        assert_eq!(filter.max_level_hint(), Some(LevelFilter::INFO));
+    }
+
+    #[test]
+    fn test_filter_uses_env_directives() {
+        let filter = logging::build_filter(false, Some("warn")).unwrap();
+        assert_eq!(filter.max_level_hint(), Some(LevelFilter::WARN));
+    }
+
+    #[test]
+    fn test_debug_flag_overrides_env_directives() {
+        let filter = logging::build_filter(true, Some("warn")).unwrap();
+        assert_eq!(filter.max_level_hint(), Some(LevelFilter::DEBUG));
+    }
+
+    #[test]
+    fn test_filter_rejects_invalid_directives() {
+        assert!(logging::build_filter(false, Some("gemini=verbose")).is_err());
+    }
 }
@@ -38,8 +38,13 @@ mod tests {
             mcp_servers: HashMap::new(),
             context_file_name: None,
             context_content: None,
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: gemini_cli_manager::models::extension::ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: None,
                 tags: vec![],
             },
@@ -50,6 +55,7 @@ mod tests {
             id: "test-profile".to_string(),
             name: "Test Profile".to_string(),
             description: Some("A test profile".to_string()),
+            inherits: None,
             extension_ids: vec!["test-ext".to_string()],
             environment_variables: HashMap::new(),
             working_directory: None,
@@ -131,8 +137,13 @@ mod tests {
                 mcp_servers: HashMap::new(),
                 context_file_name: None,
                 context_content: None,
+                enabled_by_default: false,
+                min_gemini_version: None,
+                platforms: Vec::new(),
+                icon: None,
                 metadata: gemini_cli_manager::models::extension::ExtensionMetadata {
                     imported_at: Utc::now(),
+                    updated_at: None,
                     source_path: None,
                     tags: vec![],
                 },
@@ -150,6 +161,7 @@ mod tests {
                 } else {
                     None
                 },
+                inherits: None,
                 extension_ids: vec![format!("ext-{i}")],
                 environment_variables: HashMap::new(),
                 working_directory: None,
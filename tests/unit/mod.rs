@@ -4,14 +4,18 @@ pub mod cli_test;
 pub mod components;
 pub mod components_trait_test;
 pub mod errors_test;
+pub mod fuzzy_test;
+pub mod icons_test;
 pub mod launcher_additional_test;
 pub mod launcher_mock_test;
 pub mod launcher_test;
 pub mod logging_test;
 pub mod main_test;
 pub mod storage_test;
+pub mod text_test;
 pub mod theme_test;
 pub mod tui_test;
 pub mod validation_test;
 pub mod view_manager_additional_test;
 pub mod view_manager_test;
+pub mod watcher_test;
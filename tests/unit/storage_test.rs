@@ -122,6 +122,36 @@ mod tests {
         assert_eq!(api_server.timeout, Some(10000));
     }
 
+    #[test]
+    fn test_mcp_servers_saved_in_name_order() {
+        let (storage, _temp) = create_temp_storage();
+
+        let ext = McpFixtures::multi_server_extension();
+        storage.save_extension(&ext).unwrap();
+
+        let path = storage
+            .data_dir()
+            .join("extensions")
+            .join(format!("{}.json", ext.id));
+        let saved = std::fs::read_to_string(path).unwrap();
+
+        let api = saved.find("\"api-server\"").unwrap();
+        let echo = saved.find("\"echo\"").unwrap();
+        let python = saved.find("\"python-echo\"").unwrap();
+        assert!(api < echo && echo < python);
+
+        // Saving again produces byte-identical output
+        storage.save_extension(&ext).unwrap();
+        let resaved = std::fs::read_to_string(
+            storage
+                .data_dir()
+                .join("extensions")
+                .join(format!("{}.json", ext.id)),
+        )
+        .unwrap();
+        assert_eq!(saved, resaved);
+    }
+
     #[test]
     fn test_profile_crud_operations() {
         let (storage, _temp) = create_temp_storage();
@@ -234,6 +264,34 @@ mod tests {
         assert_eq!(extensions.len(), 0);
     }
 
+    #[test]
+    fn test_list_many_extensions_skips_corrupted() {
+        let (storage, temp) = create_temp_storage();
+
+        for i in 0..100 {
+            let ext = ExtensionBuilder::new(&format!("Ext {i:03}")).build();
+            storage.save_extension(&ext).unwrap();
+        }
+        std::fs::write(
+            temp.path().join("extensions").join("ext-050.json"),
+            "{ invalid json",
+        )
+        .unwrap();
+
+        // Parsed across several workers, but still in file name order
+        let ids: Vec<String> = storage
+            .list_extensions()
+            .unwrap()
+            .into_iter()
+            .map(|e| e.id)
+            .collect();
+        let expected: Vec<String> = (0..100)
+            .filter(|i| *i != 50)
+            .map(|i| format!("ext-{i:03}"))
+            .collect();
+        assert_eq!(ids, expected);
+    }
+
     #[test]
     fn test_extension_metadata_persistence() {
         let (storage, _temp) = create_temp_storage();
@@ -250,6 +308,499 @@ mod tests {
         assert!(loaded.metadata.imported_at <= Utc::now());
     }
 
+    #[test]
+    fn test_extension_updated_at_persistence() {
+        let (storage, _temp) = create_temp_storage();
+
+        let mut ext = ExtensionBuilder::new("Updated Test").build();
+        assert!(ext.metadata.updated_at.is_none());
+        assert_eq!(ext.last_updated(), ext.metadata.imported_at);
+
+        let updated_at = Utc::now();
+        ext.metadata.updated_at = Some(updated_at);
+        storage.save_extension(&ext).unwrap();
+
+        let loaded = storage.load_extension(&ext.id).unwrap();
+        assert_eq!(loaded.metadata.updated_at, Some(updated_at));
+        assert_eq!(loaded.last_updated(), updated_at);
+    }
+
+    #[test]
+    fn test_extension_without_updated_at_loads() {
+        let (storage, temp) = create_temp_storage();
+
+        // Extensions saved before updated_at existed have no such field
+        let json = r#"{
+            "id": "legacy",
+            "name": "Legacy",
+            "version": "1.0.0",
+            "description": null,
+            "mcp_servers": {},
+            "context_file_name": null,
+            "context_content": null,
+            "metadata": {
+                "imported_at": "2024-01-01T00:00:00Z",
+                "source_path": null,
+                "tags": []
+            }
+        }"#;
+        std::fs::write(temp.path().join("extensions").join("legacy.json"), json).unwrap();
+
+        let loaded = storage.load_extension("legacy").unwrap();
+        assert!(loaded.metadata.updated_at.is_none());
+        assert_eq!(loaded.last_updated(), loaded.metadata.imported_at);
+    }
+
+    #[test]
+    fn test_load_json5_profile() {
+        let (storage, temp) = create_temp_storage();
+
+        let profile = ProfileBuilder::new("Tooling Profile")
+            .with_description("Generated by a script")
+            .with_tags(vec!["generated"])
+            .build();
+        storage.save_profile(&profile).unwrap();
+        let from_json = storage.load_profile(&profile.id).unwrap();
+        storage.delete_profile(&profile.id).unwrap();
+
+        // Same profile, hand-written as JSON5 with comments and trailing commas
+        let json5 = format!(
+            r#"{{
+                // Emitted by external tooling
+                id: "{id}",
+                name: "Tooling Profile",
+                description: "Generated by a script",
+                extension_ids: [],
+                environment_variables: {{}},
+                working_directory: null,
+                metadata: {{
+                    created_at: "{created}",
+                    updated_at: "{updated}",
+                    tags: ["generated",],
+                    is_default: false,
+                    icon: null,
+                }},
+            }}"#,
+            id = profile.id,
+            created = profile.metadata.created_at.to_rfc3339(),
+            updated = profile.metadata.updated_at.to_rfc3339(),
+        );
+        std::fs::write(
+            temp.path()
+                .join("profiles")
+                .join(format!("{}.json5", profile.id)),
+            json5,
+        )
+        .unwrap();
+
+        let from_json5 = storage.load_profile(&profile.id).unwrap();
+        assert_eq!(
+            serde_json::to_value(&from_json5).unwrap(),
+            serde_json::to_value(&from_json).unwrap()
+        );
+
+        let listed = storage.list_profiles().unwrap();
+        assert_eq!(listed.len(), 1);
+        assert_eq!(listed[0].id, profile.id);
+    }
+
+    #[test]
+    fn test_sync_defaults_from_profile() {
+        let (storage, _temp) = create_temp_storage();
+
+        let mut stale = ExtensionBuilder::new("Stale Default").build();
+        stale.enabled_by_default = true;
+        storage.save_extension(&stale).unwrap();
+        let wanted = ExtensionBuilder::new("Wanted").build();
+        storage.save_extension(&wanted).unwrap();
+        let unrelated = ExtensionBuilder::new("Unrelated").build();
+        storage.save_extension(&unrelated).unwrap();
+
+        let profile = ProfileBuilder::new("Active")
+            .with_extensions(vec!["wanted"])
+            .build();
+
+        // Stale Default is switched off and Wanted switched on
+        assert_eq!(storage.sync_defaults_from_profile(&profile).unwrap(), 2);
+        assert!(
+            !storage
+                .load_extension(&stale.id)
+                .unwrap()
+                .enabled_by_default
+        );
+        assert!(
+            storage
+                .load_extension(&wanted.id)
+                .unwrap()
+                .enabled_by_default
+        );
+        assert!(
+            !storage
+                .load_extension(&unrelated.id)
+                .unwrap()
+                .enabled_by_default
+        );
+
+        // Running it again changes nothing
+        assert_eq!(storage.sync_defaults_from_profile(&profile).unwrap(), 0);
+    }
+
+    #[test]
+    fn test_capture_defaults_into_profile() {
+        use gemini_cli_manager::storage::ProfileFormat;
+
+        let (storage, temp) = create_temp_storage();
+
+        for (name, enabled) in [("Alpha", true), ("Beta", false), ("Gamma", true)] {
+            let mut ext = ExtensionBuilder::new(name).build();
+            ext.enabled_by_default = enabled;
+            storage.save_extension(&ext).unwrap();
+        }
+
+        let profile = ProfileBuilder::new("Snapshot")
+            .with_extensions(vec!["beta", "missing"])
+            .build();
+        storage
+            .save_profile_as(&profile, ProfileFormat::Json5)
+            .unwrap();
+
+        let saved = storage.capture_defaults_into_profile(&profile).unwrap();
+        assert_eq!(saved.extension_ids, vec!["alpha", "gamma"]);
+        assert_eq!(
+            storage.load_profile(&profile.id).unwrap().extension_ids,
+            vec!["alpha", "gamma"]
+        );
+        // The profile stays in the format it was written in
+        assert!(temp.path().join("profiles").join("snapshot.json5").exists());
+        assert!(!temp.path().join("profiles").join("snapshot.json").exists());
+    }
+
+    #[test]
+    fn test_orphaned_extensions() {
+        let (storage, _temp) = create_temp_storage();
+
+        for name in ["Shared", "Dev Only", "Unused", "Also Unused"] {
+            storage
+                .save_extension(&ExtensionBuilder::new(name).build())
+                .unwrap();
+        }
+        // Nothing is used before any profile exists
+        assert_eq!(storage.orphaned_extensions().unwrap().len(), 4);
+
+        let dev = ProfileBuilder::new("Dev")
+            .with_extensions(vec!["shared", "dev-only"])
+            .build();
+        let prod = ProfileBuilder::new("Prod")
+            .with_extensions(vec!["shared", "missing"])
+            .build();
+        storage.save_profile(&dev).unwrap();
+        storage.save_profile(&prod).unwrap();
+
+        let mut orphans: Vec<String> = storage
+            .orphaned_extensions()
+            .unwrap()
+            .into_iter()
+            .map(|e| e.id)
+            .collect();
+        orphans.sort();
+        assert_eq!(orphans, vec!["also-unused", "unused"]);
+
+        let shared = storage.load_extension("shared").unwrap();
+        assert!(!shared.is_orphan(&[dev, prod]));
+        assert!(shared.is_orphan(&[]));
+    }
+
+    #[test]
+    fn test_copy_into_new_data_dir() {
+        let (source, _source_dir) = create_temp_storage();
+        let ext = McpFixtures::multi_server_extension();
+        source.save_extension(&ext).unwrap();
+        let profile = ProfileBuilder::new("Team")
+            .with_extensions(vec![ext.id.as_str()])
+            .as_default()
+            .build();
+        source.save_profile(&profile).unwrap();
+
+        let target_dir = tempfile::TempDir::new().unwrap();
+        let new_dir = target_dir.path().join("state");
+        let target = Storage::with_data_dir(new_dir.clone());
+        assert_eq!(source.copy_into(&target).unwrap(), (1, 1));
+
+        // A fresh instance over the new directory sees everything
+        let reopened = Storage::with_data_dir(new_dir);
+        let extensions = reopened.list_extensions().unwrap();
+        assert_eq!(extensions.len(), 1);
+        assert_eq!(extensions[0].mcp_servers.len(), 3);
+        let copied = reopened.load_profile(&profile.id).unwrap();
+        assert_eq!(copied.extension_ids, vec![ext.id.clone()]);
+        // No profile comes across as the default
+        assert!(!copied.metadata.is_default);
+        assert!(reopened.get_default_profile().unwrap().is_none());
+
+        // Changes to the copy don't leak back into the original
+        reopened.delete_profile(&profile.id).unwrap();
+        assert!(source.load_profile(&profile.id).is_ok());
+
+        // Copying onto a populated directory is refused
+        assert!(source.copy_into(&source).is_err());
+    }
+
+    #[test]
+    fn test_apply_edited_profile() {
+        let (storage, temp) = create_temp_storage();
+
+        let profile = ProfileBuilder::new("Editable").build();
+        storage.save_profile(&profile).unwrap();
+        let stored = storage.profile_file(&profile.id);
+        assert!(stored.ends_with(format!("profiles/{}.json", profile.id)));
+
+        // A valid edit is stored
+        let edited = temp.path().join("edited.json");
+        let mut changed = profile.clone();
+        changed.description = Some("Edited by hand".to_string());
+        std::fs::write(&edited, serde_json::to_string_pretty(&changed).unwrap()).unwrap();
+
+        let applied = storage.apply_edited_profile(&profile.id, &edited).unwrap();
+        assert_eq!(applied.description.as_deref(), Some("Edited by hand"));
+        let loaded = storage.load_profile(&profile.id).unwrap();
+        assert_eq!(loaded.description.as_deref(), Some("Edited by hand"));
+
+        // Broken JSON is rejected and the stored profile is kept
+        std::fs::write(&edited, "{ \"id\": ").unwrap();
+        let err = storage
+            .apply_edited_profile(&profile.id, &edited)
+            .unwrap_err();
+        assert!(err.to_string().contains("invalid profile file"));
+        let loaded = storage.load_profile(&profile.id).unwrap();
+        assert_eq!(loaded.description.as_deref(), Some("Edited by hand"));
+
+        // So is an edit that changes the ID or clears the name
+        let mut renamed = changed.clone();
+        renamed.id = "someone-else".to_string();
+        std::fs::write(&edited, serde_json::to_string(&renamed).unwrap()).unwrap();
+        assert!(storage.apply_edited_profile(&profile.id, &edited).is_err());
+        assert!(storage.load_profile("someone-else").is_err());
+
+        let mut unnamed = changed.clone();
+        unnamed.name = "  ".to_string();
+        std::fs::write(&edited, serde_json::to_string(&unnamed).unwrap()).unwrap();
+        let err = storage
+            .apply_edited_profile(&profile.id, &edited)
+            .unwrap_err();
+        assert!(err.to_string().contains("name is required"));
+        assert_eq!(storage.load_profile(&profile.id).unwrap().name, "Editable");
+
+        // And one with an environment value a launch couldn't expand
+        let mut broken_env = changed;
+        broken_env
+            .environment_variables
+            .insert("API_URL".to_string(), "${HOST".to_string());
+        std::fs::write(&edited, serde_json::to_string(&broken_env).unwrap()).unwrap();
+        let err = storage
+            .apply_edited_profile(&profile.id, &edited)
+            .unwrap_err();
+        assert!(err.to_string().contains("invalid environment"));
+        assert!(
+            !storage
+                .load_profile(&profile.id)
+                .unwrap()
+                .environment_variables
+                .contains_key("API_URL")
+        );
+    }
+
+    #[test]
+    fn test_save_profile_as_json5() {
+        use gemini_cli_manager::storage::ProfileFormat;
+
+        let (storage, temp) = create_temp_storage();
+        let profiles_dir = temp.path().join("profiles");
+
+        let profile = ProfileBuilder::new("Format Test").build();
+        storage.save_profile(&profile).unwrap();
+        assert!(profiles_dir.join(format!("{}.json", profile.id)).exists());
+
+        storage
+            .save_profile_as(&profile, ProfileFormat::Json5)
+            .unwrap();
+        assert!(profiles_dir.join(format!("{}.json5", profile.id)).exists());
+        assert!(!profiles_dir.join(format!("{}.json", profile.id)).exists());
+
+        let loaded = storage.load_profile(&profile.id).unwrap();
+        assert_eq!(loaded.name, "Format Test");
+        assert_eq!(storage.list_profiles().unwrap().len(), 1);
+
+        storage.delete_profile(&profile.id).unwrap();
+        assert!(storage.list_profiles().unwrap().is_empty());
+    }
+
+    #[test]
+    fn test_saving_keeps_json5_profiles_in_json5() {
+        use gemini_cli_manager::storage::ProfileFormat;
+
+        let (storage, temp) = create_temp_storage();
+        let profiles_dir = temp.path().join("profiles");
+
+        let mut profile = ProfileBuilder::new("Hand Written").build();
+        storage
+            .save_profile_as(&profile, ProfileFormat::Json5)
+            .unwrap();
+        let json5 = profiles_dir.join(format!("{}.json5", profile.id));
+        let json = profiles_dir.join(format!("{}.json", profile.id));
+
+        profile.description = Some("Edited".to_string());
+        storage.save_profile(&profile).unwrap();
+        assert!(json5.exists());
+        assert!(!json.exists());
+
+        storage.set_default_profile(&profile.id).unwrap();
+        assert!(json5.exists());
+        assert!(!json.exists());
+
+        let loaded = storage.load_profile(&profile.id).unwrap();
+        assert_eq!(loaded.description.as_deref(), Some("Edited"));
+        assert!(loaded.metadata.is_default);
+    }
+
+    #[test]
+    fn test_import_profile_without_conflict() {
+        use gemini_cli_manager::storage::ImportOutcome;
+
+        let (storage, _temp) = create_temp_storage();
+        let profile = ProfileBuilder::new("Fresh").build();
+
+        let outcome = storage
+            .import_profile(profile.clone(), |_, _| {
+                panic!("no conflict expected for a new profile")
+            })
+            .unwrap();
+
+        assert_eq!(outcome, ImportOutcome::Imported(profile.id.clone()));
+        assert!(storage.load_profile(&profile.id).is_ok());
+    }
+
+    #[test]
+    fn test_import_profile_conflict_skip() {
+        use gemini_cli_manager::storage::{ConflictResolution, ImportOutcome};
+
+        let (storage, _temp) = create_temp_storage();
+        let existing = ProfileBuilder::new("Shared")
+            .with_description("original")
+            .build();
+        storage.save_profile(&existing).unwrap();
+
+        let imported = ProfileBuilder::new("Shared")
+            .with_description("imported")
+            .build();
+        let outcome = storage
+            .import_profile(imported, |_, _| ConflictResolution::Skip)
+            .unwrap();
+
+        assert_eq!(outcome, ImportOutcome::Skipped(existing.id.clone()));
+        let loaded = storage.load_profile(&existing.id).unwrap();
+        assert_eq!(loaded.description.as_deref(), Some("original"));
+        assert_eq!(storage.list_profiles().unwrap().len(), 1);
+    }
+
+    #[test]
+    fn test_import_profile_conflict_overwrite() {
+        use gemini_cli_manager::storage::{ConflictResolution, ImportOutcome};
+
+        let (storage, _temp) = create_temp_storage();
+        let existing = ProfileBuilder::new("Shared")
+            .with_description("original")
+            .build();
+        storage.save_profile(&existing).unwrap();
+
+        let imported = ProfileBuilder::new("Shared")
+            .with_description("imported")
+            .build();
+        let outcome = storage
+            .import_profile(imported, |_, _| ConflictResolution::Overwrite)
+            .unwrap();
+
+        assert_eq!(outcome, ImportOutcome::Overwritten(existing.id.clone()));
+        let loaded = storage.load_profile(&existing.id).unwrap();
+        assert_eq!(loaded.description.as_deref(), Some("imported"));
+        assert_eq!(storage.list_profiles().unwrap().len(), 1);
+    }
+
+    #[test]
+    fn test_import_profile_conflict_overwrite_keeps_default() {
+        use gemini_cli_manager::storage::ConflictResolution;
+
+        let (storage, _temp) = create_temp_storage();
+        let default = ProfileBuilder::new("Main").as_default().build();
+        let shared = ProfileBuilder::new("Shared").build();
+        storage.save_profile(&default).unwrap();
+        storage.save_profile(&shared).unwrap();
+
+        // An imported default doesn't become a second default
+        let imported = ProfileBuilder::new("Shared").as_default().build();
+        storage
+            .import_profile(imported, |_, _| ConflictResolution::Overwrite)
+            .unwrap();
+        assert!(
+            !storage
+                .load_profile(&shared.id)
+                .unwrap()
+                .metadata
+                .is_default
+        );
+
+        // Replacing the default keeps it the default
+        let imported = ProfileBuilder::new("Main").build();
+        storage
+            .import_profile(imported, |_, _| ConflictResolution::Overwrite)
+            .unwrap();
+        assert_eq!(
+            storage.get_default_profile().unwrap().map(|p| p.id),
+            Some(default.id)
+        );
+    }
+
+    #[test]
+    fn test_import_profile_conflict_rename_is_default() {
+        use gemini_cli_manager::storage::{ConflictResolution, ImportOutcome};
+
+        let (storage, _temp) = create_temp_storage();
+        let existing = ProfileBuilder::new("Shared").as_default().build();
+        storage.save_profile(&existing).unwrap();
+
+        // Rename twice to check that taken suffixes are skipped too
+        for expected_suffix in [2, 3] {
+            let imported = ProfileBuilder::new("Shared").as_default().build();
+            let outcome = storage
+                .import_profile(imported, |current, incoming| {
+                    assert_eq!(current.id, incoming.id);
+                    ConflictResolution::default()
+                })
+                .unwrap();
+
+            let new_id = format!("{}-{expected_suffix}", existing.id);
+            assert_eq!(
+                outcome,
+                ImportOutcome::Renamed {
+                    from: existing.id.clone(),
+                    to: new_id.clone(),
+                }
+            );
+
+            let renamed = storage.load_profile(&new_id).unwrap();
+            assert_eq!(renamed.name, format!("Shared ({expected_suffix})"));
+            assert!(!renamed.metadata.is_default);
+        }
+
+        assert_eq!(storage.list_profiles().unwrap().len(), 3);
+        assert!(
+            storage
+                .load_profile(&existing.id)
+                .unwrap()
+                .metadata
+                .is_default
+        );
+    }
+
     #[test]
     fn test_profile_metadata_persistence() {
         let (storage, _temp) = create_temp_storage();
@@ -311,4 +862,349 @@ mod tests {
             assert_eq!(profiles[0].id, "test");
         }
     }
+
+    #[test]
+    fn test_save_extension_rejects_control_characters() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let mut ext = McpFixtures::echo_extension();
+        let server = ext.mcp_servers.values_mut().next().unwrap();
+        server.args = Some(vec!["line one\nline two".to_string()]);
+
+        assert!(storage.save_extension(&ext).is_err());
+        assert!(storage.load_extension(&ext.id).is_err());
+    }
+
+    #[test]
+    fn test_find_duplicate_extension() {
+        let (storage, _temp_dir) = create_temp_storage();
+        let installed = ExtensionBuilder::new("Database Tools").build();
+        storage.save_extension(&installed).unwrap();
+
+        // Same name from another source under a different id
+        let mut other_source = ExtensionBuilder::new("database tools ").build();
+        other_source.id = "5f0c7a52-second-copy".to_string();
+        let duplicate = storage.find_duplicate_extension(&other_source).unwrap();
+        assert_eq!(duplicate.map(|e| e.id), Some(installed.id.clone()));
+
+        // Same id, renamed manifest
+        let mut renamed = installed.clone();
+        renamed.name = "DB Tools".to_string();
+        assert!(
+            storage
+                .find_duplicate_extension(&renamed)
+                .unwrap()
+                .is_some()
+        );
+
+        let unrelated = ExtensionBuilder::new("Web Tools").build();
+        assert!(
+            storage
+                .find_duplicate_extension(&unrelated)
+                .unwrap()
+                .is_none()
+        );
+    }
+
+    #[test]
+    fn test_data_dir_badge_only_for_custom_dirs() {
+        use gemini_cli_manager::storage::data_dir_badge;
+
+        let temp = tempfile::TempDir::new().unwrap();
+        let default_dir = temp.path().join("gemini-cli-manager");
+        let scratch = temp.path().join("gemini-test");
+        std::fs::create_dir_all(&default_dir).unwrap();
+        std::fs::create_dir_all(&scratch).unwrap();
+
+        // The default directory, however it is spelled, gets no badge
+        assert_eq!(data_dir_badge(&default_dir, &default_dir), None);
+        assert_eq!(
+            data_dir_badge(
+                &default_dir.join("..").join("gemini-cli-manager"),
+                &default_dir
+            ),
+            None
+        );
+
+        // Anything else shows its name
+        assert_eq!(
+            data_dir_badge(&scratch, &default_dir).as_deref(),
+            Some("gemini-test")
+        );
+        assert_eq!(
+            data_dir_badge(&temp.path().join("not-created-yet"), &default_dir).as_deref(),
+            Some("not-created-yet")
+        );
+    }
+
+    #[test]
+    fn test_extension_notes_live_beside_the_extension() {
+        let (storage, _temp) = create_temp_storage();
+        let ext = ExtensionBuilder::new("Web Tools").build();
+        storage.save_extension(&ext).unwrap();
+        assert_eq!(storage.load_extension_notes(&ext.id).unwrap(), None);
+
+        storage
+            .save_extension_notes(&ext.id, "Needs GITHUB_TOKEN set\n")
+            .unwrap();
+        assert!(storage.extension_notes_file(&ext.id).exists());
+
+        // An update replaces the extension but not the notes, and rescans
+        // don't mistake the notes for an extension
+        let updated = ExtensionBuilder::new("Web Tools")
+            .with_version("2.0.0")
+            .build();
+        storage.save_extension(&updated).unwrap();
+        assert_eq!(storage.list_extensions().unwrap().len(), 1);
+        assert_eq!(
+            storage.load_extension_notes(&ext.id).unwrap().as_deref(),
+            Some("Needs GITHUB_TOKEN set\n")
+        );
+
+        // Blank notes remove the file, as does deleting the extension
+        storage.save_extension_notes(&ext.id, "  \n").unwrap();
+        assert!(!storage.extension_notes_file(&ext.id).exists());
+        storage.save_extension_notes(&ext.id, "keep").unwrap();
+        storage.delete_extension(&ext.id).unwrap();
+        assert_eq!(storage.load_extension_notes(&ext.id).unwrap(), None);
+    }
+
+    #[test]
+    fn test_custom_layout_is_used_for_extensions_and_profiles() {
+        let temp = tempfile::TempDir::new().unwrap();
+        let shared = temp.path().join("shared-profiles");
+        let storage = Storage::with_data_dir(temp.path().join("data"))
+            .with_layout(Some(std::path::Path::new("exts")), Some(&shared));
+        storage.init().unwrap();
+        assert_eq!(storage.extensions_dir(), temp.path().join("data/exts"));
+        assert_eq!(storage.profiles_dir(), shared);
+
+        let ext = ExtensionBuilder::new("Web Tools").build();
+        storage.save_extension(&ext).unwrap();
+        let profile = ProfileBuilder::new("Work")
+            .with_extensions(vec![&ext.id])
+            .build();
+        storage.save_profile(&profile).unwrap();
+
+        assert!(
+            storage
+                .extensions_dir()
+                .join(format!("{}.json", ext.id))
+                .exists()
+        );
+        assert!(shared.join(format!("{}.json", profile.id)).exists());
+        assert!(!temp.path().join("data/extensions").exists());
+        assert!(!temp.path().join("data/profiles").exists());
+        assert_eq!(storage.list_extensions().unwrap().len(), 1);
+        assert_eq!(storage.list_profiles().unwrap().len(), 1);
+    }
+
+    #[cfg(unix)]
+    #[test]
+    fn test_init_rejects_read_only_directories() {
+        use std::os::unix::fs::PermissionsExt;
+
+        let temp = tempfile::TempDir::new().unwrap();
+        let locked = temp.path().join("locked");
+        std::fs::create_dir(&locked).unwrap();
+        std::fs::set_permissions(&locked, std::fs::Permissions::from_mode(0o555)).unwrap();
+        if std::fs::write(locked.join("probe"), "").is_ok() {
+            // Running as root, permissions aren't enforced
+            return;
+        }
+
+        let storage =
+            Storage::with_data_dir(temp.path().to_path_buf()).with_layout(None, Some(&locked));
+        let err = storage.init().unwrap_err().to_string();
+        assert!(err.contains("is not writable"), "{err}");
+    }
+
+    #[test]
+    fn test_concurrent_profile_saves_from_two_storages() {
+        let temp = tempfile::TempDir::new().unwrap();
+        let first = Storage::with_data_dir(temp.path().to_path_buf());
+        let second = first.clone();
+        first.init().unwrap();
+
+        // Each writer saves the same profile with its own, differently sized
+        // description, as two app instances on one data directory would
+        let writer = |storage: Storage, label: &'static str, size: usize| {
+            std::thread::spawn(move || {
+                for _ in 0..50 {
+                    let mut profile = ProfileBuilder::new("Shared").build();
+                    profile.id = "shared".to_string();
+                    profile.description = Some(label.repeat(size));
+                    storage.save_profile(&profile).unwrap();
+                    storage.set_default_profile("shared").unwrap();
+                }
+            })
+        };
+        let a = writer(first.clone(), "a", 10);
+        let b = writer(second, "b", 5000);
+        a.join().unwrap();
+        b.join().unwrap();
+
+        // The file holds one writer's profile in full, never a mix of both
+        let profile = first.load_profile("shared").unwrap();
+        let description = profile.description.unwrap();
+        assert!(
+            description == "a".repeat(10) || description == "b".repeat(5000),
+            "profile was corrupted"
+        );
+        assert!(profile.metadata.is_default);
+    }
+
+    #[test]
+    fn test_profile_export_and_import() {
+        use gemini_cli_manager::storage::{ConflictResolution, ImportOutcome};
+
+        let (source, _source_dir) = create_temp_storage();
+        let mut profile = ProfileBuilder::new("Work").as_default().build();
+        profile.metadata.created_at = Utc::now() - chrono::Duration::days(30);
+        profile
+            .environment_variables
+            .insert("API_URL".to_string(), "https://${HOST}/api".to_string());
+        source.save_profile(&profile).unwrap();
+
+        let shared = tempfile::TempDir::new().unwrap();
+        let file = shared.path().join("work.json");
+        source.export_profile(&profile.id, &file).unwrap();
+        let exported: serde_json::Value =
+            serde_json::from_str(&std::fs::read_to_string(&file).unwrap()).unwrap();
+        assert_eq!(exported["formatVersion"], 1);
+
+        // A teammate imports it as a regular, fresh profile
+        let (target, _target_dir) = create_temp_storage();
+        assert_eq!(
+            target
+                .import_profile_file(&file, |_, _| ConflictResolution::Rename)
+                .unwrap(),
+            ImportOutcome::Imported(profile.id.clone())
+        );
+        let imported = target.load_profile(&profile.id).unwrap();
+        assert_eq!(
+            imported.environment_variables,
+            profile.environment_variables
+        );
+        assert!(!imported.metadata.is_default);
+        assert!(imported.metadata.created_at > profile.metadata.created_at);
+
+        // Importing again keeps both
+        assert_eq!(
+            target
+                .import_profile_file(&file, |_, _| ConflictResolution::Rename)
+                .unwrap(),
+            ImportOutcome::Renamed {
+                from: profile.id.clone(),
+                to: format!("{}-2", profile.id),
+            }
+        );
+
+        // Invalid or newer exports are refused
+        let mut broken = exported.clone();
+        broken["profile"]["environment_variables"]["API_URL"] = "${HOST".into();
+        std::fs::write(&file, broken.to_string()).unwrap();
+        let err = target
+            .import_profile_file(&file, |_, _| ConflictResolution::Rename)
+            .unwrap_err()
+            .to_string();
+        assert!(err.contains("invalid environment"), "{err}");
+
+        let mut newer = exported.clone();
+        newer["formatVersion"] = 2.into();
+        std::fs::write(&file, newer.to_string()).unwrap();
+        assert!(
+            target
+                .import_profile_file(&file, |_, _| ConflictResolution::Rename)
+                .is_err()
+        );
+
+        // IDs that would escape the profiles directory or name a device are refused
+        for id in ["../../escaped", "nested/work", "con", "Work"] {
+            let mut unsafe_id = exported.clone();
+            unsafe_id["profile"]["id"] = id.into();
+            std::fs::write(&file, unsafe_id.to_string()).unwrap();
+            let err = target
+                .import_profile_file(&file, |_, _| ConflictResolution::Rename)
+                .unwrap_err()
+                .to_string();
+            assert!(err.contains("invalid profile ID"), "{id}: {err}");
+        }
+        assert!(
+            !target
+                .data_dir()
+                .parent()
+                .unwrap()
+                .join("escaped.json")
+                .exists()
+        );
+        assert_eq!(target.list_profiles().unwrap().len(), 2);
+
+        // Saving directly is held to the profiles directory too
+        let mut escaping = profile.clone();
+        escaping.id = "../escaped".to_string();
+        assert!(target.save_profile(&escaping).is_err());
+        assert!(!target.data_dir().join("escaped.json").exists());
+    }
+
+    #[test]
+    fn test_resolve_profile_merges_inherited_settings() {
+        let (storage, _temp) = create_temp_storage();
+
+        let mut base = ProfileBuilder::new("Base")
+            .with_extensions(vec!["git", "search"])
+            .build();
+        base.environment_variables
+            .insert("LOG_LEVEL".to_string(), "info".to_string());
+        base.environment_variables
+            .insert("REGION".to_string(), "eu".to_string());
+        base.working_directory = Some("/work".to_string());
+        storage.save_profile(&base).unwrap();
+
+        let mut team = ProfileBuilder::new("Team")
+            .with_extensions(vec!["jira"])
+            .build();
+        team.inherits = Some("base".to_string());
+        team.environment_variables
+            .insert("REGION".to_string(), "us".to_string());
+        storage.save_profile(&team).unwrap();
+
+        let mut mine = ProfileBuilder::new("Mine")
+            .with_extensions(vec!["search", "notes"])
+            .build();
+        mine.inherits = Some("team".to_string());
+        mine.environment_variables
+            .insert("LOG_LEVEL".to_string(), "debug".to_string());
+        storage.save_profile(&mine).unwrap();
+
+        let resolved = storage.resolve_profile("mine").unwrap();
+        assert_eq!(resolved.id, "mine");
+        assert_eq!(
+            resolved.extension_ids,
+            vec!["git", "jira", "search", "notes"]
+        );
+        assert_eq!(resolved.environment_variables["LOG_LEVEL"], "debug");
+        assert_eq!(resolved.environment_variables["REGION"], "us");
+        assert_eq!(resolved.working_directory.as_deref(), Some("/work"));
+
+        // Replacing an inherited variable anywhere along the chain is reported
+        let (_, overridden) = storage.resolve_with_overrides(mine).unwrap();
+        assert_eq!(overridden, vec!["LOG_LEVEL", "REGION"]);
+
+        // The stored profile keeps only its own settings
+        assert_eq!(
+            storage.load_profile("mine").unwrap().extension_ids,
+            vec!["search", "notes"]
+        );
+
+        // A loop is reported rather than followed forever
+        base.inherits = Some("mine".to_string());
+        storage.save_profile(&base).unwrap();
+        let err = storage.resolve_profile("mine").unwrap_err().to_string();
+        assert!(err.contains("mine -> team -> base -> mine"), "{err}");
+
+        // So is a parent that doesn't exist
+        base.inherits = Some("gone".to_string());
+        storage.save_profile(&base).unwrap();
+        assert!(storage.resolve_profile("team").is_err());
+    }
 }
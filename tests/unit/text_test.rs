@@ -0,0 +1,84 @@
+#[cfg(test)]
+mod tests {
+    use gemini_cli_manager::utils::text::{
+        decode_text, display_width, pad_to_width, truncate_to_width,
+    };
+
+    const FAMILY: &str = "👨\u{200d}👩\u{200d}👧";
+
+    #[test]
+    fn test_display_width_plain_and_wide() {
+        assert_eq!(display_width("hello"), 5);
+        assert_eq!(display_width("日本"), 4);
+        assert_eq!(display_width(""), 0);
+    }
+
+    #[test]
+    fn test_display_width_combining_marks() {
+        // "é" written as "e" + combining acute accent is one column
+        assert_eq!(display_width("cafe\u{301}"), 4);
+        assert_eq!(display_width("n\u{303}o\u{308}"), 2);
+    }
+
+    #[test]
+    fn test_display_width_zwj_sequences() {
+        // A ZWJ family renders as a single double-width glyph
+        assert_eq!(display_width(FAMILY), 2);
+        assert_eq!(display_width(&format!("{FAMILY} Tools")), 8);
+        // A bare zero-width space takes no room
+        assert_eq!(display_width("a\u{200b}b"), 2);
+    }
+
+    #[test]
+    fn test_truncate_leaves_short_text_alone() {
+        assert_eq!(truncate_to_width("short", 10), "short");
+        assert_eq!(truncate_to_width("cafe\u{301}", 4), "cafe\u{301}");
+        assert_eq!(truncate_to_width("anything", 0), "");
+    }
+
+    #[test]
+    fn test_truncate_keeps_combining_marks_attached() {
+        let truncated = truncate_to_width("e\u{301}e\u{301}e\u{301}e\u{301}", 3);
+        assert_eq!(truncated, "e\u{301}e\u{301}…");
+        assert_eq!(display_width(&truncated), 3);
+    }
+
+    #[test]
+    fn test_truncate_never_splits_zwj_sequences() {
+        let text = format!("ab{FAMILY}cd");
+
+        // Not enough room for the whole sequence: it's dropped rather than split
+        let truncated = truncate_to_width(&text, 4);
+        assert_eq!(truncated, "ab…");
+        assert!(!truncated.contains('\u{200d}'));
+
+        let truncated = truncate_to_width(&text, 5);
+        assert_eq!(truncated, format!("ab{FAMILY}…"));
+        assert_eq!(display_width(&truncated), 5);
+    }
+
+    #[test]
+    fn test_pad_to_width_counts_graphemes() {
+        assert_eq!(pad_to_width("cafe\u{301}", 6), "cafe\u{301}  ");
+        assert_eq!(display_width(&pad_to_width(FAMILY, 6)), 6);
+        assert_eq!(pad_to_width("too long", 3), "too long");
+    }
+
+    #[test]
+    fn test_decode_text_replaces_invalid_utf8() {
+        assert_eq!(
+            decode_text("# Notes".as_bytes()).as_deref(),
+            Some("# Notes")
+        );
+
+        // A Latin-1 "é" is not valid UTF-8
+        let decoded = decode_text(b"caf\xe9 menu").unwrap();
+        assert_eq!(decoded, "caf\u{fffd} menu");
+        assert_eq!(display_width(&decoded), 9);
+    }
+
+    #[test]
+    fn test_decode_text_rejects_binary() {
+        assert_eq!(decode_text(b"\x89PNG\r\n\x1a\n\0\0\0\rIHDR"), None);
+    }
+}
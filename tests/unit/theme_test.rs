@@ -224,4 +224,98 @@ mod style_verification_tests {
         // Should detect black on dark background
         assert!(!issues.is_empty(), "Should detect color issues");
     }
+
+    #[test]
+    fn test_load_custom_themes() {
+        const ROLES: [&str; 20] = [
+            "background",
+            "surface",
+            "overlay",
+            "text_primary",
+            "text_secondary",
+            "text_muted",
+            "text_disabled",
+            "primary",
+            "secondary",
+            "accent",
+            "highlight",
+            "success",
+            "warning",
+            "error",
+            "info",
+            "border",
+            "border_focused",
+            "selection",
+            "selection_bar",
+            "cursor",
+        ];
+
+        let dir = tempfile::TempDir::new().unwrap();
+        let yaml_colors: Vec<String> = ROLES
+            .iter()
+            .map(|role| format!("  {role}: \"#102030\""))
+            .collect();
+        std::fs::write(
+            dir.path().join("Ocean-Sample.yaml"),
+            format!(
+                "name: Ocean\nvariant: Dark\ncolors:\n{}\n",
+                yaml_colors.join("\n")
+            ),
+        )
+        .unwrap();
+        let toml_colors: Vec<String> = ROLES
+            .iter()
+            .map(|role| format!("{role} = \"#f0e0d0\""))
+            .collect();
+        std::fs::write(
+            dir.path().join("paper-sample.toml"),
+            format!(
+                "variant = \"Light\"\n[colors]\n{}\n",
+                toml_colors.join("\n")
+            ),
+        )
+        .unwrap();
+
+        // Leaves out every role but one
+        std::fs::write(
+            dir.path().join("broken-sample.yaml"),
+            "colors:\n  background: \"#000000\"\n",
+        )
+        .unwrap();
+        // Would shadow a built-in theme
+        std::fs::write(
+            dir.path().join("mocha.yaml"),
+            format!("colors:\n{}\n", yaml_colors.join("\n")),
+        )
+        .unwrap();
+
+        let err = theme::load_custom_themes(dir.path())
+            .unwrap_err()
+            .to_string();
+        assert!(err.contains("broken-sample.yaml"), "{err}");
+        assert!(err.contains("mocha.yaml"), "{err}");
+
+        let themes = theme::custom_themes();
+        let ocean = themes.iter().find(|t| t.name == "ocean-sample").unwrap();
+        assert_eq!(ocean.display_name, "Ocean");
+        assert_eq!(ocean.variant, "Dark");
+        let paper = themes.iter().find(|t| t.name == "paper-sample").unwrap();
+        assert_eq!(paper.display_name, "paper-sample");
+        assert!(!themes.iter().any(|t| t.name == "broken-sample"));
+
+        // Custom themes are selected by name like the built-in ones
+        let ocean = theme::theme_by_name("Ocean-Sample").unwrap();
+        assert_eq!(ocean.primary(), Color::Rgb(0x10, 0x20, 0x30));
+        assert_eq!(
+            theme::theme_by_name("paper-sample").unwrap().cursor(),
+            Color::Rgb(0xf0, 0xe0, 0xd0)
+        );
+        assert_ne!(
+            theme::theme_by_name("mocha").unwrap().background(),
+            Color::Rgb(0x10, 0x20, 0x30)
+        );
+
+        // A missing directory simply has no themes
+        assert!(theme::load_custom_themes(&dir.path().join("missing")).is_ok());
+    }
 }
@@ -261,6 +261,43 @@ mod tests {
         }
     }
 
+    #[test]
+    fn test_profile_id_from_edge_case_names() {
+        use gemini_cli_manager::models::profile::profile_id_from_name;
+
+        let cases: &[(&str, Result<&str, &str>)] = &[
+            ("Work", Ok("work")),
+            ("  Side   Project  ", Ok("side-project")),
+            ("my_profile.v2", Ok("my-profile-v2")),
+            ("--a--b--", Ok("a-b")),
+            ("O'Brien's (dev)", Ok("obriens-dev")),
+            ("Café Team", Ok("café-team")),
+            ("Default Tools", Ok("default-tools")),
+            ("", Err("the name needs at least one letter or digit")),
+            ("   ", Err("the name needs at least one letter or digit")),
+            ("!!!", Err("the name needs at least one letter or digit")),
+            ("- _ .", Err("the name needs at least one letter or digit")),
+            ("default", Err("'default' is reserved, choose another name")),
+            (
+                " DEFAULT ",
+                Err("'default' is reserved, choose another name"),
+            ),
+            ("System", Err("'system' is reserved, choose another name")),
+            ("nul", Err("'nul' is reserved, choose another name")),
+            ("COM1", Err("'com1' is reserved, choose another name")),
+            ("lpt9", Err("'lpt9' is reserved, choose another name")),
+            ("com10", Ok("com10")),
+        ];
+
+        for (name, expected) in cases {
+            assert_eq!(
+                profile_id_from_name(name).as_deref(),
+                expected.map_err(str::to_string).as_deref(),
+                "name {name:?}"
+            );
+        }
+    }
+
     #[test]
     fn test_special_character_handling() {
         // Test that special characters are handled properly
@@ -283,6 +320,82 @@ mod tests {
         }
     }
 
+    fn command_server(command: &str, args: &[&str]) -> McpServerConfig {
+        McpServerConfig {
+            command: Some(command.to_string()),
+            args: Some(args.iter().map(|a| a.to_string()).collect()),
+            cwd: None,
+            env: None,
+            timeout: None,
+            trust: None,
+            url: None,
+        }
+    }
+
+    #[test]
+    fn test_mcp_args_with_newline_rejected() {
+        let mut ext = ExtensionBuilder::new("injected").build();
+        ext.mcp_servers.insert(
+            "server".to_string(),
+            command_server("node", &["server.js", "--flag\n\"evil\": true"]),
+        );
+
+        let err = ext.validate().unwrap_err();
+        assert!(err.contains("server"));
+        assert!(err.contains("argument 2"));
+        assert!(validate_extension_json(&ext).is_err());
+    }
+
+    #[test]
+    fn test_mcp_command_with_null_byte_rejected() {
+        let server = command_server("node\0--inspect", &[]);
+        assert!(server.validate().is_err());
+
+        let server = command_server("node\r", &[]);
+        assert!(server.validate().is_err());
+    }
+
+    #[test]
+    fn test_mcp_shell_safe_characters_allowed() {
+        let server = command_server(
+            "/usr/local/bin/my-server",
+            &[
+                "--config=$HOME/.config/app.json",
+                "--name",
+                "with spaces & 'quotes'",
+                "tab\tseparated",
+                "ünïcødé",
+            ],
+        );
+        assert!(server.validate().is_ok());
+    }
+
+    #[test]
+    fn test_mcp_env_and_cwd_validated() {
+        let mut server = command_server("node", &["server.js"]);
+        server.cwd = Some("./servers".to_string());
+        server.env = Some(HashMap::from([
+            ("API_KEY".to_string(), "$MCP_API_KEY".to_string()),
+            ("CONFIG".to_string(), "${HOME}/.config".to_string()),
+        ]));
+        assert!(server.validate().is_ok());
+
+        let mut bad_name = server.clone();
+        bad_name.env = Some(HashMap::from([("API-KEY".to_string(), "x".to_string())]));
+        assert!(bad_name.validate().unwrap_err().contains("API-KEY"));
+
+        let mut bad_value = server.clone();
+        bad_value.env = Some(HashMap::from([(
+            "CONFIG".to_string(),
+            "${HOME".to_string(),
+        )]));
+        assert!(bad_value.validate().unwrap_err().contains("CONFIG"));
+
+        let mut bad_cwd = server;
+        bad_cwd.cwd = Some("./servers\n".to_string());
+        assert!(bad_cwd.validate().unwrap_err().contains("cwd"));
+    }
+
     #[test]
     fn test_empty_mcp_servers_allowed() {
         // Extensions without MCP servers are valid (context-only)
@@ -289,6 +289,127 @@ mod tests {
         // Error should be cleared
     }
 
+    #[tokio::test]
+    async fn test_search_state_is_per_view_across_tabs() {
+        use crossterm::event::{KeyCode, KeyEvent, KeyModifiers};
+
+        let storage = create_test_storage();
+        for name in ["Alpha Tools", "Beta Tools"] {
+            storage
+                .save_extension(&ExtensionBuilder::new(name).build())
+                .unwrap();
+        }
+        for name in ["Alpha Work", "Beta Work"] {
+            storage
+                .save_profile(&ProfileBuilder::new(name).build())
+                .unwrap();
+        }
+
+        let mut vm = ViewManager::with_storage(storage);
+        let (tx, _rx) = mpsc::unbounded_channel();
+        vm.register_action_handler(tx).unwrap();
+
+        let key = |code| {
+            gemini_cli_manager::tui::Event::Key(KeyEvent {
+                code,
+                modifiers: KeyModifiers::empty(),
+                kind: crossterm::event::KeyEventKind::Press,
+                state: crossterm::event::KeyEventState::empty(),
+            })
+        };
+        let mut terminal = Terminal::new(ratatui::backend::TestBackend::new(80, 30)).unwrap();
+        let mut render = |vm: &mut ViewManager| {
+            terminal
+                .draw(|frame| {
+                    let area = frame.area();
+                    vm.draw(frame, area).unwrap();
+                })
+                .unwrap();
+            buffer_to_string(terminal.backend().buffer())
+        };
+
+        // Filter the extensions
+        vm.handle_events(Some(key(KeyCode::Char('/')))).unwrap();
+        for c in "beta".chars() {
+            vm.handle_events(Some(key(KeyCode::Char(c)))).unwrap();
+        }
+        let screen = render(&mut vm);
+        assert!(screen.contains("Extensions (1/2)"));
+        assert!(!screen.contains("Alpha Tools"));
+
+        // The profile list has its own, empty, search
+        vm.update(Action::NavigateToProfiles).unwrap();
+        let screen = render(&mut vm);
+        assert!(screen.contains("Alpha Work"));
+        assert!(screen.contains("Beta Work"));
+        assert!(!screen.contains("Search (Esc to close)"));
+
+        // Coming back, the extension filter is still in place
+        vm.update(Action::NavigateToExtensions).unwrap();
+        let screen = render(&mut vm);
+        assert!(screen.contains("Search (Esc to close)"));
+        assert!(screen.contains("Extensions (1/2)"));
+        assert!(screen.contains("Beta Tools"));
+        assert!(!screen.contains("Alpha Tools"));
+    }
+
+    #[tokio::test]
+    async fn test_extension_cursor_survives_detail_and_delete() {
+        use crossterm::event::{KeyCode, KeyEvent, KeyModifiers};
+
+        let storage = create_test_storage();
+        for name in ["First", "Second", "Third", "Fourth"] {
+            storage
+                .save_extension(&ExtensionBuilder::new(name).build())
+                .unwrap();
+        }
+
+        let mut vm = ViewManager::with_storage(storage);
+        let (tx, mut rx) = mpsc::unbounded_channel();
+        vm.register_action_handler(tx).unwrap();
+
+        let key = |code| {
+            gemini_cli_manager::tui::Event::Key(KeyEvent {
+                code,
+                modifiers: KeyModifiers::empty(),
+                kind: crossterm::event::KeyEventKind::Press,
+                state: crossterm::event::KeyEventState::empty(),
+            })
+        };
+        // Enter reports which extension the cursor is on
+        let highlighted = |vm: &mut ViewManager| match vm.handle_events(Some(key(KeyCode::Enter))) {
+            Ok(Some(Action::ViewExtensionDetails(id))) => id,
+            other => panic!("expected a highlighted extension, got {other:?}"),
+        };
+
+        let mut order = Vec::new();
+        for _ in 0..4 {
+            order.push(highlighted(&mut vm));
+            vm.handle_events(Some(key(KeyCode::Down))).unwrap();
+        }
+        // Wrapped around to the top; move to the second entry
+        vm.handle_events(Some(key(KeyCode::Down))).unwrap();
+        assert_eq!(highlighted(&mut vm), order[1]);
+
+        // Into the detail view and back again
+        vm.update(Action::ViewExtensionDetails(order[1].clone()))
+            .unwrap();
+        assert_eq!(vm.current_view(), ViewType::ExtensionDetail);
+        vm.update(Action::NavigateBack).unwrap();
+        assert_eq!(vm.current_view(), ViewType::ExtensionList);
+        assert_eq!(highlighted(&mut vm), order[1]);
+
+        // Deleting it leaves the cursor on the extension that followed
+        vm.update(Action::DeleteExtension(order[1].clone()))
+            .unwrap();
+        vm.update(Action::ConfirmDelete).unwrap();
+        while let Ok(action) = rx.try_recv() {
+            vm.update(action).unwrap();
+        }
+        assert_eq!(vm.current_view(), ViewType::ExtensionList);
+        assert_eq!(highlighted(&mut vm), order[2]);
+    }
+
     #[tokio::test]
     async fn test_draw_method() {
         let mut vm = ViewManager::with_storage(create_test_storage());
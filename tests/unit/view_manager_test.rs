@@ -137,6 +137,26 @@ mod tests {
         assert_eq!(vm.current_view(), ViewType::ProfileList);
     }
 
+    #[tokio::test]
+    async fn test_profile_config_preview_navigation() {
+        let mut vm = create_test_view_manager().await;
+        let (tx, _rx) = mpsc::unbounded_channel();
+        vm.register_action_handler(tx).unwrap();
+
+        vm.update(Action::NavigateToProfiles).unwrap();
+        vm.update(Action::ViewProfileDetails("test-profile".to_string()))
+            .unwrap();
+
+        // Open the generated config
+        vm.update(Action::PreviewConfig("test-profile".to_string()))
+            .unwrap();
+        assert_eq!(vm.current_view(), ViewType::ProfileConfig);
+
+        // Back returns to the profile it was opened from
+        vm.update(Action::NavigateBack).unwrap();
+        assert_eq!(vm.current_view(), ViewType::ProfileDetail);
+    }
+
     #[tokio::test]
     async fn test_edit_extension_navigation() {
         let mut vm = create_test_view_manager().await;
@@ -255,6 +275,106 @@ mod tests {
         assert!(result.is_ok());
     }
 
+    #[tokio::test]
+    async fn test_empty_launch_confirmation_flow() {
+        let mut vm = create_test_view_manager().await;
+        let (tx, _rx) = mpsc::unbounded_channel();
+        vm.register_action_handler(tx).unwrap();
+
+        vm.update(Action::NavigateToProfiles).unwrap();
+
+        // Ask for confirmation
+        vm.update(Action::ConfirmEmptyLaunch(
+            "test-profile".to_string(),
+            vec![],
+        ))
+        .unwrap();
+        assert_eq!(vm.current_view(), ViewType::ConfirmDelete);
+
+        // Confirming with 'y' produces the confirmed launch
+        let key = gemini_cli_manager::tui::Event::Key(crossterm::event::KeyEvent {
+            code: crossterm::event::KeyCode::Char('y'),
+            modifiers: crossterm::event::KeyModifiers::NONE,
+            kind: crossterm::event::KeyEventKind::Press,
+            state: crossterm::event::KeyEventState::NONE,
+        });
+        let action = vm.handle_events(Some(key)).unwrap();
+        assert_eq!(
+            action,
+            Some(Action::LaunchConfirmed("test-profile".to_string(), vec![]))
+        );
+
+        // Cancelling closes the dialog
+        vm.update(Action::CancelLaunch).unwrap();
+        assert_eq!(vm.current_view(), ViewType::ProfileList);
+    }
+
+    #[tokio::test]
+    async fn test_capture_defaults_confirmation_flow() {
+        let mut vm = create_test_view_manager().await;
+        let (tx, _rx) = mpsc::unbounded_channel();
+        vm.register_action_handler(tx).unwrap();
+
+        vm.update(Action::NavigateToProfiles).unwrap();
+
+        vm.update(Action::CaptureDefaults("test-profile".to_string()))
+            .unwrap();
+        assert_eq!(vm.current_view(), ViewType::ConfirmDelete);
+
+        let key = gemini_cli_manager::tui::Event::Key(crossterm::event::KeyEvent {
+            code: crossterm::event::KeyCode::Char('y'),
+            modifiers: crossterm::event::KeyModifiers::NONE,
+            kind: crossterm::event::KeyEventKind::Press,
+            state: crossterm::event::KeyEventState::NONE,
+        });
+        let action = vm.handle_events(Some(key)).unwrap();
+        assert_eq!(
+            action,
+            Some(Action::CaptureDefaultsConfirmed("test-profile".to_string()))
+        );
+
+        // Confirming closes the dialog and the profile list does the capture
+        let action = vm
+            .update(Action::CaptureDefaultsConfirmed("test-profile".to_string()))
+            .unwrap();
+        assert!(matches!(action, Some(Action::Success(_))));
+        assert_eq!(vm.current_view(), ViewType::ProfileList);
+    }
+
+    #[tokio::test]
+    async fn test_quit_confirmation_flow() {
+        let mut vm = create_test_view_manager().await;
+        let (tx, _rx) = mpsc::unbounded_channel();
+        vm.register_action_handler(tx).unwrap();
+
+        vm.update(Action::NavigateToProfiles).unwrap();
+
+        vm.update(Action::ConfirmQuit).unwrap();
+        assert_eq!(vm.current_view(), ViewType::ConfirmDelete);
+
+        let key = |code| {
+            gemini_cli_manager::tui::Event::Key(crossterm::event::KeyEvent {
+                code,
+                modifiers: crossterm::event::KeyModifiers::NONE,
+                kind: crossterm::event::KeyEventKind::Press,
+                state: crossterm::event::KeyEventState::NONE,
+            })
+        };
+        let action = vm
+            .handle_events(Some(key(crossterm::event::KeyCode::Char('y'))))
+            .unwrap();
+        assert_eq!(action, Some(Action::QuitConfirmed));
+
+        // Declining returns to where the user was
+        vm.update(Action::ConfirmQuit).unwrap();
+        let action = vm
+            .handle_events(Some(key(crossterm::event::KeyCode::Char('n'))))
+            .unwrap();
+        assert_eq!(action, Some(Action::CancelQuit));
+        vm.update(Action::CancelQuit).unwrap();
+        assert_eq!(vm.current_view(), ViewType::ProfileList);
+    }
+
     // TODO: Add save action tests when SaveExtension and SaveProfile actions are implemented
     // #[tokio::test]
     // async fn test_save_extension_navigation() {
@@ -0,0 +1,72 @@
+#[cfg(test)]
+mod tests {
+    use gemini_cli_manager::action::Action;
+    use gemini_cli_manager::watcher::{ChangeSource, DirectorySource, ReloadWatcher};
+    use std::cell::Cell;
+    use std::rc::Rc;
+    use std::time::{Duration, Instant};
+    use tempfile::TempDir;
+
+    const DEBOUNCE: Duration = Duration::from_millis(500);
+
+    /// Stands in for the filesystem: bump the counter to simulate a file event
+    #[derive(Clone, Default)]
+    struct FakeSource(Rc<Cell<u64>>);
+
+    impl FakeSource {
+        fn touch(&self) {
+            self.0.set(self.0.get() + 1);
+        }
+    }
+
+    impl ChangeSource for FakeSource {
+        fn fingerprint(&self) -> u64 {
+            self.0.get()
+        }
+    }
+
+    #[test]
+    fn test_file_event_produces_debounced_reload() {
+        let source = FakeSource::default();
+        let mut watcher = ReloadWatcher::new(source.clone(), Action::RefreshExtensions, DEBOUNCE);
+        let start = Instant::now();
+
+        // Nothing changed yet
+        assert_eq!(watcher.poll(start), None);
+
+        source.touch();
+        assert_eq!(watcher.poll(start), None);
+        // A second write inside the window restarts it
+        source.touch();
+        assert_eq!(watcher.poll(start + Duration::from_millis(300)), None);
+        assert_eq!(watcher.poll(start + Duration::from_millis(700)), None);
+
+        assert_eq!(
+            watcher.poll(start + Duration::from_millis(800)),
+            Some(Action::RefreshExtensions)
+        );
+        // Reported once
+        assert_eq!(watcher.poll(start + Duration::from_secs(5)), None);
+    }
+
+    #[test]
+    fn test_directory_source_sees_added_and_changed_files() {
+        let dir = TempDir::new().unwrap();
+        let source = DirectorySource::new(dir.path());
+        let empty = source.fingerprint();
+        assert_eq!(source.fingerprint(), empty);
+
+        std::fs::write(dir.path().join("web-tools.json"), "{}").unwrap();
+        let added = source.fingerprint();
+        assert_ne!(added, empty);
+
+        std::fs::write(dir.path().join("web-tools.json"), "{\"name\": \"Web\"}").unwrap();
+        assert_ne!(source.fingerprint(), added);
+
+        // A missing directory is just empty
+        assert_eq!(
+            DirectorySource::new(dir.path().join("gone")).fingerprint(),
+            empty
+        );
+    }
+}
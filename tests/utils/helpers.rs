@@ -64,8 +64,13 @@ pub fn create_test_extension(name: &str) -> Extension {
         mcp_servers: HashMap::new(),
         context_file_name: None,
         context_content: None,
+        enabled_by_default: false,
+        min_gemini_version: None,
+        platforms: Vec::new(),
+        icon: None,
         metadata: ExtensionMetadata {
             imported_at: Utc::now(),
+            updated_at: None,
             source_path: None,
             tags: vec!["test".to_string()],
         },
@@ -78,6 +83,7 @@ pub fn create_test_profile(name: &str) -> Profile {
         id: name.to_lowercase().replace(' ', "-"),
         name: name.to_string(),
         description: Some(format!("Test profile: {name}")),
+        inherits: None,
         extension_ids: vec![],
         environment_variables: HashMap::new(),
         working_directory: None,
@@ -158,8 +164,13 @@ impl ExtensionBuilder {
             mcp_servers: self.mcp_servers,
             context_file_name: None,
             context_content: None,
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: None,
                 tags: self.tags,
             },
@@ -234,6 +245,7 @@ impl ProfileBuilder {
             id,
             name: self.name,
             description: self.description,
+            inherits: None,
             extension_ids: self.extension_ids,
             environment_variables: HashMap::new(),
             working_directory: None,
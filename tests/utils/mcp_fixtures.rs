@@ -90,8 +90,13 @@ impl McpFixtures {
             },
             context_file_name: Some("GEMINI.md".to_string()),
             context_content: Some(Self::echo_context_content()),
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: Some("/test/extensions/echo-test".to_string()),
                 tags: vec!["test".to_string(), "echo".to_string()],
             },
@@ -114,8 +119,13 @@ impl McpFixtures {
             },
             context_file_name: Some("MULTI_SERVER.md".to_string()),
             context_content: Some(Self::multi_server_context()),
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: None,
                 tags: vec!["test".to_string(), "multi-server".to_string()],
             },
@@ -132,8 +142,13 @@ impl McpFixtures {
             mcp_servers: HashMap::new(),
             context_file_name: Some("INSTRUCTIONS.md".to_string()),
             context_content: Some(Self::context_only_content()),
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: None,
                 tags: vec!["test".to_string(), "context".to_string()],
             },
@@ -176,8 +191,13 @@ impl McpFixtures {
             },
             context_file_name: Some("ADVANCED.md".to_string()),
             context_content: Some(Self::advanced_context_content()),
+            enabled_by_default: false,
+            min_gemini_version: None,
+            platforms: Vec::new(),
+            icon: None,
             metadata: ExtensionMetadata {
                 imported_at: Utc::now(),
+                updated_at: None,
                 source_path: Some("/opt/extensions/full-featured".to_string()),
                 tags: vec![
                     "test".to_string(),
@@ -345,6 +365,8 @@ pub fn validate_extension_json(extension: &Extension) -> Result<(), String> {
         return Err("Extension version is required".to_string());
     }
 
+    extension.validate()?;
+
     // Validate MCP servers
     for (name, server) in &extension.mcp_servers {
         // Must have command